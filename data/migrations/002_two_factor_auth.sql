@@ -0,0 +1,52 @@
+-- Two-Factor Authentication (TOTP, RFC 6238)
+-- Adds per-user TOTP secrets, hashed recovery codes and short-lived login challenges
+
+-- ============================================================================
+-- USERS - TOTP enrollment state
+-- ============================================================================
+-- totp_secret is set when enrollment starts; totp_enabled_at is set once the
+-- user confirms a first code. totp_last_step prevents replaying a used code.
+ALTER TABLE users ADD COLUMN totp_secret VARCHAR(64);
+ALTER TABLE users ADD COLUMN totp_enabled_at TIMESTAMP;
+ALTER TABLE users ADD COLUMN totp_last_step BIGINT;
+
+-- ============================================================================
+-- USER RECOVERY CODES TABLE - Single-use backup codes (SHA256 hashed)
+-- ============================================================================
+CREATE TABLE user_recovery_codes (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    code_hash VARCHAR(255) NOT NULL,
+    used_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT NOW()
+);
+
+CREATE INDEX idx_recovery_codes_user_id ON user_recovery_codes(user_id);
+
+-- ============================================================================
+-- TWO FACTOR CHALLENGES TABLE - Pending logins awaiting a second factor
+-- ============================================================================
+CREATE TABLE two_factor_challenges (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    token_hash VARCHAR(255) NOT NULL,
+    attempts INTEGER DEFAULT 0,
+    expires_at TIMESTAMP NOT NULL,
+    created_at TIMESTAMP DEFAULT NOW(),
+    user_agent TEXT,
+    ip_address INET
+);
+
+CREATE INDEX idx_two_factor_challenges_token_hash ON two_factor_challenges(token_hash);
+CREATE INDEX idx_two_factor_challenges_expires_at ON two_factor_challenges(expires_at);
+
+-- Expired challenges are removed together with expired sessions
+CREATE OR REPLACE FUNCTION cleanup_expired_sessions()
+RETURNS void AS $$
+BEGIN
+    DELETE FROM user_sessions WHERE expires_at < NOW();
+    DELETE FROM two_factor_challenges WHERE expires_at < NOW();
+END;
+$$ language 'plpgsql';
+
+GRANT ALL PRIVILEGES ON ALL TABLES IN SCHEMA public TO clarticle_user;
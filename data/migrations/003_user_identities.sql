@@ -0,0 +1,20 @@
+-- External Identities (OpenID Connect single sign-on)
+-- Links users to accounts at an external identity provider
+
+-- ============================================================================
+-- USER IDENTITIES TABLE - Provider subject to local user mapping
+-- ============================================================================
+CREATE TABLE user_identities (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    provider VARCHAR(100) NOT NULL,
+    subject VARCHAR(255) NOT NULL,
+    email VARCHAR(255),
+    created_at TIMESTAMP DEFAULT NOW(),
+    last_login TIMESTAMP,
+    UNIQUE (provider, subject)
+);
+
+CREATE INDEX idx_user_identities_user_id ON user_identities(user_id);
+
+GRANT ALL PRIVILEGES ON ALL TABLES IN SCHEMA public TO clarticle_user;
@@ -0,0 +1,19 @@
+-- Account Self-Service
+-- Pending email address changes awaiting confirmation from the new address
+
+-- ============================================================================
+-- EMAIL CHANGE REQUESTS TABLE - Single-use confirmation tokens (SHA256 hashed)
+-- ============================================================================
+CREATE TABLE email_change_requests (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    new_email VARCHAR(255) NOT NULL,
+    token_hash VARCHAR(255) NOT NULL,
+    expires_at TIMESTAMP NOT NULL,
+    created_at TIMESTAMP DEFAULT NOW()
+);
+
+CREATE INDEX idx_email_change_requests_user_id ON email_change_requests(user_id);
+CREATE INDEX idx_email_change_requests_token_hash ON email_change_requests(token_hash);
+
+GRANT ALL PRIVILEGES ON ALL TABLES IN SCHEMA public TO clarticle_user;
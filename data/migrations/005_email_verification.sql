@@ -0,0 +1,7 @@
+-- Email Verification
+-- Records when a user proved ownership of their email address
+
+ALTER TABLE users ADD COLUMN email_verified_at TIMESTAMP;
+
+-- Accounts created before verification existed are treated as verified
+UPDATE users SET email_verified_at = created_at WHERE email_verified_at IS NULL;
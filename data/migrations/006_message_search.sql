@@ -0,0 +1,8 @@
+-- Full-Text Search over chat history
+-- A generated tsvector keeps the search document in sync with messages.content
+
+ALTER TABLE messages
+    ADD COLUMN content_tsv tsvector
+    GENERATED ALWAYS AS (to_tsvector('english', content)) STORED;
+
+CREATE INDEX idx_messages_content_tsv ON messages USING GIN (content_tsv);
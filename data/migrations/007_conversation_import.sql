@@ -0,0 +1,20 @@
+-- Conversation Import
+-- Tracks where imported conversations came from so re-imports are reported as duplicates
+
+ALTER TABLE conversations ADD COLUMN imported_from UUID;
+
+CREATE UNIQUE INDEX idx_conversations_imported_from
+    ON conversations(user_id, imported_from)
+    WHERE imported_from IS NOT NULL;
+
+-- Only stamp updated_at when the statement did not set it explicitly,
+-- so imports can restore the original timestamps
+CREATE OR REPLACE FUNCTION update_updated_at_column()
+RETURNS TRIGGER AS $$
+BEGIN
+    IF NEW.updated_at IS NOT DISTINCT FROM OLD.updated_at THEN
+        NEW.updated_at = NOW();
+    END IF;
+    RETURN NEW;
+END;
+$$ language 'plpgsql';
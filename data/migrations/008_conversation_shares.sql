@@ -0,0 +1,20 @@
+-- Shareable Conversation Links
+-- Read-only links to a conversation; only SHA256 hashes of the tokens are stored
+
+-- ============================================================================
+-- CONVERSATION SHARES TABLE - Revocable, optionally expiring share tokens
+-- ============================================================================
+CREATE TABLE conversation_shares (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    conversation_id UUID NOT NULL REFERENCES conversations(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    token_hash VARCHAR(255) UNIQUE NOT NULL,
+    expires_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT NOW(),
+    last_accessed_at TIMESTAMP,
+    access_count INTEGER DEFAULT 0
+);
+
+CREATE INDEX idx_conversation_shares_conversation_id ON conversation_shares(conversation_id);
+
+GRANT ALL PRIVILEGES ON ALL TABLES IN SCHEMA public TO clarticle_user;
@@ -0,0 +1,33 @@
+-- Conversation Branching
+-- Messages form a tree; editing or regenerating adds a sibling instead of overwriting.
+-- The conversation remembers which leaf is active, and history follows that branch.
+
+ALTER TABLE messages ADD COLUMN parent_id UUID REFERENCES messages(id) ON DELETE CASCADE;
+ALTER TABLE conversations ADD COLUMN active_leaf_id UUID REFERENCES messages(id) ON DELETE SET NULL;
+
+CREATE INDEX idx_messages_parent_id ON messages(parent_id);
+
+-- Backfilling must not touch updated_at, which orders the conversation list
+ALTER TABLE conversations DISABLE TRIGGER update_conversations_updated_at;
+
+-- Existing conversations become a single linear branch.
+-- Message pairs share a created_at, so the user message sorts first within a pair.
+WITH ordered AS (
+    SELECT id,
+           LAG(id) OVER (PARTITION BY conversation_id ORDER BY created_at, (role = 'assistant'), id) AS previous_id
+    FROM messages
+)
+UPDATE messages m
+SET parent_id = ordered.previous_id
+FROM ordered
+WHERE m.id = ordered.id AND ordered.previous_id IS NOT NULL;
+
+UPDATE conversations c
+SET active_leaf_id = (
+    SELECT m.id FROM messages m
+    WHERE m.conversation_id = c.id
+    ORDER BY m.created_at DESC, (m.role = 'assistant') DESC, m.id DESC
+    LIMIT 1
+);
+
+ALTER TABLE conversations ENABLE TRIGGER update_conversations_updated_at;
@@ -0,0 +1,23 @@
+-- Message Feedback
+-- Thumbs up/down ratings on assistant answers, with optional reason tags and a comment.
+-- One rating per user per message; rating again replaces the previous one.
+
+-- ============================================================================
+-- MESSAGE FEEDBACK TABLE
+-- ============================================================================
+CREATE TABLE message_feedback (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    message_id UUID NOT NULL REFERENCES messages(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    rating VARCHAR(10) NOT NULL CHECK (rating IN ('up', 'down')),
+    reasons TEXT[] NOT NULL DEFAULT '{}',
+    comment TEXT,
+    created_at TIMESTAMP DEFAULT NOW(),
+    updated_at TIMESTAMP DEFAULT NOW(),
+    UNIQUE (message_id, user_id)
+);
+
+-- The quality report filters by date range
+CREATE INDEX idx_message_feedback_updated_at ON message_feedback(updated_at);
+
+GRANT ALL PRIVILEGES ON ALL TABLES IN SCHEMA public TO clarticle_user;
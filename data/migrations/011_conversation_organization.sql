@@ -0,0 +1,56 @@
+-- Conversation Organization
+-- Pinned and archived flags, user-defined folders and tags
+
+-- ============================================================================
+-- FOLDERS AND TAGS - Defined per user
+-- ============================================================================
+CREATE TABLE folders (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    name VARCHAR(100) NOT NULL,
+    created_at TIMESTAMP DEFAULT NOW(),
+    updated_at TIMESTAMP DEFAULT NOW(),
+    UNIQUE (user_id, name)
+);
+
+CREATE TABLE tags (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    name VARCHAR(50) NOT NULL,
+    created_at TIMESTAMP DEFAULT NOW(),
+    updated_at TIMESTAMP DEFAULT NOW(),
+    UNIQUE (user_id, name)
+);
+
+CREATE TRIGGER update_folders_updated_at BEFORE UPDATE ON folders
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+
+CREATE TRIGGER update_tags_updated_at BEFORE UPDATE ON tags
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+
+-- ============================================================================
+-- CONVERSATIONS - Flags, folder and tag assignments
+-- ============================================================================
+ALTER TABLE conversations ADD COLUMN pinned_at TIMESTAMP;
+ALTER TABLE conversations ADD COLUMN archived_at TIMESTAMP;
+ALTER TABLE conversations ADD COLUMN folder_id UUID REFERENCES folders(id) ON DELETE SET NULL;
+
+CREATE INDEX idx_conversations_folder_id ON conversations(folder_id);
+
+CREATE TABLE conversation_tags (
+    conversation_id UUID NOT NULL REFERENCES conversations(id) ON DELETE CASCADE,
+    tag_id UUID NOT NULL REFERENCES tags(id) ON DELETE CASCADE,
+    created_at TIMESTAMP DEFAULT NOW(),
+    PRIMARY KEY (conversation_id, tag_id)
+);
+
+CREATE INDEX idx_conversation_tags_tag_id ON conversation_tags(tag_id);
+
+-- Organizing a conversation must not move it to the top of the recency-ordered list,
+-- so updated_at is only stamped when its content or title changes
+DROP TRIGGER update_conversations_updated_at ON conversations;
+CREATE TRIGGER update_conversations_updated_at
+    BEFORE UPDATE OF title, message_count, active_leaf_id, updated_at ON conversations
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+
+GRANT ALL PRIVILEGES ON ALL TABLES IN SCHEMA public TO clarticle_user;
@@ -0,0 +1,5 @@
+-- Conversation Titles
+-- Titles are generated automatically after the first exchange unless the user set one.
+-- title_manual marks titles chosen by the user, which generated titles never replace.
+
+ALTER TABLE conversations ADD COLUMN title_manual BOOLEAN NOT NULL DEFAULT false;
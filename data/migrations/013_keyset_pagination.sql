@@ -0,0 +1,12 @@
+-- Keyset Pagination
+-- Composite indexes backing cursor pagination of conversation lists and message history.
+-- Conversation lists put pinned conversations first, then order by the sort column and ID.
+
+CREATE INDEX idx_conversations_user_pinned_updated
+    ON conversations (user_id, (pinned_at IS NOT NULL) DESC, updated_at DESC, id DESC);
+CREATE INDEX idx_conversations_user_pinned_created
+    ON conversations (user_id, (pinned_at IS NOT NULL) DESC, created_at DESC, id DESC);
+
+-- Messages page in conversation order: question before answer within one timestamp
+CREATE INDEX idx_messages_conversation_created
+    ON messages (conversation_id, created_at, (role = 'assistant'), id);
@@ -0,0 +1,8 @@
+-- Conversation Summaries
+-- Turns that no longer fit the history window sent to the RAG service are folded into a rolling
+-- summary. summary_message_id is the last message the summary covers; the summary only applies
+-- while that message is on the active branch.
+
+ALTER TABLE conversations ADD COLUMN summary TEXT;
+ALTER TABLE conversations ADD COLUMN summary_message_id UUID REFERENCES messages(id) ON DELETE SET NULL;
+ALTER TABLE conversations ADD COLUMN summary_updated_at TIMESTAMP;
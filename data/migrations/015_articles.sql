@@ -0,0 +1,35 @@
+-- Articles
+-- Articles added to the knowledge base, replacing the in-memory list of the article handler.
+-- Duplicates are detected by canonical URL (one live article per URL) and by content hash.
+
+-- ============================================================================
+-- ARTICLES TABLE
+-- ============================================================================
+CREATE TABLE articles (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    url TEXT NOT NULL,
+    canonical_url TEXT NOT NULL UNIQUE,
+    content_hash CHAR(64),
+    title TEXT NOT NULL DEFAULT '',
+    content TEXT NOT NULL DEFAULT '',
+    raw_html TEXT,
+    author VARCHAR(255),
+    source VARCHAR(50) NOT NULL,
+    status VARCHAR(20) NOT NULL CHECK (status IN ('fetched', 'indexing', 'indexed', 'failed')),
+    chunk_count INTEGER NOT NULL DEFAULT 0,
+    metadata JSONB NOT NULL DEFAULT '{}',
+    published_at TIMESTAMP,
+    fetched_at TIMESTAMP,
+    processed_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT NOW(),
+    updated_at TIMESTAMP DEFAULT NOW()
+);
+
+-- Duplicate lookups by extracted content
+CREATE INDEX idx_articles_content_hash ON articles(content_hash);
+CREATE INDEX idx_articles_created_at ON articles(created_at DESC);
+
+CREATE TRIGGER update_articles_updated_at BEFORE UPDATE ON articles
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+
+GRANT ALL PRIVILEGES ON ALL TABLES IN SCHEMA public TO clarticle_user;
@@ -0,0 +1,37 @@
+-- Article Refresh
+-- Indexed articles are re-fetched periodically with conditional requests; changed content is
+-- re-embedded and the replaced version kept in article_versions.
+
+-- ============================================================================
+-- REFRESH STATE
+-- ============================================================================
+ALTER TABLE articles
+    ADD COLUMN version INTEGER NOT NULL DEFAULT 1,
+    ADD COLUMN etag TEXT,
+    ADD COLUMN last_modified TEXT,
+    -- Seconds between checks; NULL uses the configured default, 0 disables refreshing
+    ADD COLUMN refresh_interval INTEGER CHECK (refresh_interval >= 0),
+    ADD COLUMN last_checked_at TIMESTAMP;
+
+-- Checks and status changes are bookkeeping: updated_at marks new content only
+DROP TRIGGER update_articles_updated_at ON articles;
+CREATE TRIGGER update_articles_updated_at
+    BEFORE UPDATE OF title, content, version ON articles
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+
+-- ============================================================================
+-- ARTICLE VERSIONS - Content replaced by a refresh
+-- ============================================================================
+CREATE TABLE article_versions (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    article_id UUID NOT NULL REFERENCES articles(id) ON DELETE CASCADE,
+    version INTEGER NOT NULL,
+    title TEXT NOT NULL,
+    content TEXT NOT NULL,
+    content_hash CHAR(64),
+    fetched_at TIMESTAMP,
+    replaced_at TIMESTAMP DEFAULT NOW(),
+    UNIQUE (article_id, version)
+);
+
+GRANT ALL PRIVILEGES ON ALL TABLES IN SCHEMA public TO clarticle_user;
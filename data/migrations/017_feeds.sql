@@ -0,0 +1,42 @@
+-- Feed Subscriptions
+-- RSS and Atom feeds polled on a per-feed interval; new entries are ingested as articles.
+-- Polling errors push next_poll_at back exponentially until a poll succeeds.
+
+-- ============================================================================
+-- FEEDS TABLE
+-- ============================================================================
+CREATE TABLE feeds (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    url TEXT NOT NULL UNIQUE,
+    title TEXT NOT NULL DEFAULT '',
+    -- Added as a tag to every article ingested from the feed
+    category VARCHAR(50) NOT NULL DEFAULT '',
+    -- Seconds between polls while the feed is healthy
+    poll_interval INTEGER NOT NULL CHECK (poll_interval > 0),
+    enabled BOOLEAN NOT NULL DEFAULT TRUE,
+    etag TEXT,
+    last_modified TEXT,
+    error_count INTEGER NOT NULL DEFAULT 0,
+    last_error TEXT,
+    last_polled_at TIMESTAMP,
+    next_poll_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    created_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP DEFAULT NOW(),
+    updated_at TIMESTAMP DEFAULT NOW()
+);
+
+-- Scheduler lookups of due feeds
+CREATE INDEX idx_feeds_next_poll_at ON feeds(next_poll_at) WHERE enabled;
+
+-- Polls are bookkeeping: updated_at marks changes to the subscription only
+CREATE TRIGGER update_feeds_updated_at
+    BEFORE UPDATE OF url, title, category, poll_interval, enabled ON feeds
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+
+-- ============================================================================
+-- FEED ARTICLES - The feed an article was ingested from
+-- ============================================================================
+ALTER TABLE articles ADD COLUMN feed_id UUID REFERENCES feeds(id) ON DELETE SET NULL;
+CREATE INDEX idx_articles_feed_id ON articles(feed_id);
+
+GRANT ALL PRIVILEGES ON ALL TABLES IN SCHEMA public TO clarticle_user;
@@ -0,0 +1,30 @@
+-- Article Listing
+-- Filters, keyword search and keyset pagination for GET /api/articles.
+
+-- Who submitted or uploaded the article; feed entries have no submitter
+ALTER TABLE articles ADD COLUMN submitted_by UUID REFERENCES users(id) ON DELETE SET NULL;
+CREATE INDEX idx_articles_submitted_by ON articles(submitted_by);
+
+-- Host of the canonical URL, lowercase and without port
+ALTER TABLE articles
+    ADD COLUMN domain TEXT
+    GENERATED ALWAYS AS (substring(canonical_url FROM '^[a-z]+://([^/:?#]+)')) STORED;
+CREATE INDEX idx_articles_domain ON articles(domain);
+
+-- Keyword search over title (ranked higher) and content
+ALTER TABLE articles
+    ADD COLUMN search_tsv tsvector
+    GENERATED ALWAYS AS (
+        setweight(to_tsvector('english', title), 'A') || setweight(to_tsvector('english', content), 'B')
+    ) STORED;
+CREATE INDEX idx_articles_search_tsv ON articles USING GIN (search_tsv);
+
+-- Tag filters match metadata.tags
+CREATE INDEX idx_articles_tags ON articles USING GIN ((metadata -> 'tags'));
+
+-- Sort orders, each with the ID as tie-breaker
+CREATE INDEX idx_articles_fetched_at ON articles (COALESCE(fetched_at, '-infinity'::timestamp) DESC, id DESC);
+CREATE INDEX idx_articles_published_at ON articles (COALESCE(published_at, '-infinity'::timestamp) DESC, id DESC);
+CREATE INDEX idx_articles_title ON articles (title, id);
+
+GRANT ALL PRIVILEGES ON ALL TABLES IN SCHEMA public TO clarticle_user;
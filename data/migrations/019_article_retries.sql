@@ -0,0 +1,13 @@
+-- Article Retries
+-- Failed ingestions keep their error and are retried with exponential backoff until
+-- the attempt limit; a successful ingestion clears the failure state.
+
+ALTER TABLE articles
+    ADD COLUMN last_error TEXT,
+    ADD COLUMN attempts INTEGER NOT NULL DEFAULT 0, -- Failed attempts since the last success
+    ADD COLUMN next_retry_at TIMESTAMP;             -- NULL: no automatic retry scheduled
+
+CREATE INDEX idx_articles_next_retry_at ON articles(next_retry_at)
+    WHERE status = 'failed' AND next_retry_at IS NOT NULL;
+
+GRANT ALL PRIVILEGES ON ALL TABLES IN SCHEMA public TO clarticle_user;
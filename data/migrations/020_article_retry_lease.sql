@@ -0,0 +1,7 @@
+-- Article Retry Lease
+-- A failed article is leased while a retry runs, so a manual retry and the retry
+-- scheduler never re-ingest the same article at the same time.
+
+ALTER TABLE articles ADD COLUMN retry_leased_until TIMESTAMP; -- NULL or past: no retry running
+
+GRANT ALL PRIVILEGES ON ALL TABLES IN SCHEMA public TO clarticle_user;
@@ -0,0 +1,8 @@
+-- SSO Re-authentication
+-- Sessions remember whether they came from an SSO login, so accounts without a
+-- password can confirm sensitive changes by signing in through SSO again.
+
+ALTER TABLE user_sessions ADD COLUMN via_sso BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE two_factor_challenges ADD COLUMN via_sso BOOLEAN NOT NULL DEFAULT FALSE; -- Passed on to the session once the code is accepted
+
+GRANT ALL PRIVILEGES ON ALL TABLES IN SCHEMA public TO clarticle_user;
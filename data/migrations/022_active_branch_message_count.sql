@@ -0,0 +1,24 @@
+-- Active Branch Message Count
+-- conversations.message_count is the length of the active branch: what the conversation
+-- shows, exports and sends as history. Writers set it along with active_leaf_id, so the
+-- per-message trigger (which counted every stored message, including other branches) goes.
+
+DROP TRIGGER update_conversation_message_count_trigger ON messages;
+DROP FUNCTION update_conversation_message_count();
+
+-- Recounting must not touch updated_at, which orders the conversation list
+ALTER TABLE conversations DISABLE TRIGGER update_conversations_updated_at;
+
+UPDATE conversations c
+SET message_count = (
+    WITH RECURSIVE path AS (
+        SELECT m.id, m.parent_id FROM messages m WHERE m.id = c.active_leaf_id
+        UNION ALL
+        SELECT p.id, p.parent_id FROM messages p JOIN path ON p.id = path.parent_id
+    )
+    SELECT COUNT(*) FROM path
+);
+
+ALTER TABLE conversations ENABLE TRIGGER update_conversations_updated_at;
+
+GRANT ALL PRIVILEGES ON ALL TABLES IN SCHEMA public TO clarticle_user;
@@ -104,6 +104,52 @@ export const chatValidationRules: ValidationRule[] = [
     type: 'string',
     pattern: /^[a-zA-Z0-9-_]+$/,
   },
+  {
+    field: 'conversationSummary',
+    required: false,
+    type: 'string',
+    maxLength: 4000,
+  },
+];
+
+export const summaryValidationRules: ValidationRule[] = [
+  {
+    field: 'previousSummary',
+    required: false,
+    type: 'string',
+    maxLength: 4000,
+  },
+  {
+    field: 'messages',
+    required: true,
+    custom: (value: any) => {
+      if (!Array.isArray(value) || value.length === 0) {
+        return 'messages must be a non-empty array';
+      }
+      for (const msg of value) {
+        if (!msg || typeof msg.content !== 'string' || (msg.role !== 'user' && msg.role !== 'assistant')) {
+          return 'Each message needs a role (user or assistant) and content';
+        }
+      }
+      return true;
+    },
+  },
+];
+
+export const titleValidationRules: ValidationRule[] = [
+  {
+    field: 'query',
+    required: true,
+    type: 'string',
+    minLength: 1,
+    maxLength: 4000,
+  },
+  {
+    field: 'response',
+    required: true,
+    type: 'string',
+    minLength: 1,
+  },
 ];
 
 export const articleValidationRules: ValidationRule[] = [
@@ -111,7 +157,8 @@ export const articleValidationRules: ValidationRule[] = [
     field: 'url',
     required: true,
     type: 'string',
-    pattern: /^https?:\/\/.+/,
+    // upload:// identifies a document uploaded to the backend; its content is always provided
+    pattern: /^(https?|upload):\/\/.+/,
     custom: (value: string) => {
       try {
         new URL(value);
@@ -121,6 +168,23 @@ export const articleValidationRules: ValidationRule[] = [
       }
     },
   },
+  {
+    field: 'title',
+    required: false,
+    type: 'string',
+    maxLength: 500,
+  },
+  {
+    field: 'content',
+    required: false,
+    type: 'string',
+    minLength: 1,
+  },
+  {
+    field: 'replace',
+    required: false,
+    type: 'boolean',
+  },
   {
     field: 'metadata',
     required: false,
@@ -13,6 +13,8 @@ const router = Router();
 interface ProcessArticleRequest {
   url: string;
   title?: string;
+  content?: string; // Already extracted by the backend; the URL is fetched when omitted (required for upload://)
+  replace?: boolean; // Re-index: replace the chunks stored for the URL
 }
 
 interface ProcessBatchRequest {
@@ -120,7 +122,7 @@ async function fetchArticleContent(url: string): Promise<{ title: string; conten
 router.post('/process', 
   validateRequest(articleValidationRules),
   asyncHandler(async (req: Request, res: Response): Promise<void> => {
-    const { url, title }: ProcessArticleRequest = req.body;
+    const { url, title, content: providedContent, replace = false }: ProcessArticleRequest = req.body;
 
     // Process single article
 
@@ -132,13 +134,29 @@ router.post('/process',
       );
     }
 
-    const { title: fetchedTitle, content } = await fetchArticleContent(url);
-    const articleTitle = title || fetchedTitle;
+    let content = providedContent;
+    let articleTitle = title || '';
+    if (!content && url.startsWith('upload://')) {
+      throw createError(
+        ErrorCode.VALIDATION_ERROR,
+        'Uploaded documents must include their content'
+      );
+    }
+    if (!content) {
+      const fetched = await fetchArticleContent(url);
+      content = fetched.content;
+      articleTitle = articleTitle || fetched.title;
+    }
+    articleTitle = articleTitle || extractTitleFromUrl(url);
 
-    const ids = await langchainService.processArticle(url, content);
+    const ids = await langchainService.processArticle(url, content, replace);
 
-    // Update article count in prompt engineering service
-    promptEngineeringService.incrementArticleCount(url);
+    // Update article count in prompt engineering service; a re-index replaces the article's entry
+    if (replace) {
+      processedArticles = processedArticles.filter(article => article.url !== url);
+    } else {
+      promptEngineeringService.incrementArticleCount(url);
+    }
 
     // Store processed article metadata
     processedArticles.push({
@@ -1,7 +1,8 @@
 import { Router, Request, Response } from 'express';
 import { langchainService } from '../services/langchain.service';
+import { claudeService } from '../services/claude.service';
 import { asyncHandler } from '../middleware/error-handler';
-import { validateRequest, chatValidationRules } from '../middleware/validation';
+import { validateRequest, chatValidationRules, summaryValidationRules, titleValidationRules } from '../middleware/validation';
 import { createError, ErrorCode } from '../utils/errors';
 
 const router = Router();
@@ -16,6 +17,7 @@ interface ChatRequest {
   query: string;
   conversationId?: string;
   conversationHistory?: ChatMessage[];
+  conversationSummary?: string; // Summary of turns older than conversationHistory
 }
 
 interface ChunkSource {
@@ -42,7 +44,7 @@ interface ChatResponse {
 router.post('/', 
   validateRequest(chatValidationRules),
   asyncHandler(async (req: Request, res: Response): Promise<void> => {
-    const { query, conversationId = 'default', conversationHistory = [] }: ChatRequest = req.body;
+    const { query, conversationId = 'default', conversationHistory = [], conversationSummary = '' }: ChatRequest = req.body;
 
     // Process chat query
 
@@ -54,7 +56,7 @@ router.post('/',
       );
     }
 
-    const formattedResponse = await langchainService.processChat(query, conversationId, conversationHistory);
+    const formattedResponse = await langchainService.processChat(query, conversationId, conversationHistory, conversationSummary);
 
     const chatResponse: ChatResponse = {
       response: formattedResponse.answer,
@@ -75,7 +77,7 @@ router.post('/',
 router.post('/stream', 
   validateRequest(chatValidationRules),
   asyncHandler(async (req: Request, res: Response): Promise<void> => {
-    const { query, conversationId = 'default', conversationHistory = [] }: ChatRequest = req.body;
+    const { query, conversationId = 'default', conversationHistory = [], conversationSummary = '' }: ChatRequest = req.body;
 
     // Process streaming chat query
 
@@ -94,7 +96,7 @@ router.post('/stream',
     res.setHeader('Access-Control-Allow-Headers', 'Cache-Control');
 
     try {
-      const stream = await langchainService.processChatStreaming(query, conversationId, conversationHistory);
+      const stream = await langchainService.processChatStreaming(query, conversationId, conversationHistory, conversationSummary);
 
       for await (const chunk of stream) {
         res.write(`data: ${JSON.stringify({ content: chunk, done: false })}\n\n`);
@@ -118,6 +120,44 @@ router.post('/stream',
   })
 );
 
+// Concise conversation title from the first exchange (no retrieval involved)
+router.post('/title',
+  validateRequest(titleValidationRules),
+  asyncHandler(async (req: Request, res: Response): Promise<void> => {
+    const { query, response }: { query: string; response: string } = req.body;
+
+    if (!claudeService.isConfigured()) {
+      throw createError(
+        ErrorCode.SERVICE_NOT_INITIALIZED,
+        'Chat service is not initialized'
+      );
+    }
+
+    const title = await claudeService.generateTitle(query, response);
+
+    res.json({ title });
+  })
+);
+
+// Rolling summary of turns that fall outside the history window (no retrieval involved)
+router.post('/summarize',
+  validateRequest(summaryValidationRules),
+  asyncHandler(async (req: Request, res: Response): Promise<void> => {
+    const { previousSummary = '', messages }: { previousSummary?: string; messages: ChatMessage[] } = req.body;
+
+    if (!claudeService.isConfigured()) {
+      throw createError(
+        ErrorCode.SERVICE_NOT_INITIALIZED,
+        'Chat service is not initialized'
+      );
+    }
+
+    const summary = await claudeService.summarizeConversation(previousSummary, messages);
+
+    res.json({ summary });
+  })
+);
+
 router.get('/history/:conversationId', asyncHandler(async (req: Request, res: Response): Promise<void> => {
   const { conversationId } = req.params;
   
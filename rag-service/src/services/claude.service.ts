@@ -34,7 +34,7 @@
 // - Role Mapping: Converts between "user"/"assistant" and LangChain message types
 // - Context Preservation: Maintains conversation state across multiple turns
 import { ChatAnthropic } from "@langchain/anthropic";
-import { BaseMessage, HumanMessage, AIMessage } from "@langchain/core/messages";
+import { BaseMessage, HumanMessage, AIMessage, SystemMessage } from "@langchain/core/messages";
 import { createError, ErrorCode } from "../utils/errors";
 
 /**
@@ -119,6 +119,61 @@ export class ClaudeService {
     }
   }
 
+  /**
+   * Generate a short conversation title from the first question and answer
+   * Used by the Go API to replace the truncated first-message title of new conversations
+   *
+   * @param question - First user message of the conversation
+   * @param answer - Assistant reply to that message
+   * @returns Promise<string> - Title without quotes or trailing punctuation
+   */
+  async generateTitle(question: string, answer: string): Promise<string> {
+    const messages: BaseMessage[] = [
+      new SystemMessage(
+        "You write titles for chat conversations. Reply with a concise title of at most 6 words " +
+        "describing the topic, in the language of the question. No quotes, no trailing punctuation, nothing else."
+      ),
+      new HumanMessage(`Question:\n${question}\n\nAnswer:\n${answer.slice(0, 2000)}`),
+    ];
+
+    const title = await this.generateResponse(messages);
+    return title
+      .split("\n")[0]
+      .replace(/^["'\s]+|["'.\s]+$/g, "")
+      .trim();
+  }
+
+  /**
+   * Fold older conversation turns into a rolling summary
+   * Used by the Go API for turns that no longer fit the history window sent with each question
+   *
+   * @param previousSummary - Summary of the turns before these messages ('' for the first summary)
+   * @param messages - Turns to add to the summary, oldest first
+   * @returns Promise<string> - Updated summary
+   */
+  async summarizeConversation(
+    previousSummary: string,
+    messages: Array<{ role: string; content: string }>
+  ): Promise<string> {
+    const transcript = messages
+      .map(msg => `${msg.role === 'user' ? 'User' : 'Assistant'}: ${msg.content.slice(0, 1500)}`)
+      .join("\n\n");
+
+    const request: BaseMessage[] = [
+      new SystemMessage(
+        "You maintain a running summary of a chat conversation about articles. Merge the earlier summary " +
+        "with the new turns into one summary of at most 200 words. Keep the user's goals, questions asked, " +
+        "key facts and conclusions, and the articles referred to. Reply with the summary only."
+      ),
+      new HumanMessage(
+        `Earlier summary:\n${previousSummary || "(none)"}\n\nNew turns:\n${transcript}`
+      ),
+    ];
+
+    const summary = await this.generateResponse(request);
+    return summary.trim();
+  }
+
   /**
    * Generate streaming response from Claude API for real-time delivery
    * Used for long responses where progressive display improves user experience
@@ -57,6 +57,30 @@ export class FaissVectorStoreService {
     await this.save();
   }
 
+  // IDs of the stored chunks whose metadata.source is the given article URL
+  getDocumentIdsBySource(source: string): string[] {
+    if (!this.vectorStore) {
+      throw new Error('Vector store not initialized');
+    }
+
+    const docs = this.vectorStore.getDocstore()._docs;
+    return [...docs.entries()]
+      .filter(([, doc]) => doc.metadata?.source === source)
+      .map(([id]) => id);
+  }
+
+  async deleteDocuments(ids: string[]): Promise<void> {
+    if (!this.vectorStore) {
+      throw new Error('Vector store not initialized');
+    }
+    if (ids.length === 0) {
+      return;
+    }
+
+    await this.vectorStore.delete({ ids });
+    await this.save();
+  }
+
   async similaritySearch(query: string, k: number = 4): Promise<Document[]> {
     if (!this.vectorStore) {
       throw new Error('Vector store not initialized');
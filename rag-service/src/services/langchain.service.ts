@@ -86,8 +86,11 @@ Provide a comprehensive and helpful answer based on the provided articles:`);
     );
   }
 
-  async processArticle(url: string, content: string): Promise<string[]> {
+  // With replace, chunks previously stored for the URL are removed once the new ones are added,
+  // so a failed re-index leaves the old version searchable
+  async processArticle(url: string, content: string, replace: boolean = false): Promise<string[]> {
     try {
+      const previousIds = replace ? faissVectorStoreService.getDocumentIdsBySource(url) : [];
       const chunks = await this.textSplitter.splitText(content);
       
       const documents = chunks.map((chunk, index) => new Document({
@@ -101,8 +104,9 @@ Provide a comprehensive and helpful answer based on the provided articles:`);
       }));
 
       await faissVectorStoreService.addDocuments(documents);
+      await faissVectorStoreService.deleteDocuments(previousIds);
       const ids = documents.map((_, index) => `${url}_chunk_${index}`);
-      console.log(`Processed article ${url} into ${chunks.length} chunks`);
+      console.log(`Processed article ${url} into ${chunks.length} chunks` + (replace ? `, replacing ${previousIds.length}` : ''));
       
       return ids;
     } catch (error) {
@@ -111,7 +115,7 @@ Provide a comprehensive and helpful answer based on the provided articles:`);
     }
   }
 
-  async processChat(query: string, conversationId: string = 'default', providedHistory: ChatMessage[] = []): Promise<FormattedResponse> {
+  async processChat(query: string, conversationId: string = 'default', providedHistory: ChatMessage[] = [], summary: string = ''): Promise<FormattedResponse> {
     if (!this.ragChain) {
       throw new Error('RAG chain not initialized');
     }
@@ -143,7 +147,7 @@ Provide a comprehensive and helpful answer based on the provided articles:`);
       }));
 
       // Generate specialized prompt based on question type and include conversation context
-      const specializedPrompt = promptEngineeringService.generatePrompt(query, questionType, context, historyToUse);
+      const specializedPrompt = promptEngineeringService.generatePrompt(query, questionType, context, historyToUse, summary);
 
       // Convert history to Claude message format
       const messages = claudeService.formatMessagesFromHistory(historyToUse);
@@ -178,7 +182,7 @@ Provide a comprehensive and helpful answer based on the provided articles:`);
     }
   }
 
-  async processChatStreaming(query: string, conversationId: string = 'default', providedHistory: ChatMessage[] = []): Promise<AsyncIterable<string>> {
+  async processChatStreaming(query: string, conversationId: string = 'default', providedHistory: ChatMessage[] = [], summary: string = ''): Promise<AsyncIterable<string>> {
     if (!this.ragChain) {
       throw new Error('RAG chain not initialized');
     }
@@ -195,7 +199,7 @@ Provide a comprehensive and helpful answer based on the provided articles:`);
       const context = relevantDocs.map((doc: Document) => doc.pageContent).join('\n\n');
 
       // Generate specialized prompt based on question type and include conversation context
-      const specializedPrompt = promptEngineeringService.generatePrompt(query, questionType, context, historyToUse);
+      const specializedPrompt = promptEngineeringService.generatePrompt(query, questionType, context, historyToUse, summary);
       
       const messages = claudeService.formatMessagesFromHistory(historyToUse);
       messages.push({
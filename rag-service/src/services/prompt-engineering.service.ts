@@ -237,7 +237,7 @@ export class PromptEngineeringService {
     return introPatterns.some(pattern => pattern.test(query));
   }
 
-  generatePrompt(query: string, questionType: QuestionType, context: string, conversationHistory: any[] = []): string {
+  generatePrompt(query: string, questionType: QuestionType, context: string, conversationHistory: any[] = [], summary: string = ''): string {
     // For general introduction queries, don't use specific article context
     const isIntroductionQuery = this.isIntroductionQuery(query);
     const baseContext = isIntroductionQuery ? '' : `Context from Articles:\n${context}\n\n`;
@@ -251,6 +251,11 @@ export class PromptEngineeringService {
         `${msg.role === 'user' ? 'User' : 'Assistant'}: ${msg.content}`
       ).join('\n')}\n\n`;
     }
+
+    // Older turns arrive as a rolling summary ahead of the recent messages
+    if (summary) {
+      historyContext = `Summary of Earlier Conversation:\n${summary}\n\n${historyContext}`;
+    }
     
     switch (questionType.type) {
       case 'articles_list':
@@ -44,6 +44,7 @@ import (
 	"article-chat-system/server/internal/database"
 	"article-chat-system/server/internal/fetcher"
 	"article-chat-system/server/internal/handlers"
+	"article-chat-system/server/internal/mailer"
 	"article-chat-system/server/internal/middleware"
 	"article-chat-system/server/internal/services"
 	"article-chat-system/server/internal/workers"
@@ -143,15 +144,57 @@ func main() {
 	}
 
 	// PHASE 5: SERVICE INITIALIZATION
-	// Initialize authentication service
-	authService := auth.NewAuthService(db)
+	// Initialize mailer for confirmation emails (log driver by default)
+	mail, err := mailer.New(cfg.Mail)
+	if err != nil {
+		log.Fatal("Failed to initialize mailer:", err)
+	}
+
+	// Initialize authentication service with cache-backed brute-force protection
+	loginLimiter := auth.NewLoginLimiter(cache, cfg.Auth.LoginProtection)
+	authService := auth.NewAuthService(db, loginLimiter, mail, cfg.Auth, cfg.Mail)
 
 	// Initialize HTTP client for communicating with Node.js RAG service
 	// This client handles all AI/RAG operations including chat processing and article embedding
 	ragClient := services.NewRAGClient(cfg.RAGService)
 
 	// Initialize article fetcher for processing URLs (if needed for backup/validation)
-	articleFetcher := fetcher.NewArticleFetcher()
+	articleFetcher := fetcher.NewArticleFetcher(cfg.Fetcher)
+
+	// Article ingestion shared by submissions and feeds: dedup, fetch, store, index
+	articleIngester := services.NewArticleIngester(articleFetcher, ragClient, db, cache, cfg.Fetcher.Retry)
+
+	// Periodic re-fetch of indexed articles; changed content is re-embedded
+	refreshCtx, stopRefresh := context.WithCancel(context.Background())
+	defer stopRefresh()
+	if cfg.Fetcher.Refresh.Enabled {
+		services.NewArticleRefresher(cfg.Fetcher.Refresh, articleFetcher, ragClient, db, poolManager).Start(refreshCtx)
+		slog.Info("Article refresh enabled", "interval", cfg.Fetcher.Refresh.Interval, "poll_interval", cfg.Fetcher.Refresh.PollInterval)
+	}
+
+	// RSS/Atom subscriptions; new entries are ingested as articles (stopped with the refresher)
+	if cfg.Fetcher.Feeds.Enabled {
+		services.NewFeedPoller(cfg.Fetcher.Feeds, articleFetcher, articleIngester, db, poolManager).Start(refreshCtx)
+		slog.Info("Feed polling enabled", "poll_interval", cfg.Fetcher.Feeds.PollInterval)
+	}
+
+	// Failed ingestions retried with exponential backoff (stopped with the refresher)
+	if cfg.Fetcher.Retry.Enabled {
+		services.NewArticleRetrier(cfg.Fetcher.Retry, articleIngester, db, poolManager).Start(refreshCtx)
+		slog.Info("Article retries enabled", "max_attempts", cfg.Fetcher.Retry.MaxAttempts, "poll_interval", cfg.Fetcher.Retry.PollInterval)
+	}
+
+	// Admin-triggered re-ingestion of stored articles, stopped on shutdown
+	articleReindexer := services.NewArticleReindexer(refreshCtx, articleIngester, db, poolManager)
+
+	// Bounded chat history with optional rolling summaries of older turns
+	historyWindow := services.NewHistoryWindow(cfg.Chat.History, ragClient, db, poolManager)
+
+	// Conversation titles generated by the RAG service; without it titles are cut from the first message
+	var titleGenerator *services.TitleGenerator
+	if cfg.Chat.AutoTitles {
+		titleGenerator = services.NewTitleGenerator(ragClient, db, poolManager)
+	}
 
 	// PHASE 6: RAG SERVICE HEALTH CHECK
 	// Verify Node.js RAG service is available before accepting requests
@@ -169,11 +212,25 @@ func main() {
 	// PHASE 7: HTTP HANDLER INITIALIZATION WITH DEPENDENCY INJECTION
 	// Handlers are initialized with their required dependencies for clean architecture
 	slog.Info("Initializing handlers")
-	authHandler := handlers.NewAuthHandler(authService)                                         // Auth: user authentication
-	chatHandler := handlers.NewChatHandler(ragClient, cache, db)                                // Chat: RAG + caching + persistence
-	conversationHandler := handlers.NewConversationHandler(db)                                  // Conversations: CRUD operations
-	articleHandler := handlers.NewArticleHandler(articleFetcher, ragClient, poolManager, cache) // Articles: fetching + RAG + pools + caching
-	healthHandler := handlers.NewHealthHandler(cfg, ragClient, poolManager)                     // Health: system status monitoring
+	authHandler := handlers.NewAuthHandler(authService)                                            // Auth: user authentication
+	accountHandler := handlers.NewAccountHandler(authService, cache)                               // Account: self-service changes and deletion
+	chatHandler := handlers.NewChatHandler(ragClient, cache, db, historyWindow, titleGenerator)    // Chat: RAG + caching + persistence
+	conversationHandler := handlers.NewConversationHandler(db)                                     // Conversations: CRUD operations
+	shareHandler := handlers.NewShareHandler(db)                                                   // Shares: read-only conversation links
+	feedbackHandler := handlers.NewFeedbackHandler(db, cache)                                      // Feedback: answer ratings + quality report
+	feedHandler := handlers.NewFeedHandler(articleFetcher, db, authService, cfg.Fetcher.Feeds)     // Feeds: RSS/Atom subscriptions
+	healthHandler := handlers.NewHealthHandler(cfg, ragClient, poolManager)                        // Health: system status monitoring
+	articleHandler := handlers.NewArticleHandler(articleFetcher, articleIngester, db, poolManager, // Articles: ingestion + persistence + pools
+		articleReindexer, authService, cfg.Fetcher.Uploads)
+
+	// Optional OpenID Connect single sign-on, enabled through OIDC_* configuration
+	var oidcHandler *handlers.OIDCHandler
+	if cfg.Auth.OIDC.Enabled {
+		oidcProvider := auth.NewOIDCProvider(cfg.Auth.OIDC, cache)
+		oidcHandler = handlers.NewOIDCHandler(authService, oidcProvider, cfg.Auth.OIDC)
+		slog.Info("OIDC single sign-on enabled", "issuer", cfg.Auth.OIDC.IssuerURL, "provider", cfg.Auth.OIDC.ProviderName)
+	}
+
 	slog.Info("Handlers initialized",
 		"auth_handler_nil", authHandler == nil,
 		"chat_handler_nil", chatHandler == nil,
@@ -183,10 +240,19 @@ func main() {
 
 	// PHASE 8: FIBER WEB SERVER CONFIGURATION
 	// Configure Fiber with appropriate timeouts and error handling
+	// Fiber's default 4 MB body limit, raised to fit the largest document upload plus form overhead
+	bodyLimit := max(fiber.DefaultBodyLimit, cfg.Fetcher.Uploads.MaxFiles*int(cfg.Fetcher.Uploads.MaxFileBytes)+1<<20)
 	app := fiber.New(fiber.Config{
 		ReadTimeout:  time.Duration(cfg.Server.ReadTimeout) * time.Second,  // Prevent slow loris attacks
 		WriteTimeout: time.Duration(cfg.Server.WriteTimeout) * time.Second, // Ensure responses don't hang
 		ErrorHandler: middleware.ErrorHandler(),                            // Standardized error responses across all endpoints
+		BodyLimit:    bodyLimit,                                            // Room for a full document upload
+
+		// Behind nginx the connecting address is the proxy; c.IP() reads X-Real-IP only from trusted proxies
+		ProxyHeader:             "X-Real-IP",
+		EnableTrustedProxyCheck: true,
+		TrustedProxies:          cfg.Server.TrustedProxyList(),
+		EnableIPValidation:      true,
 	})
 
 	// PHASE 9: MIDDLEWARE STACK SETUP
@@ -195,7 +261,7 @@ func main() {
 	app.Use(middleware.RequestID()) // Generate unique request IDs for tracing
 	app.Use(cors.New(cors.Config{   // Enable CORS for React frontend communication
 		AllowOrigins: "*", // Allow all origins (configure for production)
-		AllowMethods: "GET,POST,PUT,DELETE,OPTIONS",
+		AllowMethods: "GET,POST,PUT,PATCH,DELETE,OPTIONS",
 		AllowHeaders: "Origin,Content-Type,Accept,Authorization",
 	}))
 	// Note: Request logging middleware temporarily disabled for debugging
@@ -210,6 +276,10 @@ func main() {
 	// API route group for versioning and organization
 	api := app.Group("/api")
 
+	// Write routes outside /api/auth reject unverified users when EMAIL_VERIFICATION_ALLOW_UNVERIFIED_CHAT is false
+	// Must run after RequireAuth; reads and removing your own data stay open
+	verified := auth.RequireVerifiedEmail(authService)
+
 	// Development/testing endpoints for API validation
 	api.Get("/test", func(c *fiber.Ctx) error {
 		slog.Info("Test endpoint called")
@@ -233,35 +303,118 @@ func main() {
 		authGroup.Post("/logout-all", auth.RequireAuth(authService), authHandler.HandleLogoutAll) // Logout all sessions
 		authGroup.Get("/me", auth.RequireAuth(authService), authHandler.HandleGetProfile)         // Get current user profile
 		authGroup.Put("/profile", auth.RequireAuth(authService), authHandler.HandleUpdateProfile) // Update profile
-		authGroup.Get("/check-email", authHandler.HandleCheckEmail)                               // Check if email exists
+		authGroup.Get("/check-email", authHandler.HandleCheckEmail)                               // Check if email exists (auth required when verification is on)
+
+		// Email verification - signed link sent at signup
+		authGroup.Post("/verify-email", authHandler.HandleVerifyEmail)                                              // Confirm verification token
+		authGroup.Post("/verify-email/resend", auth.RequireAuth(authService), authHandler.HandleResendVerification) // Send a new link
+
+		// Account self-service - email change, deactivation and deletion (password confirmed)
+		authGroup.Delete("/account", auth.RequireAuth(authService), accountHandler.HandleDeleteAccount)  // Delete or deactivate account
+		authGroup.Post("/email", auth.RequireAuth(authService), accountHandler.HandleRequestEmailChange) // Request email change
+		authGroup.Post("/email/confirm", accountHandler.HandleConfirmEmailChange)                        // Confirm emailed token
+
+		// Two-factor authentication (TOTP) - enrollment, login challenge completion, disabling
+		authGroup.Get("/2fa", auth.RequireAuth(authService), authHandler.HandleTwoFactorStatus)           // 2FA status
+		authGroup.Post("/2fa/enroll", auth.RequireAuth(authService), authHandler.HandleTwoFactorEnroll)   // Start enrollment
+		authGroup.Post("/2fa/confirm", auth.RequireAuth(authService), authHandler.HandleTwoFactorConfirm) // Confirm first code
+		authGroup.Post("/2fa/disable", auth.RequireAuth(authService), authHandler.HandleTwoFactorDisable) // Disable (password required)
+		authGroup.Post("/2fa/verify", authHandler.HandleTwoFactorVerify)                                  // Complete login challenge
+
+		// OpenID Connect single sign-on (only when configured)
+		if oidcHandler != nil {
+			authGroup.Get("/oidc/login", oidcHandler.HandleOIDCLogin)       // Redirect to identity provider
+			authGroup.Get("/oidc/callback", oidcHandler.HandleOIDCCallback) // Authorization code callback
+		}
 	}
 
 	// Chat endpoints - main functionality for RAG-based conversations (requires authentication)
 	if chatHandler != nil {
 		// Apply required auth middleware to chat endpoint - all chat requires authentication
-		api.Post("/chat", auth.RequireAuth(authService), chatHandler.HandleChat) // Process chat messages through RAG service
+		// Unverified users are rejected when EMAIL_VERIFICATION_ALLOW_UNVERIFIED_CHAT is false
+		api.Post("/chat", auth.RequireAuth(authService), verified, chatHandler.HandleChat) // Process chat messages through RAG service
 	}
 
 	// Conversation endpoints - chat history management (requires authentication)
 	if conversationHandler != nil {
 		convGroup := api.Group("/conversations", auth.RequireAuth(authService))
-		convGroup.Get("/", conversationHandler.HandleListConversations)                   // List user's conversations
-		convGroup.Post("/", conversationHandler.HandleCreateConversation)                 // Create new conversation
-		convGroup.Get("/:id", conversationHandler.HandleGetConversation)                  // Get conversation with messages
-		convGroup.Put("/:id", conversationHandler.HandleUpdateConversation)               // Update conversation title
-		convGroup.Delete("/:id", conversationHandler.HandleDeleteConversation)            // Delete conversation
-		convGroup.Get("/:id/messages", conversationHandler.HandleGetConversationMessages) // Get conversation messages with pagination
+		convGroup.Get("/", conversationHandler.HandleListConversations)                    // List user's conversations
+		convGroup.Post("/", verified, conversationHandler.HandleCreateConversation)        // Create new conversation
+		convGroup.Get("/search", conversationHandler.HandleSearchConversations)            // Full-text search across messages
+		convGroup.Get("/export", conversationHandler.HandleExportAllConversations)         // Zip of all conversations
+		convGroup.Post("/import", verified, conversationHandler.HandleImportConversations) // Recreate conversations from JSON exports
+		convGroup.Get("/:id", conversationHandler.HandleGetConversation)                   // Get conversation with messages
+		convGroup.Put("/:id", verified, conversationHandler.HandleUpdateConversation)      // Update conversation title
+		convGroup.Patch("/:id", verified, conversationHandler.HandleOrganizeConversation)  // Pin, archive, move to folder, tag
+		convGroup.Delete("/:id", conversationHandler.HandleDeleteConversation)             // Delete conversation
+		convGroup.Get("/:id/messages", conversationHandler.HandleGetConversationMessages)  // Get conversation messages with pagination
+		convGroup.Get("/:id/export", conversationHandler.HandleExportConversation)         // Download as md, json or html
+
+		// Branching - edits and regenerations add sibling messages; history follows the active branch
+		if chatHandler != nil {
+			convGroup.Post("/:id/messages/:messageId/edit", verified, chatHandler.HandleEditMessage)             // Re-ask with new text on a new branch
+			convGroup.Post("/:id/messages/:messageId/regenerate", verified, chatHandler.HandleRegenerateMessage) // New answer on a new branch
+			convGroup.Get("/:id/messages/:messageId/branches", chatHandler.HandleListMessageBranches)            // List sibling branches
+			convGroup.Post("/:id/messages/:messageId/activate", verified, chatHandler.HandleSwitchBranch)        // Switch active branch
+		}
+
+		// Answer ratings - thumbs down also evicts the cached answer
+		convGroup.Post("/:id/messages/:messageId/feedback", verified, feedbackHandler.HandleMessageFeedback) // Rate an assistant message
+
+		// Read-only share links (owner only)
+		convGroup.Post("/:id/shares", verified, shareHandler.HandleCreateShare)  // Create share link
+		convGroup.Get("/:id/shares", shareHandler.HandleListShares)              // List share links
+		convGroup.Delete("/:id/shares/:shareId", shareHandler.HandleRevokeShare) // Revoke share link
+	}
+
+	// Folders and tags for organizing conversations (requires authentication)
+	if conversationHandler != nil {
+		folderGroup := api.Group("/folders", auth.RequireAuth(authService))
+		folderGroup.Get("/", conversationHandler.HandleListFolders)               // List folders with conversation counts
+		folderGroup.Post("/", verified, conversationHandler.HandleCreateFolder)   // Create folder
+		folderGroup.Put("/:id", verified, conversationHandler.HandleRenameFolder) // Rename folder
+		folderGroup.Delete("/:id", conversationHandler.HandleDeleteFolder)        // Delete folder (conversations become unfiled)
+
+		tagGroup := api.Group("/tags", auth.RequireAuth(authService))
+		tagGroup.Get("/", conversationHandler.HandleListTags)               // List tags with conversation counts
+		tagGroup.Post("/", verified, conversationHandler.HandleCreateTag)   // Create tag
+		tagGroup.Put("/:id", verified, conversationHandler.HandleRenameTag) // Rename tag
+		tagGroup.Delete("/:id", conversationHandler.HandleDeleteTag)        // Delete tag
 	}
 
+	// Public view of shared conversations - the share token is the credential
+	api.Get("/shared/:token", shareHandler.HandleGetSharedConversation)
+
+	// Admin endpoints - restricted to ADMIN_EMAILS
+	adminGroup := api.Group("/admin", auth.RequireAuth(authService), auth.RequireAdmin(authService))
+	adminGroup.Get("/feedback/report", feedbackHandler.HandleFeedbackReport) // Answer quality by article, cache status and day
+	adminGroup.Get("/articles/queue", healthHandler.HandleArticleQueue)      // Article tasks waiting for a worker, with queue metrics
+
 	// Article management endpoints - CRUD operations for knowledge base (requires authentication)
 	if articleHandler != nil {
 		articleGroup := api.Group("/articles", auth.RequireAuth(authService))
-		articleGroup.Post("/", articleHandler.HandleAddArticle)         // Add new article to RAG system
-		articleGroup.Get("/", articleHandler.HandleListArticles)        // List processed articles
-		articleGroup.Get("/:id", articleHandler.HandleGetArticle)       // Get specific article details
-		articleGroup.Delete("/:id", articleHandler.HandleDeleteArticle) // Remove article from system
+		articleGroup.Post("/", verified, articleHandler.HandleAddArticle)                            // Add new article to RAG system
+		articleGroup.Post("/upload", verified, articleHandler.HandleUploadArticles)                  // Add uploaded documents (txt, md, html, pdf)
+		articleGroup.Get("/", articleHandler.HandleListArticles)                                     // List processed articles
+		articleGroup.Get("/:id", articleHandler.HandleGetArticle)                                    // Get specific article details
+		articleGroup.Delete("/:id", verified, articleHandler.HandleDeleteArticle)                    // Remove article from system
+		articleGroup.Post("/:id/retry", verified, articleHandler.HandleRetryArticle)                 // Re-run ingestion of a failed article
+		articleGroup.Get("/:id/versions", articleHandler.HandleGetArticleVersions)                   // Content versions kept by refreshes
+		articleGroup.Put("/:id/refresh-interval", verified, articleHandler.HandleSetRefreshInterval) // Per-article refresh interval
+
+		// Bulk re-ingestion with progress reporting (admin only)
+		articleGroup.Post("/reindex", auth.RequireAdmin(authService), articleHandler.HandleReindexArticles)  // Re-run ingestion in the background (admin)
+		articleGroup.Get("/reindex/:id", auth.RequireAdmin(authService), articleHandler.HandleGetReindexJob) // Reindex job progress (admin)
 	}
 
+	// Feed subscriptions - entries are polled and ingested in the background (requires authentication)
+	feedGroup := api.Group("/feeds", auth.RequireAuth(authService))
+	feedGroup.Get("/", feedHandler.HandleListFeeds)                  // List subscriptions with polling state
+	feedGroup.Post("/", verified, feedHandler.HandleCreateFeed)      // Subscribe (feed is fetched once to validate)
+	feedGroup.Get("/:id", feedHandler.HandleGetFeed)                 // Get subscription
+	feedGroup.Patch("/:id", verified, feedHandler.HandleUpdateFeed)  // Change title, category, interval or enabled
+	feedGroup.Delete("/:id", verified, feedHandler.HandleDeleteFeed) // Unsubscribe (articles are kept)
+
 	// PHASE 11: GRACEFUL SHUTDOWN HANDLING
 	// Proper shutdown sequence ensures no data loss and clean resource cleanup
 	go func() {
@@ -271,7 +424,8 @@ func main() {
 
 		slog.Info("Shutting down server...")
 
-		// 1. Stop accepting new work - shutdown worker pools first
+		// 1. Stop accepting new work - stop schedulers, then shutdown worker pools
+		stopRefresh()
 		poolManager.Shutdown()
 
 		// 2. Close cache connections to prevent data corruption
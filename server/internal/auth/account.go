@@ -0,0 +1,114 @@
+package auth
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"strings"
+	"time"
+
+	"article-chat-system/server/internal/errors"
+	"article-chat-system/server/internal/mailer"
+	"article-chat-system/server/internal/models"
+	"github.com/google/uuid"
+)
+
+// DeactivateAccount disables an account without deleting its data
+// All sessions are revoked; ValidateSession and login already reject inactive users
+func (s *AuthService) DeactivateAccount(userID uuid.UUID) error {
+	if err := s.db.DeactivateUser(userID); err != nil {
+		return err
+	}
+
+	if err := s.db.DeleteUserSessions(userID); err != nil {
+		return err
+	}
+
+	auditLog(AuditAccountDeactivated, "user_id", userID)
+
+	return nil
+}
+
+// DeleteAccount permanently removes an account with its conversations, messages and sessions
+// Callers must confirm the user's identity first and purge any caches derived from the user's data
+func (s *AuthService) DeleteAccount(ctx context.Context, userID uuid.UUID) error {
+	if err := s.db.DeleteUser(ctx, userID); err != nil {
+		return err
+	}
+
+	auditLog(AuditAccountDeleted, "user_id", userID)
+
+	return nil
+}
+
+// RequestEmailChange emails a single-use confirmation link to the new address
+// The account keeps its current email until the link is followed
+func (s *AuthService) RequestEmailChange(ctx context.Context, user *models.User, change *models.EmailChange) error {
+	if err := s.ConfirmIdentity(user, change.Password); err != nil {
+		return err
+	}
+
+	newEmail := strings.TrimSpace(strings.ToLower(change.NewEmail))
+	if newEmail == strings.ToLower(user.Email) {
+		return errors.New(errors.ErrValidationFailed, "New email must differ from the current email")
+	}
+
+	exists, err := s.db.CheckEmailExists(newEmail)
+	if err != nil {
+		return err
+	}
+	if exists {
+		return errors.New(errors.ErrValidationFailed, "Email already exists")
+	}
+
+	token, err := GenerateSessionToken()
+	if err != nil {
+		return err
+	}
+
+	ttl := time.Duration(s.cfg.EmailTokenTTL) * time.Second
+	if err := s.db.CreateEmailChangeRequest(user.ID, newEmail, HashToken(token), ttl); err != nil {
+		return err
+	}
+
+	link := fmt.Sprintf("%s/confirm-email-change?token=%s", strings.TrimRight(s.mailCfg.AppURL, "/"), url.QueryEscape(token))
+	err = s.mailer.Send(ctx, mailer.Message{
+		To:      newEmail,
+		Subject: "Confirm your new email address",
+		Body: fmt.Sprintf("Follow this link to start using %s for your account:\n\n%s\n\nThe link expires in %s. If you did not request this change, ignore this email.",
+			newEmail, link, ttl),
+	})
+	if err != nil {
+		return errors.Wrap(err, errors.ErrInternalServer)
+	}
+
+	// Let the current address know, so an unexpected change can be noticed
+	if err := s.mailer.Send(ctx, mailer.Message{
+		To:      user.Email,
+		Subject: "Email change requested",
+		Body:    fmt.Sprintf("A request was made to change your account email to %s. If this was not you, change your password.", newEmail),
+	}); err != nil {
+		slog.Warn("Failed to send email change notice", "user_id", user.ID, "error", err)
+	}
+
+	auditLog(AuditEmailChangeRequested, "user_id", user.ID)
+
+	return nil
+}
+
+// ConfirmEmailChange applies a pending email change from its emailed token
+func (s *AuthService) ConfirmEmailChange(token string) error {
+	if token == "" {
+		return errors.New(errors.ErrMissingRequiredField, "Token is required")
+	}
+
+	userID, _, err := s.db.ConfirmEmailChange(HashToken(token))
+	if err != nil {
+		return err
+	}
+
+	auditLog(AuditEmailChanged, "user_id", userID)
+
+	return nil
+}
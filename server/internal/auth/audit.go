@@ -0,0 +1,41 @@
+package auth
+
+import (
+	"log/slog"
+)
+
+// Audit event names emitted by the authentication subsystem
+const (
+	AuditLoginSucceeded = "login_succeeded"
+	AuditLoginFailed    = "login_failed"
+	AuditLoginThrottled = "login_throttled"
+	AuditAccountLocked  = "account_locked"
+	AuditIPLocked       = "ip_locked"
+
+	AuditTwoFactorEnrolled   = "two_factor_enrolled"
+	AuditTwoFactorDisabled   = "two_factor_disabled"
+	AuditTwoFactorFailed     = "two_factor_failed"
+	AuditRecoveryCodeUsed    = "recovery_code_used"
+	AuditChallengeExhausted  = "two_factor_challenge_exhausted"
+	AuditPasswordConfirmFail = "password_confirmation_failed"
+
+	AuditSSOLogin           = "sso_login"
+	AuditSSOLinked          = "sso_identity_linked"
+	AuditSSOSignup          = "sso_signup"
+	AuditSSORejected        = "sso_rejected"
+	AuditSSOReauthenticated = "sso_reauthenticated"
+
+	AuditAccountDeactivated   = "account_deactivated"
+	AuditAccountDeleted       = "account_deleted"
+	AuditEmailChangeRequested = "email_change_requested"
+	AuditEmailChanged         = "email_changed"
+	AuditVerificationSent     = "email_verification_sent"
+	AuditEmailVerified        = "email_verified"
+)
+
+// auditLog writes a security audit entry
+// Entries share the "audit" attribute so they can be filtered out of the regular log stream
+func auditLog(event string, args ...any) {
+	attrs := append([]any{"audit", true, "event", event}, args...)
+	slog.Info("Security audit event", attrs...)
+}
@@ -1,6 +1,7 @@
 package auth
 
 import (
+	"context"
 	"crypto/rand"
 	"crypto/sha256"
 	"encoding/base64"
@@ -8,8 +9,10 @@ import (
 	"strings"
 	"time"
 
+	"article-chat-system/server/internal/config"
 	"article-chat-system/server/internal/database"
 	"article-chat-system/server/internal/errors"
+	"article-chat-system/server/internal/mailer"
 	"article-chat-system/server/internal/models"
 	"github.com/google/uuid"
 	"golang.org/x/crypto/bcrypt"
@@ -17,13 +20,30 @@ import (
 
 // AuthService handles authentication operations
 type AuthService struct {
-	db *database.DB
+	db      *database.DB
+	limiter *LoginLimiter
+	mailer  mailer.Mailer
+	cfg     config.AuthConfig
+	mailCfg config.MailConfig
+}
+
+// LoginResult is the outcome of a successful password check
+// Token is set when a session was issued; Challenge is set when a second factor is still required
+type LoginResult struct {
+	User      *models.User
+	Token     string
+	Challenge *models.TwoFactorChallengeResponse
 }
 
 // NewAuthService creates a new authentication service
-func NewAuthService(db *database.DB) *AuthService {
+// limiter may be nil to disable brute-force protection
+func NewAuthService(db *database.DB, limiter *LoginLimiter, m mailer.Mailer, cfg config.AuthConfig, mailCfg config.MailConfig) *AuthService {
 	return &AuthService{
-		db: db,
+		db:      db,
+		limiter: limiter,
+		mailer:  m,
+		cfg:     cfg,
+		mailCfg: mailCfg,
 	}
 }
 
@@ -32,6 +52,21 @@ func (s *AuthService) GetDB() *database.DB {
 	return s.db
 }
 
+// IsAdmin reports whether the user is listed in ADMIN_EMAILS
+// When email verification is on, the address must also be verified
+func (s *AuthService) IsAdmin(user *models.User) bool {
+	if s.cfg.EmailVerification.Enabled && !user.EmailVerified {
+		return false
+	}
+
+	for _, email := range strings.Split(s.cfg.AdminEmails, ",") {
+		if email = strings.TrimSpace(email); email != "" && strings.EqualFold(email, user.Email) {
+			return true
+		}
+	}
+	return false
+}
+
 // HashPassword hashes a plain text password using bcrypt
 func HashPassword(password string) (string, error) {
 	bytes, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
@@ -92,40 +127,74 @@ func (s *AuthService) SignupUser(signup *models.UserSignup) (*models.User, error
 }
 
 // LoginUser authenticates a user and creates a session
-func (s *AuthService) LoginUser(credentials *models.UserCredentials, userAgent, ipAddress string) (*models.User, string, error) {
+// Users with two-factor authentication enabled receive a challenge instead of a session
+func (s *AuthService) LoginUser(credentials *models.UserCredentials, userAgent, ipAddress string) (*LoginResult, error) {
 	// Normalize email
 	credentials.Email = strings.TrimSpace(strings.ToLower(credentials.Email))
 
+	// Reject locked or throttled emails/IPs before touching the password hash
+	ctx := context.Background()
+	if err := s.limiter.Check(ctx, credentials.Email, ipAddress); err != nil {
+		return nil, err
+	}
+
 	// Get user ID and password hash
 	userID, passwordHash, err := s.db.GetUserPasswordHash(credentials.Email)
 	if err != nil {
-		return nil, "", err
+		if appErr, ok := errors.IsAppError(err); ok && appErr.Code == errors.ErrUnauthorized {
+			return nil, s.loginFailed(ctx, credentials.Email, ipAddress)
+		}
+		return nil, err
 	}
 
 	// Verify password
 	if !CheckPasswordHash(credentials.Password, passwordHash) {
-		return nil, "", errors.New(errors.ErrUnauthorized, "Invalid credentials")
+		return nil, s.loginFailed(ctx, credentials.Email, ipAddress)
 	}
 
 	// Get user details
 	user, err := s.db.GetUserByID(userID)
 	if err != nil {
-		return nil, "", err
+		return nil, err
+	}
+
+	// Second factor required: hand out a short-lived challenge instead of a session
+	// The failure history is kept until the code is verified, so wrong codes still count towards the lockout
+	if user.TwoFactorEnabled {
+		challenge, err := s.createTwoFactorChallenge(user, userAgent, ipAddress, false)
+		if err != nil {
+			return nil, err
+		}
+		return &LoginResult{User: user, Challenge: challenge}, nil
 	}
 
+	// Successful login clears the email's failure history
+	s.limiter.Reset(ctx, credentials.Email)
+
+	token, err := s.issueSession(user, userAgent, ipAddress, false)
+	if err != nil {
+		return nil, err
+	}
+
+	return &LoginResult{User: user, Token: token}, nil
+}
+
+// issueSession creates a session for a fully authenticated user and returns the raw token
+// viaSSO marks sessions from an SSO login, which may confirm sensitive changes for a short while
+func (s *AuthService) issueSession(user *models.User, userAgent, ipAddress string, viaSSO bool) (string, error) {
 	// Generate session token
 	token, err := GenerateSessionToken()
 	if err != nil {
-		return nil, "", err
+		return "", err
 	}
 
 	// Hash token for storage
 	tokenHash := HashToken(token)
 
 	// Create session
-	_, err = s.db.CreateSession(user.ID, tokenHash, userAgent, ipAddress)
+	_, err = s.db.CreateSession(user.ID, tokenHash, userAgent, ipAddress, viaSSO)
 	if err != nil {
-		return nil, "", err
+		return "", err
 	}
 
 	// Update last login
@@ -134,7 +203,18 @@ func (s *AuthService) LoginUser(credentials *models.UserCredentials, userAgent,
 		// This is a non-critical operation
 	}
 
-	return user, token, nil
+	auditLog(AuditLoginSucceeded, "user_id", user.ID, "ip", ipAddress)
+
+	return token, nil
+}
+
+// loginFailed records a failed attempt and returns the error to show the client
+// Unknown emails and wrong passwords look identical to avoid account enumeration
+func (s *AuthService) loginFailed(ctx context.Context, email, ipAddress string) error {
+	if err := s.limiter.RecordFailure(ctx, email, ipAddress); err != nil {
+		return err
+	}
+	return errors.New(errors.ErrUnauthorized, "Invalid credentials")
 }
 
 // LogoutUser deletes a user's session
@@ -176,6 +256,11 @@ func (s *AuthService) ValidateSession(token string) (*models.User, error) {
 		return nil, errors.New(errors.ErrForbidden, "Account deactivated")
 	}
 
+	// SSO sessions can stand in for the password right after login (see ConfirmIdentity)
+	if session.ViaSSO {
+		user.SSOLoginAt = &session.CreatedAt
+	}
+
 	// Optionally extend session on activity
 	// This keeps active users logged in
 	if time.Until(session.ExpiresAt) < 12*time.Hour {
@@ -198,12 +283,14 @@ func (s *AuthService) GetUserProfile(userID uuid.UUID) (*models.UserProfile, err
 	}
 
 	profile := &models.UserProfile{
-		ID:        user.ID,
-		Email:     user.Email,
-		FullName:  user.FullName,
-		CreatedAt: user.CreatedAt,
-		UpdatedAt: user.UpdatedAt,
-		LastLogin: user.LastLogin,
+		ID:               user.ID,
+		Email:            user.Email,
+		FullName:         user.FullName,
+		CreatedAt:        user.CreatedAt,
+		UpdatedAt:        user.UpdatedAt,
+		LastLogin:        user.LastLogin,
+		TwoFactorEnabled: user.TwoFactorEnabled,
+		EmailVerified:    user.EmailVerified,
 	}
 
 	return profile, nil
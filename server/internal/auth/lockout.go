@@ -0,0 +1,234 @@
+package auth
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"article-chat-system/server/internal/config"
+	"article-chat-system/server/internal/errors"
+	"article-chat-system/server/internal/services"
+)
+
+// LoginLimiter tracks failed login attempts per email and per client IP
+// Records live in the shared cache (Redis, or memory fallback) so they expire on their own
+//
+// PROTECTION STRATEGY:
+// - The first FreeAttempts failures for an email are not penalized
+// - Every further failure doubles the wait before the next attempt (BaseDelay..MaxDelay)
+// - MaxAttemptsPerEmail failures lock the account for LockoutDuration
+// - MaxAttemptsPerIP failures lock the client IP for LockoutDuration
+type LoginLimiter struct {
+	cache services.CacheService
+	cfg   config.LoginProtectionConfig
+}
+
+// attemptRecord is the cached failure state for one email or IP
+// Failures and LockedUntil live under their own keys: the counter is incremented atomically and the
+// lock is only ever set, so parallel guesses can't overwrite each other's progress
+type attemptRecord struct {
+	Failures      int       `json:"-"`
+	NextAllowedAt time.Time `json:"next_allowed_at"`
+	LockedUntil   time.Time `json:"-"`
+}
+
+// NewLoginLimiter creates a new login limiter backed by the given cache
+func NewLoginLimiter(cache services.CacheService, cfg config.LoginProtectionConfig) *LoginLimiter {
+	return &LoginLimiter{
+		cache: cache,
+		cfg:   cfg,
+	}
+}
+
+// Check returns an error if the email or IP is currently locked or throttled
+func (l *LoginLimiter) Check(ctx context.Context, email, ipAddress string) error {
+	if l == nil || !l.cfg.Enabled {
+		return nil
+	}
+
+	now := time.Now()
+
+	emailRecord := l.load(ctx, emailAttemptKey(email))
+	if emailRecord.LockedUntil.After(now) {
+		auditLog(AuditLoginThrottled, "email", email, "ip", ipAddress, "reason", "account_locked")
+		return accountLockedError(emailRecord.LockedUntil.Sub(now))
+	}
+
+	ipRecord := l.load(ctx, ipAttemptKey(ipAddress))
+	if ipRecord.LockedUntil.After(now) {
+		auditLog(AuditLoginThrottled, "email", email, "ip", ipAddress, "reason", "ip_locked")
+		return ipLockedError(ipRecord.LockedUntil.Sub(now))
+	}
+
+	if emailRecord.NextAllowedAt.After(now) {
+		wait := emailRecord.NextAllowedAt.Sub(now)
+		auditLog(AuditLoginThrottled, "email", email, "ip", ipAddress, "reason", "progressive_delay", "wait", wait)
+		return errors.New(errors.ErrRateLimitExceeded, "Too many failed login attempts, please wait before retrying").
+			WithRetryAfter(wait)
+	}
+
+	return nil
+}
+
+// RecordFailure registers a failed login and returns a lockout error if a threshold was reached
+func (l *LoginLimiter) RecordFailure(ctx context.Context, email, ipAddress string) error {
+	if l == nil || !l.cfg.Enabled {
+		return nil
+	}
+
+	now := time.Now()
+
+	emailRecord := l.registerFailure(ctx, emailAttemptKey(email), l.cfg.MaxAttemptsPerEmail, true, now)
+	ipRecord := l.registerFailure(ctx, ipAttemptKey(ipAddress), l.cfg.MaxAttemptsPerIP, false, now)
+
+	auditLog(AuditLoginFailed, "email", email, "ip", ipAddress, "email_failures", emailRecord.Failures, "ip_failures", ipRecord.Failures)
+
+	if emailRecord.LockedUntil.After(now) {
+		auditLog(AuditAccountLocked, "email", email, "ip", ipAddress, "locked_until", emailRecord.LockedUntil)
+		return accountLockedError(emailRecord.LockedUntil.Sub(now))
+	}
+	if ipRecord.LockedUntil.After(now) {
+		auditLog(AuditIPLocked, "email", email, "ip", ipAddress, "locked_until", ipRecord.LockedUntil)
+		return ipLockedError(ipRecord.LockedUntil.Sub(now))
+	}
+
+	return nil
+}
+
+// Reset clears the failure history for an email after a successful login
+// The IP record is kept so one valid account can't be used to reset an IP under attack
+func (l *LoginLimiter) Reset(ctx context.Context, email string) {
+	if l == nil || !l.cfg.Enabled {
+		return
+	}
+
+	key := emailAttemptKey(email)
+	for _, k := range []string{key, failureCountKey(key), lockKey(key)} {
+		if err := l.cache.Delete(ctx, k); err != nil {
+			slog.Warn("Failed to reset login attempts", "error", err)
+		}
+	}
+}
+
+// registerFailure increments the failure counter for a key and applies delays and lockouts
+func (l *LoginLimiter) registerFailure(ctx context.Context, key string, maxAttempts int, progressive bool, now time.Time) attemptRecord {
+	failures, err := l.cache.Increment(ctx, failureCountKey(key), l.ttl())
+	if err != nil {
+		// Non-blocking: a cache failure must not turn into a login failure
+		slog.Warn("Failed to count login attempt", "error", err)
+		return attemptRecord{}
+	}
+
+	record := l.load(ctx, key)
+	record.Failures = int(failures)
+
+	if maxAttempts > 0 && record.Failures >= maxAttempts {
+		// Lock and start counting again once the lockout expires
+		lockout := l.seconds(l.cfg.LockoutDuration)
+		record.LockedUntil = now.Add(lockout)
+		if lockout > 0 {
+			if err := l.cache.Set(ctx, lockKey(key), record.LockedUntil, lockout); err != nil {
+				slog.Warn("Failed to store login lockout", "error", err)
+			}
+		}
+		if err := l.cache.Delete(ctx, failureCountKey(key)); err != nil {
+			slog.Warn("Failed to reset login attempts", "error", err)
+		}
+		return record
+	}
+
+	if progressive {
+		record.NextAllowedAt = now.Add(l.progressiveDelay(record.Failures))
+		l.save(ctx, key, record)
+	}
+
+	return record
+}
+
+// progressiveDelay doubles the delay for each failure beyond the free attempts
+func (l *LoginLimiter) progressiveDelay(failures int) time.Duration {
+	excess := failures - l.cfg.FreeAttempts
+	if excess <= 0 || l.cfg.BaseDelay <= 0 {
+		return 0
+	}
+
+	delay := l.seconds(l.cfg.BaseDelay)
+	maxDelay := l.seconds(l.cfg.MaxDelay)
+	for i := 1; i < excess && delay < maxDelay; i++ {
+		delay *= 2
+	}
+	if maxDelay > 0 && delay > maxDelay {
+		delay = maxDelay
+	}
+	return delay
+}
+
+// load reads an attempt record and its lock, treating cache misses and errors as a clean record
+func (l *LoginLimiter) load(ctx context.Context, key string) attemptRecord {
+	var record attemptRecord
+	if err := l.cache.Get(ctx, key, &record); err != nil {
+		record = attemptRecord{}
+	}
+	if err := l.cache.Get(ctx, lockKey(key), &record.LockedUntil); err != nil {
+		record.LockedUntil = time.Time{}
+	}
+	return record
+}
+
+// save stores an attempt record for as long as it can still matter
+func (l *LoginLimiter) save(ctx context.Context, key string, record attemptRecord) {
+	// Non-blocking: a cache failure must not turn into a login failure
+	if err := l.cache.Set(ctx, key, record, l.ttl()); err != nil {
+		slog.Warn("Failed to store login attempts", "error", err)
+	}
+}
+
+// ttl is how long attempt state is kept: the attempt window or the lockout, whichever is longer
+func (l *LoginLimiter) ttl() time.Duration {
+	ttl := l.seconds(l.cfg.AttemptWindow)
+	if lockout := l.seconds(l.cfg.LockoutDuration); lockout > ttl {
+		ttl = lockout
+	}
+	if ttl <= 0 {
+		ttl = 15 * time.Minute
+	}
+	return ttl
+}
+
+func (l *LoginLimiter) seconds(n int) time.Duration {
+	return time.Duration(n) * time.Second
+}
+
+// emailAttemptKey hashes the email so addresses don't appear in cache keys
+func emailAttemptKey(email string) string {
+	return "login_attempts:email:" + HashToken(email)[:16]
+}
+
+func ipAttemptKey(ipAddress string) string {
+	return "login_attempts:ip:" + ipAddress
+}
+
+// failureCountKey is the atomic failure counter that belongs to an attempt record
+func failureCountKey(key string) string {
+	return key + ":failures"
+}
+
+// lockKey holds the lockout end time that belongs to an attempt record
+func lockKey(key string) string {
+	return key + ":locked"
+}
+
+func accountLockedError(retryAfter time.Duration) error {
+	return errors.New(
+		errors.ErrAccountLocked,
+		fmt.Sprintf("Account temporarily locked due to too many failed login attempts. Try again in %s", retryAfter.Round(time.Second)),
+	).WithRetryAfter(retryAfter)
+}
+
+func ipLockedError(retryAfter time.Duration) error {
+	return errors.New(
+		errors.ErrRateLimitExceeded,
+		"Too many failed login attempts from this address",
+	).WithRetryAfter(retryAfter)
+}
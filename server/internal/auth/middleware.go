@@ -35,6 +35,44 @@ func RequireAuth(authService *AuthService) fiber.Handler {
 	}
 }
 
+// RequireVerifiedEmail blocks users who have not verified their email yet
+// Must run after RequireAuth; it is a no-op unless verification gates chat and other writes
+func RequireVerifiedEmail(authService *AuthService) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if !authService.ChatRequiresVerifiedEmail() {
+			return c.Next()
+		}
+
+		user, err := GetUserFromContext(c)
+		if err != nil {
+			return err
+		}
+
+		if !user.EmailVerified {
+			return errors.New(errors.ErrForbidden, "Please verify your email address to continue")
+		}
+
+		return c.Next()
+	}
+}
+
+// RequireAdmin blocks users who are not listed in ADMIN_EMAILS
+// Must run after RequireAuth
+func RequireAdmin(authService *AuthService) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		user, err := GetUserFromContext(c)
+		if err != nil {
+			return err
+		}
+
+		if !authService.IsAdmin(user) {
+			return errors.New(errors.ErrForbidden, "Admin access required")
+		}
+
+		return c.Next()
+	}
+}
+
 // OptionalAuth has been removed - all endpoints now require authentication except signup/login
 
 // GetUserFromContext retrieves the authenticated user from the fiber context
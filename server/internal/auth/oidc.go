@@ -0,0 +1,554 @@
+package auth
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"hash"
+	"log/slog"
+	"math/big"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"article-chat-system/server/internal/config"
+	"article-chat-system/server/internal/errors"
+	"article-chat-system/server/internal/services"
+
+	"github.com/go-resty/resty/v2"
+)
+
+const (
+	OIDCStateTTL        = 10 * time.Minute // How long a login may take at the provider
+	oidcDiscoveryTTL    = time.Hour        // How long discovery documents and keys are reused
+	oidcJWKSMinInterval = time.Minute      // Minimum time between JWKS refreshes for unknown key IDs
+	oidcClockSkew       = 2 * time.Minute  // Tolerated clock difference for exp/iat checks
+)
+
+// OIDCProvider implements the OpenID Connect authorization code flow with PKCE
+// Discovery documents and signing keys are fetched lazily and cached in memory;
+// per-login state (nonce, PKCE verifier) lives in the shared cache
+type OIDCProvider struct {
+	cfg    config.OIDCConfig
+	cache  services.CacheService
+	client *resty.Client
+
+	mu            sync.RWMutex
+	discovery     *oidcDiscovery
+	discoveredAt  time.Time
+	keys          map[string]crypto.PublicKey
+	keysFetchedAt time.Time
+}
+
+// OIDCIdentity is the verified identity extracted from an ID token
+type OIDCIdentity struct {
+	Provider      string
+	Subject       string
+	Email         string
+	EmailVerified bool
+	Name          string
+}
+
+// oidcDiscovery holds the fields we use from /.well-known/openid-configuration
+type oidcDiscovery struct {
+	Issuer                string   `json:"issuer"`
+	AuthorizationEndpoint string   `json:"authorization_endpoint"`
+	TokenEndpoint         string   `json:"token_endpoint"`
+	JWKSURI               string   `json:"jwks_uri"`
+	TokenAuthMethods      []string `json:"token_endpoint_auth_methods_supported"`
+}
+
+// oidcLoginState is stored between the authorization redirect and the callback
+type oidcLoginState struct {
+	Nonce        string `json:"nonce"`
+	CodeVerifier string `json:"code_verifier"`
+}
+
+type oidcTokenResponse struct {
+	AccessToken      string `json:"access_token"`
+	IDToken          string `json:"id_token"`
+	TokenType        string `json:"token_type"`
+	Error            string `json:"error"`
+	ErrorDescription string `json:"error_description"`
+}
+
+type jsonWebKey struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+type idTokenHeader struct {
+	Alg string `json:"alg"`
+	Kid string `json:"kid"`
+}
+
+type idTokenClaims struct {
+	Issuer        string          `json:"iss"`
+	Subject       string          `json:"sub"`
+	Audience      json.RawMessage `json:"aud"`
+	AuthorizedBy  string          `json:"azp"`
+	Expiry        int64           `json:"exp"`
+	IssuedAt      int64           `json:"iat"`
+	Nonce         string          `json:"nonce"`
+	Email         string          `json:"email"`
+	EmailVerified interface{}     `json:"email_verified"` // Some providers send "true" as a string
+	Name          string          `json:"name"`
+}
+
+// NewOIDCProvider creates a new OpenID Connect provider client
+func NewOIDCProvider(cfg config.OIDCConfig, cache services.CacheService) *OIDCProvider {
+	client := resty.New()
+	client.SetTimeout(15 * time.Second)
+	client.SetRetryCount(2)
+	client.SetRetryWaitTime(500 * time.Millisecond)
+	client.SetHeader("Accept", "application/json")
+
+	return &OIDCProvider{
+		cfg:    cfg,
+		cache:  cache,
+		client: client,
+	}
+}
+
+// Name returns the provider name stored with linked identities
+func (p *OIDCProvider) Name() string {
+	return p.cfg.ProviderName
+}
+
+// AuthorizationURL starts a login: it stores state, nonce and PKCE verifier and
+// returns the provider URL the browser should be redirected to, with the state
+// that the caller binds to the browser
+func (p *OIDCProvider) AuthorizationURL(ctx context.Context) (string, string, error) {
+	discovery, err := p.getDiscovery(ctx)
+	if err != nil {
+		return "", "", err
+	}
+
+	state, err := randomURLToken(32)
+	if err != nil {
+		return "", "", err
+	}
+	nonce, err := randomURLToken(32)
+	if err != nil {
+		return "", "", err
+	}
+	verifier, err := randomURLToken(48)
+	if err != nil {
+		return "", "", err
+	}
+
+	loginState := oidcLoginState{Nonce: nonce, CodeVerifier: verifier}
+	if err := p.cache.Set(ctx, oidcStateKey(state), loginState, OIDCStateTTL); err != nil {
+		return "", "", errors.Wrap(err, errors.ErrCacheError)
+	}
+
+	challenge := sha256.Sum256([]byte(verifier))
+
+	params := url.Values{}
+	params.Set("response_type", "code")
+	params.Set("client_id", p.cfg.ClientID)
+	params.Set("redirect_uri", p.cfg.RedirectURL)
+	params.Set("scope", p.cfg.Scopes)
+	params.Set("state", state)
+	params.Set("nonce", nonce)
+	params.Set("code_challenge", base64.RawURLEncoding.EncodeToString(challenge[:]))
+	params.Set("code_challenge_method", "S256")
+
+	separator := "?"
+	if strings.Contains(discovery.AuthorizationEndpoint, "?") {
+		separator = "&"
+	}
+
+	return discovery.AuthorizationEndpoint + separator + params.Encode(), state, nil
+}
+
+// Exchange completes a login: it validates state, redeems the code with the PKCE verifier
+// and verifies the returned ID token
+func (p *OIDCProvider) Exchange(ctx context.Context, code, state string) (*OIDCIdentity, error) {
+	if code == "" || state == "" {
+		return nil, errors.New(errors.ErrBadRequest, "Missing code or state")
+	}
+
+	// State is single-use: delete it before anything else can fail
+	var loginState oidcLoginState
+	if err := p.cache.Get(ctx, oidcStateKey(state), &loginState); err != nil {
+		return nil, errors.New(errors.ErrUnauthorized, "Invalid or expired login state")
+	}
+	p.cache.Delete(ctx, oidcStateKey(state))
+
+	discovery, err := p.getDiscovery(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	form := map[string]string{
+		"grant_type":    "authorization_code",
+		"code":          code,
+		"redirect_uri":  p.cfg.RedirectURL,
+		"client_id":     p.cfg.ClientID,
+		"code_verifier": loginState.CodeVerifier,
+	}
+
+	request := p.client.R().
+		SetContext(ctx).
+		SetResult(&oidcTokenResponse{}).
+		SetError(&oidcTokenResponse{})
+
+	if p.cfg.ClientSecret != "" {
+		if p.supportsAuthMethod(discovery, "client_secret_post") && !p.supportsAuthMethod(discovery, "client_secret_basic") {
+			form["client_secret"] = p.cfg.ClientSecret
+		} else {
+			request = request.SetBasicAuth(url.QueryEscape(p.cfg.ClientID), url.QueryEscape(p.cfg.ClientSecret))
+		}
+	}
+
+	resp, err := request.SetFormData(form).Post(discovery.TokenEndpoint)
+	if err != nil {
+		return nil, errors.New(errors.ErrServiceUnavailable, fmt.Sprintf("Identity provider token request failed: %v", err))
+	}
+	if resp.IsError() {
+		tokenErr, _ := resp.Error().(*oidcTokenResponse)
+		slog.Warn("OIDC token exchange rejected", "status", resp.StatusCode(), "error", tokenErr)
+		return nil, errors.New(errors.ErrUnauthorized, "Identity provider rejected the authorization code")
+	}
+
+	tokens := resp.Result().(*oidcTokenResponse)
+	if tokens.IDToken == "" {
+		return nil, errors.New(errors.ErrUnauthorized, "Identity provider did not return an ID token")
+	}
+
+	claims, err := p.verifyIDToken(ctx, discovery, tokens.IDToken, loginState.Nonce)
+	if err != nil {
+		return nil, err
+	}
+
+	return &OIDCIdentity{
+		Provider:      p.cfg.ProviderName,
+		Subject:       claims.Subject,
+		Email:         strings.TrimSpace(strings.ToLower(claims.Email)),
+		EmailVerified: isTruthy(claims.EmailVerified),
+		Name:          claims.Name,
+	}, nil
+}
+
+// verifyIDToken checks the JWS signature against the provider JWKS and validates the claims
+func (p *OIDCProvider) verifyIDToken(ctx context.Context, discovery *oidcDiscovery, rawToken, expectedNonce string) (*idTokenClaims, error) {
+	invalid := func(reason string) error {
+		slog.Warn("Rejected OIDC ID token", "reason", reason)
+		return errors.New(errors.ErrUnauthorized, "Invalid ID token")
+	}
+
+	parts := strings.Split(rawToken, ".")
+	if len(parts) != 3 {
+		return nil, invalid("malformed token")
+	}
+
+	var header idTokenHeader
+	if err := decodeJWTSegment(parts[0], &header); err != nil {
+		return nil, invalid("malformed header")
+	}
+
+	key, err := p.getKey(ctx, discovery, header.Kid)
+	if err != nil {
+		return nil, err
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, invalid("malformed signature")
+	}
+	if err := verifyJWSSignature(header.Alg, key, []byte(parts[0]+"."+parts[1]), signature); err != nil {
+		return nil, invalid(err.Error())
+	}
+
+	var claims idTokenClaims
+	if err := decodeJWTSegment(parts[1], &claims); err != nil {
+		return nil, invalid("malformed claims")
+	}
+
+	now := time.Now()
+	if claims.Issuer != discovery.Issuer {
+		return nil, invalid("issuer mismatch")
+	}
+	audiences := parseAudience(claims.Audience)
+	if !containsString(audiences, p.cfg.ClientID) {
+		return nil, invalid("audience mismatch")
+	}
+	if len(audiences) > 1 && claims.AuthorizedBy != "" && claims.AuthorizedBy != p.cfg.ClientID {
+		return nil, invalid("authorized party mismatch")
+	}
+	if claims.Expiry == 0 || now.After(time.Unix(claims.Expiry, 0).Add(oidcClockSkew)) {
+		return nil, invalid("token expired")
+	}
+	if claims.IssuedAt != 0 && time.Unix(claims.IssuedAt, 0).After(now.Add(oidcClockSkew)) {
+		return nil, invalid("token issued in the future")
+	}
+	if claims.Nonce != expectedNonce {
+		return nil, invalid("nonce mismatch")
+	}
+	if claims.Subject == "" {
+		return nil, invalid("missing subject")
+	}
+
+	return &claims, nil
+}
+
+// getDiscovery returns the cached discovery document, fetching it when stale
+func (p *OIDCProvider) getDiscovery(ctx context.Context) (*oidcDiscovery, error) {
+	p.mu.RLock()
+	if p.discovery != nil && time.Since(p.discoveredAt) < oidcDiscoveryTTL {
+		discovery := p.discovery
+		p.mu.RUnlock()
+		return discovery, nil
+	}
+	p.mu.RUnlock()
+
+	discoveryURL := strings.TrimSuffix(p.cfg.IssuerURL, "/") + "/.well-known/openid-configuration"
+	resp, err := p.client.R().
+		SetContext(ctx).
+		SetResult(&oidcDiscovery{}).
+		Get(discoveryURL)
+	if err != nil {
+		return nil, errors.New(errors.ErrServiceUnavailable, fmt.Sprintf("Failed to fetch OIDC discovery document: %v", err))
+	}
+	if resp.IsError() {
+		return nil, errors.New(errors.ErrServiceUnavailable, fmt.Sprintf("OIDC discovery returned status %d", resp.StatusCode()))
+	}
+
+	discovery := resp.Result().(*oidcDiscovery)
+	if strings.TrimSuffix(discovery.Issuer, "/") != strings.TrimSuffix(p.cfg.IssuerURL, "/") {
+		return nil, errors.New(errors.ErrInvalidConfiguration, "OIDC discovery issuer does not match the configured issuer")
+	}
+	if discovery.AuthorizationEndpoint == "" || discovery.TokenEndpoint == "" || discovery.JWKSURI == "" {
+		return nil, errors.New(errors.ErrInvalidConfiguration, "OIDC discovery document is incomplete")
+	}
+
+	p.mu.Lock()
+	p.discovery = discovery
+	p.discoveredAt = time.Now()
+	p.mu.Unlock()
+
+	return discovery, nil
+}
+
+// getKey returns the signing key for a key ID, refreshing the JWKS for unknown IDs (key rotation)
+func (p *OIDCProvider) getKey(ctx context.Context, discovery *oidcDiscovery, kid string) (crypto.PublicKey, error) {
+	p.mu.RLock()
+	key, ok := p.lookupKey(kid)
+	fresh := time.Since(p.keysFetchedAt) < oidcDiscoveryTTL
+	canRefresh := time.Since(p.keysFetchedAt) >= oidcJWKSMinInterval
+	p.mu.RUnlock()
+
+	if ok && fresh {
+		return key, nil
+	}
+	if !ok && !canRefresh {
+		return nil, errors.New(errors.ErrUnauthorized, "Unknown ID token signing key")
+	}
+
+	var jwks struct {
+		Keys []jsonWebKey `json:"keys"`
+	}
+	resp, err := p.client.R().SetContext(ctx).SetResult(&jwks).Get(discovery.JWKSURI)
+	if err == nil && resp.IsError() {
+		err = fmt.Errorf("status %d", resp.StatusCode())
+	}
+	if err != nil {
+		if ok {
+			// Provider briefly unreachable: keep using the known key
+			slog.Warn("Failed to refresh OIDC signing keys, using cached key", "error", err)
+			return key, nil
+		}
+		return nil, errors.New(errors.ErrServiceUnavailable, fmt.Sprintf("Failed to fetch OIDC signing keys: %v", err))
+	}
+
+	keys := make(map[string]crypto.PublicKey)
+	for _, jwk := range jwks.Keys {
+		if jwk.Use != "" && jwk.Use != "sig" {
+			continue
+		}
+		parsed, err := parseJWK(jwk)
+		if err != nil {
+			slog.Warn("Skipping unsupported OIDC signing key", "kid", jwk.Kid, "error", err)
+			continue
+		}
+		keys[jwk.Kid] = parsed
+	}
+
+	p.mu.Lock()
+	p.keys = keys
+	p.keysFetchedAt = time.Now()
+	key, ok = p.lookupKey(kid)
+	p.mu.Unlock()
+
+	if !ok {
+		return nil, errors.New(errors.ErrUnauthorized, "Unknown ID token signing key")
+	}
+	return key, nil
+}
+
+// lookupKey finds a key by ID; tokens without a kid are accepted when the JWKS has a single key
+// Callers must hold p.mu
+func (p *OIDCProvider) lookupKey(kid string) (crypto.PublicKey, bool) {
+	if kid == "" && len(p.keys) == 1 {
+		for _, key := range p.keys {
+			return key, true
+		}
+	}
+	key, ok := p.keys[kid]
+	return key, ok
+}
+
+func (p *OIDCProvider) supportsAuthMethod(discovery *oidcDiscovery, method string) bool {
+	return containsString(discovery.TokenAuthMethods, method)
+}
+
+// parseJWK converts an RSA or EC JSON Web Key into a public key
+func parseJWK(jwk jsonWebKey) (crypto.PublicKey, error) {
+	switch jwk.Kty {
+	case "RSA":
+		n, err := base64.RawURLEncoding.DecodeString(jwk.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := base64.RawURLEncoding.DecodeString(jwk.E)
+		if err != nil {
+			return nil, err
+		}
+		return &rsa.PublicKey{
+			N: new(big.Int).SetBytes(n),
+			E: int(new(big.Int).SetBytes(e).Int64()),
+		}, nil
+
+	case "EC":
+		var curve elliptic.Curve
+		switch jwk.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		default:
+			return nil, fmt.Errorf("unsupported curve %q", jwk.Crv)
+		}
+		x, err := base64.RawURLEncoding.DecodeString(jwk.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := base64.RawURLEncoding.DecodeString(jwk.Y)
+		if err != nil {
+			return nil, err
+		}
+		return &ecdsa.PublicKey{Curve: curve, X: new(big.Int).SetBytes(x), Y: new(big.Int).SetBytes(y)}, nil
+	}
+
+	return nil, fmt.Errorf("unsupported key type %q", jwk.Kty)
+}
+
+// verifyJWSSignature verifies RS256/384/512 and ES256/384 signatures
+func verifyJWSSignature(alg string, key crypto.PublicKey, signingInput, signature []byte) error {
+	var hashFunc crypto.Hash
+	var hasher hash.Hash
+	switch alg {
+	case "RS256", "ES256":
+		hashFunc, hasher = crypto.SHA256, sha256.New()
+	case "RS384", "ES384":
+		hashFunc, hasher = crypto.SHA384, sha512.New384()
+	case "RS512":
+		hashFunc, hasher = crypto.SHA512, sha512.New()
+	default:
+		return fmt.Errorf("unsupported signing algorithm %q", alg)
+	}
+	hasher.Write(signingInput)
+	digest := hasher.Sum(nil)
+
+	switch pub := key.(type) {
+	case *rsa.PublicKey:
+		if !strings.HasPrefix(alg, "RS") {
+			return fmt.Errorf("algorithm %s does not match RSA key", alg)
+		}
+		return rsa.VerifyPKCS1v15(pub, hashFunc, digest, signature)
+
+	case *ecdsa.PublicKey:
+		if !strings.HasPrefix(alg, "ES") {
+			return fmt.Errorf("algorithm %s does not match EC key", alg)
+		}
+		size := (pub.Curve.Params().BitSize + 7) / 8
+		if len(signature) != 2*size {
+			return fmt.Errorf("invalid EC signature length")
+		}
+		r := new(big.Int).SetBytes(signature[:size])
+		s := new(big.Int).SetBytes(signature[size:])
+		if !ecdsa.Verify(pub, digest, r, s) {
+			return fmt.Errorf("signature verification failed")
+		}
+		return nil
+	}
+
+	return fmt.Errorf("unsupported key type")
+}
+
+func decodeJWTSegment(segment string, dest interface{}) error {
+	data, err := base64.RawURLEncoding.DecodeString(segment)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, dest)
+}
+
+// parseAudience accepts both the string and array forms of the aud claim
+func parseAudience(raw json.RawMessage) []string {
+	var single string
+	if err := json.Unmarshal(raw, &single); err == nil {
+		return []string{single}
+	}
+	var many []string
+	json.Unmarshal(raw, &many)
+	return many
+}
+
+func isTruthy(v interface{}) bool {
+	switch value := v.(type) {
+	case bool:
+		return value
+	case string:
+		return strings.EqualFold(value, "true")
+	}
+	return false
+}
+
+func containsString(values []string, target string) bool {
+	for _, v := range values {
+		if v == target {
+			return true
+		}
+	}
+	return false
+}
+
+func randomURLToken(size int) (string, error) {
+	bytes := make([]byte, size)
+	if _, err := rand.Read(bytes); err != nil {
+		return "", errors.Wrap(err, errors.ErrInternalServer)
+	}
+	return base64.RawURLEncoding.EncodeToString(bytes), nil
+}
+
+func oidcStateKey(state string) string {
+	return "oidc_state:" + HashToken(state)[:32]
+}
@@ -0,0 +1,48 @@
+package auth
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"article-chat-system/server/internal/config"
+	"article-chat-system/server/internal/errors"
+	"article-chat-system/server/internal/models"
+)
+
+func TestConfirmIdentityWithoutPassword(t *testing.T) {
+	s := NewAuthService(nil, nil, nil, config.AuthConfig{OIDC: config.OIDCConfig{Enabled: true}}, config.MailConfig{})
+
+	at := func(ago time.Duration) *time.Time {
+		t := time.Now().Add(-ago)
+		return &t
+	}
+
+	tests := []struct {
+		name       string
+		ssoLoginAt *time.Time
+		confirmed  bool
+	}{
+		{"fresh SSO login", at(time.Minute), true},
+		{"SSO login at the end of the window", at(SSOReauthWindow - time.Second), true},
+		{"stale SSO login", at(SSOReauthWindow + time.Second), false},
+		{"password session", nil, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			user := &models.User{ID: uuid.New(), Email: "sso@example.com", SSOLoginAt: tt.ssoLoginAt}
+			err := s.ConfirmIdentity(user, "")
+			if tt.confirmed && err != nil {
+				t.Errorf("ConfirmIdentity = %v, want nil", err)
+			}
+			if !tt.confirmed {
+				appErr, ok := errors.IsAppError(err)
+				if !ok || appErr.Code != errors.ErrMissingRequiredField {
+					t.Errorf("ConfirmIdentity = %v, want ErrMissingRequiredField", err)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,248 @@
+package auth
+
+import (
+	"context"
+	"time"
+
+	"article-chat-system/server/internal/errors"
+	"article-chat-system/server/internal/models"
+	"github.com/google/uuid"
+)
+
+// StartTOTPEnrollment generates a new pending TOTP secret for the user
+// The secret only becomes active after ConfirmTOTPEnrollment succeeds
+func (s *AuthService) StartTOTPEnrollment(user *models.User) (*models.TwoFactorEnrollment, error) {
+	if user.TwoFactorEnabled {
+		return nil, errors.New(errors.ErrValidationFailed, "Two-factor authentication is already enabled")
+	}
+
+	secret, err := GenerateTOTPSecret()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.db.SetPendingTOTPSecret(user.ID, secret); err != nil {
+		return nil, err
+	}
+
+	return &models.TwoFactorEnrollment{
+		Secret:          secret,
+		ProvisioningURI: TOTPProvisioningURI(s.cfg.TwoFactor.Issuer, user.Email, secret),
+	}, nil
+}
+
+// ConfirmTOTPEnrollment verifies a first code against the pending secret and enables 2FA
+// Returns the plain recovery codes; only their hashes are stored, so they are shown once
+func (s *AuthService) ConfirmTOTPEnrollment(userID uuid.UUID, code string) ([]string, error) {
+	secret, enabled, err := s.db.GetTOTPState(userID)
+	if err != nil {
+		return nil, err
+	}
+	if enabled {
+		return nil, errors.New(errors.ErrValidationFailed, "Two-factor authentication is already enabled")
+	}
+	if secret == "" {
+		return nil, errors.New(errors.ErrValidationFailed, "Two-factor enrollment has not been started")
+	}
+
+	step, ok := ValidateTOTP(secret, code, time.Now(), s.cfg.TwoFactor.Skew)
+	if !ok {
+		return nil, errors.New(errors.ErrValidationFailed, "Invalid two-factor code")
+	}
+	consumed, err := s.db.ConsumeTOTPStep(userID, step)
+	if err != nil {
+		return nil, err
+	}
+	if !consumed {
+		return nil, errors.New(errors.ErrValidationFailed, "Invalid two-factor code")
+	}
+
+	codes, err := GenerateRecoveryCodes(s.cfg.TwoFactor.RecoveryCodeCount)
+	if err != nil {
+		return nil, err
+	}
+
+	hashes := make([]string, len(codes))
+	for i, c := range codes {
+		hashes[i] = HashRecoveryCode(c)
+	}
+
+	if err := s.db.EnableTOTP(userID, hashes); err != nil {
+		return nil, err
+	}
+
+	auditLog(AuditTwoFactorEnrolled, "user_id", userID)
+
+	return codes, nil
+}
+
+// DisableTOTP turns off two-factor authentication after re-checking the user's identity
+func (s *AuthService) DisableTOTP(user *models.User, password string) error {
+	if err := s.ConfirmIdentity(user, password); err != nil {
+		return err
+	}
+
+	if !user.TwoFactorEnabled {
+		return errors.New(errors.ErrValidationFailed, "Two-factor authentication is not enabled")
+	}
+
+	if err := s.db.DisableTOTP(user.ID); err != nil {
+		return err
+	}
+
+	auditLog(AuditTwoFactorDisabled, "user_id", user.ID)
+
+	return nil
+}
+
+// VerifyTwoFactorLogin completes a login challenge and issues a real session
+// Accepts either a current TOTP code or an unused recovery code
+func (s *AuthService) VerifyTwoFactorLogin(verify *models.TwoFactorVerify, userAgent, ipAddress string) (*models.User, string, error) {
+	if verify.ChallengeToken == "" {
+		return nil, "", errors.New(errors.ErrMissingRequiredField, "Challenge token is required")
+	}
+	if verify.Code == "" && verify.RecoveryCode == "" {
+		return nil, "", errors.New(errors.ErrMissingRequiredField, "Code or recovery code is required")
+	}
+
+	challenge, err := s.db.GetTwoFactorChallenge(HashToken(verify.ChallengeToken))
+	if err != nil {
+		return nil, "", err
+	}
+
+	user, err := s.db.GetUserByID(challenge.UserID)
+	if err != nil {
+		return nil, "", err
+	}
+	if !user.IsActive {
+		return nil, "", errors.New(errors.ErrForbidden, "Account deactivated")
+	}
+
+	// Codes are guessed against the account, so they share the login limiter with passwords
+	ctx := context.Background()
+	if err := s.limiter.Check(ctx, user.Email, ipAddress); err != nil {
+		return nil, "", err
+	}
+
+	valid, err := s.checkSecondFactor(user, verify)
+	if err != nil {
+		return nil, "", err
+	}
+
+	if !valid {
+		auditLog(AuditTwoFactorFailed, "user_id", user.ID, "ip", ipAddress)
+
+		if err := s.limiter.RecordFailure(ctx, user.Email, ipAddress); err != nil {
+			// Locked: drop the challenge too, so it can't outlive the lockout
+			s.db.DeleteTwoFactorChallenge(challenge.ID)
+			return nil, "", err
+		}
+
+		attempts, err := s.db.IncrementChallengeAttempts(challenge.ID)
+		if err != nil {
+			return nil, "", err
+		}
+		if attempts >= s.cfg.TwoFactor.MaxChallengeAttempts {
+			// Force the client back through the password step (and the login limiter)
+			s.db.DeleteTwoFactorChallenge(challenge.ID)
+			auditLog(AuditChallengeExhausted, "user_id", user.ID, "ip", ipAddress)
+			return nil, "", errors.New(errors.ErrUnauthorized, "Too many invalid codes, please log in again")
+		}
+
+		return nil, "", errors.New(errors.ErrUnauthorized, "Invalid two-factor code")
+	}
+
+	// Challenges are single-use
+	if err := s.db.DeleteTwoFactorChallenge(challenge.ID); err != nil {
+		return nil, "", err
+	}
+
+	// Both factors passed: clear the email's failure history
+	s.limiter.Reset(ctx, user.Email)
+
+	token, err := s.issueSession(user, userAgent, ipAddress, challenge.ViaSSO)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return user, token, nil
+}
+
+// ConfirmIdentity re-authenticates a logged-in user before a sensitive operation
+// Users confirm with their password. Without one, a session from an SSO login in the last
+// SSOReauthWindow also counts, so accounts created through SSO (which have no password)
+// confirm by signing in with their identity provider again.
+func (s *AuthService) ConfirmIdentity(user *models.User, password string) error {
+	if password == "" {
+		if user.SSOLoginAt != nil && time.Since(*user.SSOLoginAt) < SSOReauthWindow {
+			auditLog(AuditSSOReauthenticated, "user_id", user.ID)
+			return nil
+		}
+		if s.cfg.OIDC.Enabled {
+			return errors.New(errors.ErrMissingRequiredField, "Password is required, or sign in again with SSO to confirm")
+		}
+		return errors.New(errors.ErrMissingRequiredField, "Password is required")
+	}
+
+	_, passwordHash, err := s.db.GetUserPasswordHash(user.Email)
+	if err != nil {
+		return err
+	}
+
+	if !CheckPasswordHash(password, passwordHash) {
+		auditLog(AuditPasswordConfirmFail, "user_id", user.ID)
+		return errors.New(errors.ErrUnauthorized, "Invalid password")
+	}
+
+	return nil
+}
+
+// checkSecondFactor validates a TOTP code (with replay protection) or consumes a recovery code
+func (s *AuthService) checkSecondFactor(user *models.User, verify *models.TwoFactorVerify) (bool, error) {
+	if verify.RecoveryCode != "" {
+		used, err := s.db.UseRecoveryCode(user.ID, HashRecoveryCode(verify.RecoveryCode))
+		if err != nil {
+			return false, err
+		}
+		if used {
+			auditLog(AuditRecoveryCodeUsed, "user_id", user.ID)
+		}
+		return used, nil
+	}
+
+	secret, enabled, err := s.db.GetTOTPState(user.ID)
+	if err != nil {
+		return false, err
+	}
+	if !enabled || secret == "" {
+		return false, nil
+	}
+
+	step, ok := ValidateTOTP(secret, verify.Code, time.Now(), s.cfg.TwoFactor.Skew)
+	if !ok {
+		return false, nil
+	}
+
+	// A code may only be used once, even inside its validity window
+	return s.db.ConsumeTOTPStep(user.ID, step)
+}
+
+// createTwoFactorChallenge stores a pending login and returns the raw challenge token
+func (s *AuthService) createTwoFactorChallenge(user *models.User, userAgent, ipAddress string, viaSSO bool) (*models.TwoFactorChallengeResponse, error) {
+	token, err := GenerateSessionToken()
+	if err != nil {
+		return nil, err
+	}
+
+	ttl := time.Duration(s.cfg.TwoFactor.ChallengeTTL) * time.Second
+	challenge, err := s.db.CreateTwoFactorChallenge(user.ID, HashToken(token), ttl, userAgent, ipAddress, viaSSO)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.TwoFactorChallengeResponse{
+		TwoFactorRequired: true,
+		ChallengeToken:    token,
+		ExpiresAt:         challenge.ExpiresAt,
+	}, nil
+}
@@ -0,0 +1,129 @@
+package auth
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"article-chat-system/server/internal/errors"
+	"article-chat-system/server/internal/mailer"
+	"article-chat-system/server/internal/models"
+	"github.com/google/uuid"
+)
+
+// Email verification tokens are stateless: base64url(userID|email|expiry) + "." + HMAC-SHA256.
+// They become single-use because verification only succeeds while email_verified_at is unset
+// and the account still has the email the token was issued for.
+
+// EmailVerificationEnabled reports whether new accounts must verify their email
+func (s *AuthService) EmailVerificationEnabled() bool {
+	return s.cfg.EmailVerification.Enabled
+}
+
+// ChatRequiresVerifiedEmail reports whether unverified users are limited to logging in
+func (s *AuthService) ChatRequiresVerifiedEmail() bool {
+	return s.cfg.EmailVerification.Enabled && !s.cfg.EmailVerification.AllowUnverifiedChat
+}
+
+// SendVerificationEmail mails a signed verification link to the user's current address
+func (s *AuthService) SendVerificationEmail(ctx context.Context, user *models.User) error {
+	if !s.EmailVerificationEnabled() {
+		return errors.New(errors.ErrValidationFailed, "Email verification is not enabled")
+	}
+	if user.EmailVerified {
+		return errors.New(errors.ErrValidationFailed, "Email is already verified")
+	}
+
+	ttl := time.Duration(s.cfg.EmailTokenTTL) * time.Second
+	token := s.signVerificationToken(user.ID, user.Email, time.Now().Add(ttl))
+
+	link := fmt.Sprintf("%s/verify-email?token=%s", strings.TrimRight(s.mailCfg.AppURL, "/"), url.QueryEscape(token))
+	err := s.mailer.Send(ctx, mailer.Message{
+		To:      user.Email,
+		Subject: "Verify your email address",
+		Body: fmt.Sprintf("Welcome, %s!\n\nFollow this link to verify your email address:\n\n%s\n\nThe link expires in %s.",
+			user.FullName, link, ttl),
+	})
+	if err != nil {
+		return errors.Wrap(err, errors.ErrInternalServer)
+	}
+
+	auditLog(AuditVerificationSent, "user_id", user.ID)
+
+	return nil
+}
+
+// VerifyEmail checks a verification token and marks the address as verified
+func (s *AuthService) VerifyEmail(token string) error {
+	if token == "" {
+		return errors.New(errors.ErrMissingRequiredField, "Token is required")
+	}
+
+	userID, email, err := s.parseVerificationToken(token)
+	if err != nil {
+		return err
+	}
+
+	verified, err := s.db.MarkEmailVerified(userID, email)
+	if err != nil {
+		return err
+	}
+	if !verified {
+		return errors.New(errors.ErrValidationFailed, "Invalid or expired verification token")
+	}
+
+	auditLog(AuditEmailVerified, "user_id", userID)
+
+	return nil
+}
+
+// signVerificationToken creates a token binding the user to an email until expiresAt
+func (s *AuthService) signVerificationToken(userID uuid.UUID, email string, expiresAt time.Time) string {
+	payload := fmt.Sprintf("%s|%s|%d", userID, email, expiresAt.Unix())
+	encoded := base64.RawURLEncoding.EncodeToString([]byte(payload))
+	return encoded + "." + s.verificationSignature(encoded)
+}
+
+// parseVerificationToken validates the signature and expiry of a verification token
+func (s *AuthService) parseVerificationToken(token string) (uuid.UUID, string, error) {
+	invalid := errors.New(errors.ErrValidationFailed, "Invalid or expired verification token")
+
+	encoded, signature, ok := strings.Cut(token, ".")
+	if !ok || !hmac.Equal([]byte(signature), []byte(s.verificationSignature(encoded))) {
+		return uuid.Nil, "", invalid
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return uuid.Nil, "", invalid
+	}
+
+	parts := strings.Split(string(payload), "|")
+	if len(parts) != 3 {
+		return uuid.Nil, "", invalid
+	}
+
+	userID, err := uuid.Parse(parts[0])
+	if err != nil {
+		return uuid.Nil, "", invalid
+	}
+
+	expiresAt, err := strconv.ParseInt(parts[2], 10, 64)
+	if err != nil || time.Now().Unix() > expiresAt {
+		return uuid.Nil, "", invalid
+	}
+
+	return userID, parts[1], nil
+}
+
+func (s *AuthService) verificationSignature(encodedPayload string) string {
+	mac := hmac.New(sha256.New, []byte(s.cfg.EmailVerification.Secret))
+	mac.Write([]byte("email-verification:" + encodedPayload))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
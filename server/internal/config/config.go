@@ -3,7 +3,10 @@ package config
 import (
 	"fmt"
 	"log/slog"
+	"net"
 	"os"
+	"strconv"
+	"strings"
 
 	"github.com/joho/godotenv"
 	"github.com/spf13/viper"
@@ -15,6 +18,10 @@ type Config struct {
 	Database   DatabaseConfig   `json:"database"`
 	Redis      RedisConfig      `json:"redis"`
 	RateLimit  RateLimitConfig  `json:"rate_limit"`
+	Auth       AuthConfig       `json:"auth"`
+	Mail       MailConfig       `json:"mail"`
+	Chat       ChatConfig       `json:"chat"`
+	Fetcher    FetcherConfig    `json:"fetcher"`
 }
 
 type ServerConfig struct {
@@ -23,6 +30,8 @@ type ServerConfig struct {
 	Environment  string `json:"environment"`
 	ReadTimeout  int    `json:"read_timeout"`
 	WriteTimeout int    `json:"write_timeout"`
+
+	TrustedProxies string `json:"trusted_proxies" mapstructure:"trusted_proxies"` // Comma separated IPs or CIDRs allowed to set X-Real-IP
 }
 
 type RAGServiceConfig struct {
@@ -52,6 +61,149 @@ type RateLimitConfig struct {
 	MaxConcurrent int `json:"max_concurrent"`
 }
 
+// AuthConfig groups settings for the authentication subsystem
+type AuthConfig struct {
+	LoginProtection LoginProtectionConfig `json:"login_protection" mapstructure:"login_protection"`
+	TwoFactor       TwoFactorConfig       `json:"two_factor" mapstructure:"two_factor"`
+	OIDC            OIDCConfig            `json:"oidc" mapstructure:"oidc"`
+	EmailTokenTTL   int                   `json:"email_token_ttl" mapstructure:"email_token_ttl"` // Seconds an emailed confirmation link stays valid
+
+	EmailVerification EmailVerificationConfig `json:"email_verification" mapstructure:"email_verification"`
+
+	AdminEmails string `json:"admin_emails" mapstructure:"admin_emails"` // Comma separated emails allowed to use admin endpoints
+}
+
+// EmailVerificationConfig controls email verification at signup
+type EmailVerificationConfig struct {
+	Enabled             bool   `json:"enabled" mapstructure:"enabled"`
+	Secret              string `json:"secret" mapstructure:"secret"`                               // HMAC key signing verification tokens
+	AllowUnverifiedChat bool   `json:"allow_unverified_chat" mapstructure:"allow_unverified_chat"` // false: unverified users may only log in
+}
+
+// LoginProtectionConfig controls brute-force protection for the login endpoint.
+// Durations are expressed in seconds, like the server timeouts above.
+type LoginProtectionConfig struct {
+	Enabled             bool `json:"enabled" mapstructure:"enabled"`
+	MaxAttemptsPerEmail int  `json:"max_attempts_per_email" mapstructure:"max_attempts_per_email"` // Failures before the account is locked
+	MaxAttemptsPerIP    int  `json:"max_attempts_per_ip" mapstructure:"max_attempts_per_ip"`       // Failures before the client IP is locked
+	FreeAttempts        int  `json:"free_attempts" mapstructure:"free_attempts"`                   // Failures allowed before delays kick in
+	BaseDelay           int  `json:"base_delay" mapstructure:"base_delay"`                         // First progressive delay, doubled per failure
+	MaxDelay            int  `json:"max_delay" mapstructure:"max_delay"`                           // Upper bound for progressive delays
+	LockoutDuration     int  `json:"lockout_duration" mapstructure:"lockout_duration"`             // How long a lockout lasts
+	AttemptWindow       int  `json:"attempt_window" mapstructure:"attempt_window"`                 // How long failures are remembered
+}
+
+// TwoFactorConfig controls TOTP two-factor authentication
+type TwoFactorConfig struct {
+	Issuer               string `json:"issuer" mapstructure:"issuer"`                                 // Issuer shown in authenticator apps
+	ChallengeTTL         int    `json:"challenge_ttl" mapstructure:"challenge_ttl"`                   // Seconds a login challenge stays valid
+	MaxChallengeAttempts int    `json:"max_challenge_attempts" mapstructure:"max_challenge_attempts"` // Wrong codes before a challenge is revoked
+	Skew                 int    `json:"skew" mapstructure:"skew"`                                     // Accepted 30s steps before/after now
+	RecoveryCodeCount    int    `json:"recovery_code_count" mapstructure:"recovery_code_count"`       // Recovery codes issued on enrollment
+}
+
+// OIDCConfig configures OpenID Connect single sign-on (authorization code + PKCE)
+type OIDCConfig struct {
+	Enabled              bool   `json:"enabled" mapstructure:"enabled"`
+	ProviderName         string `json:"provider_name" mapstructure:"provider_name"`                     // Stored with linked identities, e.g. "okta"
+	IssuerURL            string `json:"issuer_url" mapstructure:"issuer_url"`                           // Base URL serving /.well-known/openid-configuration
+	ClientID             string `json:"client_id" mapstructure:"client_id"`                             // OAuth2 client registered with the provider
+	ClientSecret         string `json:"client_secret" mapstructure:"client_secret"`                     // Optional for public clients using PKCE only
+	RedirectURL          string `json:"redirect_url" mapstructure:"redirect_url"`                       // Must point at /api/auth/oidc/callback
+	Scopes               string `json:"scopes" mapstructure:"scopes"`                                   // Space separated, must include openid and email
+	AllowSignup          bool   `json:"allow_signup" mapstructure:"allow_signup"`                       // Create users for unknown verified emails
+	PostLoginRedirectURL string `json:"post_login_redirect_url" mapstructure:"post_login_redirect_url"` // Frontend URL receiving the token in the fragment
+}
+
+// ChatConfig controls conversation features built on top of the RAG service
+type ChatConfig struct {
+	AutoTitles bool `json:"auto_titles" mapstructure:"auto_titles"` // Ask the RAG service to title new conversations
+
+	History HistoryConfig `json:"history" mapstructure:"history"`
+}
+
+// HistoryConfig bounds the conversation history sent to the RAG service with each question
+// Turns outside the window are dropped, or folded into a rolling summary when summaries are enabled.
+type HistoryConfig struct {
+	MaxTurns           int  `json:"max_turns" mapstructure:"max_turns"`                       // Most recent question/answer pairs sent (0: no limit)
+	MaxTokens          int  `json:"max_tokens" mapstructure:"max_tokens"`                     // Estimated token budget for sent messages (0: no limit)
+	Summaries          bool `json:"summaries" mapstructure:"summaries"`                       // Summarize older turns in the background
+	SummaryMinMessages int  `json:"summary_min_messages" mapstructure:"summary_min_messages"` // Unsummarized older messages that trigger a refresh
+}
+
+// Article extractors selectable with FetcherConfig.Extractor
+const (
+	ExtractorNative             = "native"               // Fetch pages directly and extract the main content
+	ExtractorJina               = "jina"                 // Jina Reader API (https://r.jina.ai/)
+	ExtractorNativeWithFallback = "native_with_fallback" // Native first, Jina when native extraction fails
+)
+
+// FetcherConfig controls how article pages are fetched and their content extracted
+type FetcherConfig struct {
+	Extractor    string `json:"extractor" mapstructure:"extractor"`           // native, jina or native_with_fallback
+	JinaAPIKey   string `json:"-" mapstructure:"jina_api_key"`                // Optional; raises Jina rate limits
+	UserAgent    string `json:"user_agent" mapstructure:"user_agent"`         // Sent by the native extractor
+	MaxPageBytes int64  `json:"max_page_bytes" mapstructure:"max_page_bytes"` // Larger pages are rejected by the native extractor
+
+	// Article URLs must use an allowed port and resolve to public addresses; domain lists
+	// match the domain itself and its subdomains
+	AllowedPorts         string `json:"allowed_ports" mapstructure:"allowed_ports"`                   // Comma separated
+	AllowedDomains       string `json:"allowed_domains" mapstructure:"allowed_domains"`               // Comma separated; empty allows all
+	DeniedDomains        string `json:"denied_domains" mapstructure:"denied_domains"`                 // Comma separated
+	AllowPrivateNetworks bool   `json:"allow_private_networks" mapstructure:"allow_private_networks"` // Development only
+
+	Refresh RefreshConfig `json:"refresh" mapstructure:"refresh"`
+	Feeds   FeedConfig    `json:"feeds" mapstructure:"feeds"`
+	Uploads UploadConfig  `json:"uploads" mapstructure:"uploads"`
+	Retry   RetryConfig   `json:"retry" mapstructure:"retry"`
+}
+
+// RefreshConfig controls the periodic re-fetching of indexed articles.
+// Durations are expressed in seconds; articles may override the interval.
+type RefreshConfig struct {
+	Enabled      bool `json:"enabled" mapstructure:"enabled"`
+	Interval     int  `json:"interval" mapstructure:"interval"`           // Default time between checks of an article (0: never)
+	PollInterval int  `json:"poll_interval" mapstructure:"poll_interval"` // How often the scheduler looks for due articles
+	BatchSize    int  `json:"batch_size" mapstructure:"batch_size"`       // Articles checked per poll
+}
+
+// FeedConfig controls polling of RSS and Atom feed subscriptions.
+// Durations are expressed in seconds; feeds may set their own interval.
+type FeedConfig struct {
+	Enabled         bool `json:"enabled" mapstructure:"enabled"`
+	DefaultInterval int  `json:"default_interval" mapstructure:"default_interval"` // Poll interval of new subscriptions
+	MinInterval     int  `json:"min_interval" mapstructure:"min_interval"`         // Shortest interval a feed may request
+	MaxBackoff      int  `json:"max_backoff" mapstructure:"max_backoff"`           // Longest delay after repeated errors
+	PollInterval    int  `json:"poll_interval" mapstructure:"poll_interval"`       // How often the scheduler looks for due feeds
+	BatchSize       int  `json:"batch_size" mapstructure:"batch_size"`             // Feeds polled per pass
+	MaxItems        int  `json:"max_items" mapstructure:"max_items"`               // New entries ingested per feed poll
+}
+
+// RetryConfig controls automatic retries of failed article ingestions.
+// Durations are expressed in seconds; the delay doubles with each failed attempt.
+type RetryConfig struct {
+	Enabled      bool `json:"enabled" mapstructure:"enabled"`
+	MaxAttempts  int  `json:"max_attempts" mapstructure:"max_attempts"`   // Failed attempts after which retries stop
+	BaseDelay    int  `json:"base_delay" mapstructure:"base_delay"`       // Delay after the first failure
+	MaxDelay     int  `json:"max_delay" mapstructure:"max_delay"`         // Upper bound for the doubled delays
+	PollInterval int  `json:"poll_interval" mapstructure:"poll_interval"` // How often the scheduler looks for due retries
+	BatchSize    int  `json:"batch_size" mapstructure:"batch_size"`       // Articles retried per poll
+}
+
+// UploadConfig limits documents uploaded into the knowledge base
+type UploadConfig struct {
+	MaxFileBytes int64 `json:"max_file_bytes" mapstructure:"max_file_bytes"` // Larger files are rejected
+	MaxFiles     int   `json:"max_files" mapstructure:"max_files"`           // Files accepted per request
+}
+
+// MailConfig controls delivery of transactional emails
+type MailConfig struct {
+	Driver string `json:"driver" mapstructure:"driver"`   // "log" writes emails to the application log, "file" to Dir
+	From   string `json:"from" mapstructure:"from"`       // Sender address
+	AppURL string `json:"app_url" mapstructure:"app_url"` // Frontend base URL used in emailed links
+	Dir    string `json:"dir" mapstructure:"dir"`         // Output directory for the file driver
+}
+
 func Load() (*Config, error) {
 	// Load .env file if it exists
 	if err := godotenv.Load(".env"); err != nil {
@@ -125,6 +277,7 @@ func setDefaults() {
 	viper.SetDefault("server.environment", "development")
 	viper.SetDefault("server.read_timeout", 30)
 	viper.SetDefault("server.write_timeout", 30)
+	viper.SetDefault("server.trusted_proxies", "127.0.0.1,::1,10.0.0.0/8,172.16.0.0/12,192.168.0.0/16") // Loopback and Docker/private networks, where nginx runs
 
 	// RAG Service defaults
 	viper.SetDefault("rag_service.url", "http://rag-service:3001")
@@ -148,6 +301,80 @@ func setDefaults() {
 	viper.SetDefault("rate_limit.burst_size", 20)
 	viper.SetDefault("rate_limit.max_concurrent", 100)
 
+	// Login protection defaults
+	viper.SetDefault("auth.login_protection.enabled", true)
+	viper.SetDefault("auth.login_protection.max_attempts_per_email", 5)
+	viper.SetDefault("auth.login_protection.max_attempts_per_ip", 20)
+	viper.SetDefault("auth.login_protection.free_attempts", 3)
+	viper.SetDefault("auth.login_protection.base_delay", 1)
+	viper.SetDefault("auth.login_protection.max_delay", 30)
+	viper.SetDefault("auth.login_protection.lockout_duration", 900)
+	viper.SetDefault("auth.login_protection.attempt_window", 900)
+
+	// Two-factor authentication defaults
+	viper.SetDefault("auth.two_factor.issuer", "Clarticle")
+	viper.SetDefault("auth.two_factor.challenge_ttl", 300)
+	viper.SetDefault("auth.two_factor.max_challenge_attempts", 5)
+	viper.SetDefault("auth.two_factor.skew", 1)
+	viper.SetDefault("auth.two_factor.recovery_code_count", 10)
+
+	// OpenID Connect defaults (disabled until an issuer is configured)
+	viper.SetDefault("auth.oidc.enabled", false)
+	viper.SetDefault("auth.oidc.provider_name", "oidc")
+	viper.SetDefault("auth.oidc.scopes", "openid email profile")
+	viper.SetDefault("auth.oidc.allow_signup", true)
+
+	// Account self-service defaults
+	viper.SetDefault("auth.email_token_ttl", 86400)
+
+	// Email verification defaults (off until a signing secret is configured)
+	viper.SetDefault("auth.email_verification.enabled", false)
+	viper.SetDefault("auth.email_verification.allow_unverified_chat", false)
+
+	// Admin defaults (no admins until emails are listed)
+	viper.SetDefault("auth.admin_emails", "")
+
+	// Chat defaults
+	viper.SetDefault("chat.auto_titles", false)
+	viper.SetDefault("chat.history.max_turns", 10)
+	viper.SetDefault("chat.history.max_tokens", 6000)
+	viper.SetDefault("chat.history.summaries", false)
+	viper.SetDefault("chat.history.summary_min_messages", 6)
+
+	// Article fetcher defaults
+	viper.SetDefault("fetcher.extractor", ExtractorJina)
+	viper.SetDefault("fetcher.user_agent", "Mozilla/5.0 (compatible; ClarticleBot/1.0)")
+	viper.SetDefault("fetcher.max_page_bytes", 5<<20)
+	viper.SetDefault("fetcher.allowed_ports", "80,443")
+	viper.SetDefault("fetcher.allowed_domains", "")
+	viper.SetDefault("fetcher.denied_domains", "")
+	viper.SetDefault("fetcher.allow_private_networks", false)
+	viper.SetDefault("fetcher.refresh.enabled", false)
+	viper.SetDefault("fetcher.refresh.interval", 86400)
+	viper.SetDefault("fetcher.refresh.poll_interval", 300)
+	viper.SetDefault("fetcher.refresh.batch_size", 20)
+	viper.SetDefault("fetcher.feeds.enabled", true)
+	viper.SetDefault("fetcher.feeds.default_interval", 3600)
+	viper.SetDefault("fetcher.feeds.min_interval", 300)
+	viper.SetDefault("fetcher.feeds.max_backoff", 86400)
+	viper.SetDefault("fetcher.feeds.poll_interval", 60)
+	viper.SetDefault("fetcher.feeds.batch_size", 10)
+	viper.SetDefault("fetcher.feeds.max_items", 20)
+	viper.SetDefault("fetcher.uploads.max_file_bytes", 10<<20)
+	viper.SetDefault("fetcher.uploads.max_files", 5)
+	viper.SetDefault("fetcher.retry.enabled", true)
+	viper.SetDefault("fetcher.retry.max_attempts", 5)
+	viper.SetDefault("fetcher.retry.base_delay", 60)
+	viper.SetDefault("fetcher.retry.max_delay", 21600)
+	viper.SetDefault("fetcher.retry.poll_interval", 60)
+	viper.SetDefault("fetcher.retry.batch_size", 10)
+
+	// Mail defaults
+	viper.SetDefault("mail.driver", "log")
+	viper.SetDefault("mail.from", "no-reply@clarticle.local")
+	viper.SetDefault("mail.app_url", "http://localhost:3000")
+	viper.SetDefault("mail.dir", "./mail")
+
 	// Bind environment variables
 	viper.BindEnv("rag_service.url", "RAG_SERVICE_URL")
 	viper.BindEnv("database.url", "DATABASE_URL")
@@ -155,6 +382,62 @@ func setDefaults() {
 	viper.BindEnv("server.port", "PORT")
 	viper.BindEnv("server.host", "HOST")
 	viper.BindEnv("server.environment", "GO_ENV")
+	viper.BindEnv("server.trusted_proxies", "TRUSTED_PROXIES")
+	viper.BindEnv("auth.login_protection.enabled", "LOGIN_PROTECTION_ENABLED")
+	viper.BindEnv("auth.login_protection.max_attempts_per_email", "LOGIN_MAX_ATTEMPTS_PER_EMAIL")
+	viper.BindEnv("auth.login_protection.max_attempts_per_ip", "LOGIN_MAX_ATTEMPTS_PER_IP")
+	viper.BindEnv("auth.login_protection.lockout_duration", "LOGIN_LOCKOUT_DURATION")
+	viper.BindEnv("auth.two_factor.issuer", "TOTP_ISSUER")
+	viper.BindEnv("auth.oidc.enabled", "OIDC_ENABLED")
+	viper.BindEnv("auth.oidc.provider_name", "OIDC_PROVIDER_NAME")
+	viper.BindEnv("auth.oidc.issuer_url", "OIDC_ISSUER_URL")
+	viper.BindEnv("auth.oidc.client_id", "OIDC_CLIENT_ID")
+	viper.BindEnv("auth.oidc.client_secret", "OIDC_CLIENT_SECRET")
+	viper.BindEnv("auth.oidc.redirect_url", "OIDC_REDIRECT_URL")
+	viper.BindEnv("auth.oidc.scopes", "OIDC_SCOPES")
+	viper.BindEnv("auth.oidc.allow_signup", "OIDC_ALLOW_SIGNUP")
+	viper.BindEnv("auth.oidc.post_login_redirect_url", "OIDC_POST_LOGIN_REDIRECT_URL")
+	viper.BindEnv("auth.email_token_ttl", "EMAIL_TOKEN_TTL")
+	viper.BindEnv("auth.email_verification.enabled", "EMAIL_VERIFICATION_ENABLED")
+	viper.BindEnv("auth.email_verification.secret", "EMAIL_VERIFICATION_SECRET")
+	viper.BindEnv("auth.email_verification.allow_unverified_chat", "EMAIL_VERIFICATION_ALLOW_UNVERIFIED_CHAT")
+	viper.BindEnv("auth.admin_emails", "ADMIN_EMAILS")
+	viper.BindEnv("chat.auto_titles", "CHAT_AUTO_TITLES")
+	viper.BindEnv("chat.history.max_turns", "CHAT_HISTORY_MAX_TURNS")
+	viper.BindEnv("chat.history.max_tokens", "CHAT_HISTORY_MAX_TOKENS")
+	viper.BindEnv("chat.history.summaries", "CHAT_HISTORY_SUMMARIES")
+	viper.BindEnv("chat.history.summary_min_messages", "CHAT_HISTORY_SUMMARY_MIN_MESSAGES")
+	viper.BindEnv("fetcher.extractor", "ARTICLE_EXTRACTOR")
+	viper.BindEnv("fetcher.jina_api_key", "JINA_API_KEY")
+	viper.BindEnv("fetcher.user_agent", "FETCHER_USER_AGENT")
+	viper.BindEnv("fetcher.max_page_bytes", "FETCHER_MAX_PAGE_BYTES")
+	viper.BindEnv("fetcher.allowed_ports", "FETCHER_ALLOWED_PORTS")
+	viper.BindEnv("fetcher.allowed_domains", "FETCHER_ALLOWED_DOMAINS")
+	viper.BindEnv("fetcher.denied_domains", "FETCHER_DENIED_DOMAINS")
+	viper.BindEnv("fetcher.allow_private_networks", "FETCHER_ALLOW_PRIVATE_NETWORKS")
+	viper.BindEnv("fetcher.refresh.enabled", "ARTICLE_REFRESH_ENABLED")
+	viper.BindEnv("fetcher.refresh.interval", "ARTICLE_REFRESH_INTERVAL")
+	viper.BindEnv("fetcher.refresh.poll_interval", "ARTICLE_REFRESH_POLL_INTERVAL")
+	viper.BindEnv("fetcher.refresh.batch_size", "ARTICLE_REFRESH_BATCH_SIZE")
+	viper.BindEnv("fetcher.feeds.enabled", "FEEDS_ENABLED")
+	viper.BindEnv("fetcher.feeds.default_interval", "FEED_DEFAULT_INTERVAL")
+	viper.BindEnv("fetcher.feeds.min_interval", "FEED_MIN_INTERVAL")
+	viper.BindEnv("fetcher.feeds.max_backoff", "FEED_MAX_BACKOFF")
+	viper.BindEnv("fetcher.feeds.poll_interval", "FEED_POLL_INTERVAL")
+	viper.BindEnv("fetcher.feeds.batch_size", "FEED_BATCH_SIZE")
+	viper.BindEnv("fetcher.feeds.max_items", "FEED_MAX_ITEMS")
+	viper.BindEnv("fetcher.uploads.max_file_bytes", "UPLOAD_MAX_FILE_BYTES")
+	viper.BindEnv("fetcher.uploads.max_files", "UPLOAD_MAX_FILES")
+	viper.BindEnv("fetcher.retry.enabled", "ARTICLE_RETRY_ENABLED")
+	viper.BindEnv("fetcher.retry.max_attempts", "ARTICLE_RETRY_MAX_ATTEMPTS")
+	viper.BindEnv("fetcher.retry.base_delay", "ARTICLE_RETRY_BASE_DELAY")
+	viper.BindEnv("fetcher.retry.max_delay", "ARTICLE_RETRY_MAX_DELAY")
+	viper.BindEnv("fetcher.retry.poll_interval", "ARTICLE_RETRY_POLL_INTERVAL")
+	viper.BindEnv("fetcher.retry.batch_size", "ARTICLE_RETRY_BATCH_SIZE")
+	viper.BindEnv("mail.dir", "MAIL_DIR")
+	viper.BindEnv("mail.driver", "MAIL_DRIVER")
+	viper.BindEnv("mail.from", "MAIL_FROM")
+	viper.BindEnv("mail.app_url", "APP_URL")
 }
 
 func validateConfig(config *Config) error {
@@ -171,9 +454,72 @@ func validateConfig(config *Config) error {
 		return fmt.Errorf("DATABASE_URL is required")
 	}
 
+	if oidc := config.Auth.OIDC; oidc.Enabled {
+		if oidc.IssuerURL == "" || oidc.ClientID == "" || oidc.RedirectURL == "" {
+			return fmt.Errorf("OIDC_ISSUER_URL, OIDC_CLIENT_ID and OIDC_REDIRECT_URL are required when OIDC is enabled")
+		}
+	}
+
+	if verification := config.Auth.EmailVerification; verification.Enabled && len(verification.Secret) < 32 {
+		return fmt.Errorf("EMAIL_VERIFICATION_SECRET of at least 32 characters is required when email verification is enabled")
+	}
+
+	switch config.Fetcher.Extractor {
+	case ExtractorNative, ExtractorJina, ExtractorNativeWithFallback:
+	default:
+		return fmt.Errorf("ARTICLE_EXTRACTOR must be %q, %q or %q", ExtractorNative, ExtractorJina, ExtractorNativeWithFallback)
+	}
+
+	if refresh := config.Fetcher.Refresh; refresh.Enabled && (refresh.Interval < 0 || refresh.PollInterval < 1 || refresh.BatchSize < 1) {
+		return fmt.Errorf("ARTICLE_REFRESH_INTERVAL must not be negative and ARTICLE_REFRESH_POLL_INTERVAL and ARTICLE_REFRESH_BATCH_SIZE must be at least 1")
+	}
+
+	if feeds := config.Fetcher.Feeds; feeds.MinInterval < 1 || feeds.DefaultInterval < feeds.MinInterval || feeds.MaxBackoff < feeds.DefaultInterval ||
+		feeds.PollInterval < 1 || feeds.BatchSize < 1 || feeds.MaxItems < 1 {
+		return fmt.Errorf("FEED_MIN_INTERVAL <= FEED_DEFAULT_INTERVAL <= FEED_MAX_BACKOFF and FEED_POLL_INTERVAL, FEED_BATCH_SIZE and FEED_MAX_ITEMS must be at least 1")
+	}
+
+	if retry := config.Fetcher.Retry; retry.Enabled && (retry.MaxAttempts < 1 || retry.BaseDelay < 1 || retry.MaxDelay < retry.BaseDelay ||
+		retry.PollInterval < 1 || retry.BatchSize < 1) {
+		return fmt.Errorf("ARTICLE_RETRY_BASE_DELAY <= ARTICLE_RETRY_MAX_DELAY and ARTICLE_RETRY_MAX_ATTEMPTS, ARTICLE_RETRY_POLL_INTERVAL and ARTICLE_RETRY_BATCH_SIZE must be at least 1")
+	}
+
+	if uploads := config.Fetcher.Uploads; uploads.MaxFileBytes < 1 || uploads.MaxFiles < 1 {
+		return fmt.Errorf("UPLOAD_MAX_FILE_BYTES and UPLOAD_MAX_FILES must be at least 1")
+	}
+
+	for _, proxy := range config.Server.TrustedProxyList() {
+		if net.ParseIP(proxy) == nil {
+			if _, _, err := net.ParseCIDR(proxy); err != nil {
+				return fmt.Errorf("TRUSTED_PROXIES must be a comma separated list of IPs or CIDRs")
+			}
+		}
+	}
+
+	for _, port := range strings.Split(config.Fetcher.AllowedPorts, ",") {
+		if n, err := strconv.Atoi(strings.TrimSpace(port)); err != nil || n < 1 || n > 65535 {
+			return fmt.Errorf("FETCHER_ALLOWED_PORTS must be a comma separated list of ports")
+		}
+	}
+
+	if history := config.Chat.History; history.MaxTurns < 0 || history.MaxTokens < 0 || history.SummaryMinMessages < 1 {
+		return fmt.Errorf("CHAT_HISTORY_MAX_TURNS and CHAT_HISTORY_MAX_TOKENS must not be negative and CHAT_HISTORY_SUMMARY_MIN_MESSAGES must be at least 1")
+	}
+
 	return nil
 }
 
+// TrustedProxyList returns the entries of TRUSTED_PROXIES
+func (s ServerConfig) TrustedProxyList() []string {
+	var proxies []string
+	for _, proxy := range strings.Split(s.TrustedProxies, ",") {
+		if proxy = strings.TrimSpace(proxy); proxy != "" {
+			proxies = append(proxies, proxy)
+		}
+	}
+	return proxies
+}
+
 // DatabaseURL returns the database connection string
 func (c *Config) DatabaseURL() string {
 	return c.Database.URL
@@ -0,0 +1,127 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"article-chat-system/server/internal/errors"
+	"article-chat-system/server/internal/models"
+	"github.com/google/uuid"
+)
+
+// GetUserChatPrompts returns every user-authored message across the user's conversations
+// Only ConversationID and Content are populated; callers use them to rebuild chat cache keys
+func (db *DB) GetUserChatPrompts(ctx context.Context, userID uuid.UUID) ([]models.Message, error) {
+	query := `
+		SELECT m.conversation_id, m.content
+		FROM messages m
+		JOIN conversations c ON c.id = m.conversation_id
+		WHERE c.user_id = $1 AND m.role = 'user'`
+
+	rows, err := db.QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, errors.Wrap(err, errors.ErrDatabaseError)
+	}
+	defer rows.Close()
+
+	var messages []models.Message
+	for rows.Next() {
+		var msg models.Message
+		if err := rows.Scan(&msg.ConversationID, &msg.Content); err != nil {
+			return nil, errors.Wrap(err, errors.ErrDatabaseError)
+		}
+		messages = append(messages, msg)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, errors.Wrap(err, errors.ErrDatabaseError)
+	}
+
+	return messages, nil
+}
+
+// DeleteUser permanently removes a user together with all of their data
+// Conversations are removed first so their messages go with them; sessions, identities,
+// 2FA state and pending email changes are removed by ON DELETE CASCADE on users
+func (db *DB) DeleteUser(ctx context.Context, userID uuid.UUID) error {
+	return db.Transaction(func(tx *sql.Tx) error {
+		if _, err := tx.ExecContext(ctx, `DELETE FROM conversations WHERE user_id = $1`, userID); err != nil {
+			return errors.Wrap(err, errors.ErrDatabaseError)
+		}
+
+		result, err := tx.ExecContext(ctx, `DELETE FROM users WHERE id = $1`, userID)
+		if err != nil {
+			return errors.Wrap(err, errors.ErrDatabaseError)
+		}
+
+		rowsAffected, err := result.RowsAffected()
+		if err != nil {
+			return errors.Wrap(err, errors.ErrDatabaseError)
+		}
+
+		if rowsAffected == 0 {
+			return errors.New(errors.ErrResourceNotFound, "User not found")
+		}
+
+		return nil
+	})
+}
+
+// CreateEmailChangeRequest stores a pending email change, replacing any earlier request
+func (db *DB) CreateEmailChangeRequest(userID uuid.UUID, newEmail, tokenHash string, ttl time.Duration) error {
+	return db.Transaction(func(tx *sql.Tx) error {
+		if _, err := tx.Exec(`DELETE FROM email_change_requests WHERE user_id = $1`, userID); err != nil {
+			return errors.Wrap(err, errors.ErrDatabaseError)
+		}
+
+		query := `
+			INSERT INTO email_change_requests (user_id, new_email, token_hash, expires_at)
+			VALUES ($1, $2, $3, $4)`
+
+		if _, err := tx.Exec(query, userID, newEmail, tokenHash, time.Now().Add(ttl)); err != nil {
+			return errors.Wrap(err, errors.ErrDatabaseError)
+		}
+
+		return nil
+	})
+}
+
+// ConfirmEmailChange applies the pending email change identified by tokenHash
+// The request is consumed in the same transaction, so each token works once
+func (db *DB) ConfirmEmailChange(tokenHash string) (uuid.UUID, string, error) {
+	var userID uuid.UUID
+	var newEmail string
+
+	err := db.Transaction(func(tx *sql.Tx) error {
+		query := `
+			DELETE FROM email_change_requests
+			WHERE token_hash = $1 AND expires_at > NOW()
+			RETURNING user_id, new_email`
+
+		err := tx.QueryRow(query, tokenHash).Scan(&userID, &newEmail)
+		if err != nil {
+			if err == sql.ErrNoRows {
+				return errors.New(errors.ErrValidationFailed, "Invalid or expired confirmation token")
+			}
+			return errors.Wrap(err, errors.ErrDatabaseError)
+		}
+
+		// Following the emailed link proves ownership of the new address
+		_, err = tx.Exec(`UPDATE users SET email = $1, email_verified_at = NOW(), updated_at = NOW() WHERE id = $2`, newEmail, userID)
+		if err != nil {
+			// Another account claimed the address after the request was made
+			if err.Error() == `pq: duplicate key value violates unique constraint "users_email_key"` {
+				return errors.New(errors.ErrValidationFailed, "Email already exists")
+			}
+			return errors.Wrap(err, errors.ErrDatabaseError)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return uuid.Nil, "", err
+	}
+
+	return userID, newEmail, nil
+}
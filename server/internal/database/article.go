@@ -0,0 +1,699 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"article-chat-system/server/internal/errors"
+	"article-chat-system/server/internal/models"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// articleColumns selects an article row in the order read by scanArticle
+const articleColumns = `
+	id, url, canonical_url, COALESCE(content_hash, ''), title, content, COALESCE(raw_html, ''),
+	COALESCE(author, ''), source, status, chunk_count, metadata, published_at, fetched_at, processed_at,
+	version, refresh_interval, last_checked_at, updated_at, COALESCE(etag, ''), COALESCE(last_modified, ''),
+	COALESCE(feed_id::text, ''), COALESCE(submitted_by::text, ''), COALESCE(last_error, ''), attempts, next_retry_at`
+
+// articleSummaryColumns selects like articleColumns but leaves content and raw HTML empty
+const articleSummaryColumns = `
+	id, url, canonical_url, COALESCE(content_hash, ''), title, '', '',
+	COALESCE(author, ''), source, status, chunk_count, metadata, published_at, fetched_at, processed_at,
+	version, refresh_interval, last_checked_at, updated_at, COALESCE(etag, ''), COALESCE(last_modified, ''),
+	COALESCE(feed_id::text, ''), COALESCE(submitted_by::text, ''), COALESCE(last_error, ''), attempts, next_retry_at`
+
+// articleSorts maps the accepted sort names to their SQL expression and cursor cast
+// Articles without the date sort as -infinity, last in descending lists.
+var articleSorts = map[string]struct{ expr, cast string }{
+	"fetched_at":   {"COALESCE(fetched_at, '-infinity'::timestamp)", "timestamp"},
+	"published_at": {"COALESCE(published_at, '-infinity'::timestamp)", "timestamp"},
+	"title":        {"title", "text"},
+}
+
+// IsArticleSort reports whether sort is a supported article list sort
+func IsArticleSort(sort string) bool {
+	_, ok := articleSorts[sort]
+	return ok
+}
+
+// articleCursor is a position in an article list
+// Before cursors page backwards from the first article of a page.
+type articleCursor struct {
+	Sort   string `json:"s"`
+	Desc   bool   `json:"d"`
+	Value  string `json:"v"`
+	ID     string `json:"id"`
+	Before bool   `json:"b,omitempty"`
+}
+
+// CreateArticle stores a newly fetched article and reports whether it was stored
+// An existing article with the same canonical URL is kept unless its ingestion failed, in which
+// case it is replaced and article.ID takes over the existing ID; created is false when a live
+// article already holds the URL.
+func (db *DB) CreateArticle(ctx context.Context, article *models.Article) (bool, error) {
+	metadata, err := json.Marshal(article.Metadata)
+	if err != nil {
+		return false, errors.Wrap(err, errors.ErrInternalServer)
+	}
+
+	query := `
+		INSERT INTO articles (id, url, canonical_url, content_hash, title, content, raw_html, author,
+			source, status, chunk_count, metadata, published_at, fetched_at, processed_at, etag, last_modified, feed_id,
+			submitted_by, attempts)
+		VALUES ($1, $2, $3, NULLIF($4, ''), $5, $6, NULLIF($7, ''), NULLIF($8, ''), $9, $10, $11, $12, $13, $14, $15,
+			NULLIF($16, ''), NULLIF($17, ''), NULLIF($18, '')::uuid, NULLIF($19, '')::uuid, $20)
+		ON CONFLICT (canonical_url) DO UPDATE
+		SET url = EXCLUDED.url, content_hash = EXCLUDED.content_hash, title = EXCLUDED.title,
+			content = EXCLUDED.content, raw_html = EXCLUDED.raw_html, author = EXCLUDED.author,
+			source = EXCLUDED.source, status = EXCLUDED.status, chunk_count = EXCLUDED.chunk_count,
+			metadata = EXCLUDED.metadata, published_at = EXCLUDED.published_at,
+			fetched_at = EXCLUDED.fetched_at, processed_at = EXCLUDED.processed_at,
+			etag = EXCLUDED.etag, last_modified = EXCLUDED.last_modified, feed_id = EXCLUDED.feed_id,
+			submitted_by = EXCLUDED.submitted_by, attempts = EXCLUDED.attempts, last_error = NULL,
+			next_retry_at = NULL, retry_leased_until = NULL
+		WHERE articles.status = 'failed'
+		RETURNING id`
+
+	var id string
+	err = db.QueryRowContext(ctx, query,
+		article.ID,
+		article.URL,
+		article.CanonicalURL,
+		article.ContentHash,
+		article.Title,
+		article.Content,
+		article.RawHTML,
+		article.Author,
+		article.Source,
+		article.Status,
+		article.ChunkCount,
+		metadata,
+		zeroTimeToNull(article.PublishedAt),
+		zeroTimeToNull(article.FetchedAt),
+		zeroTimeToNull(article.ProcessedAt),
+		article.ETag,
+		article.LastModified,
+		article.FeedID,
+		article.SubmittedBy,
+		article.Attempts,
+	).Scan(&id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return false, nil
+		}
+		return false, errors.Wrap(err, errors.ErrDatabaseError)
+	}
+
+	article.ID = id
+	return true, nil
+}
+
+// FindDuplicateArticle returns a live article with the canonical URL or, when given, the content hash
+// Failed articles are not duplicates. Returns nil when there is none.
+func (db *DB) FindDuplicateArticle(ctx context.Context, canonicalURL, contentHash string) (*models.Article, error) {
+	query := `
+		SELECT ` + articleColumns + `
+		FROM articles
+		WHERE status <> 'failed' AND (canonical_url = $1 OR ($2 <> '' AND content_hash = $2))
+		ORDER BY canonical_url = $1 DESC, created_at
+		LIMIT 1`
+
+	article, err := scanArticle(db.QueryRowContext(ctx, query, canonicalURL, contentHash))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, errors.Wrap(err, errors.ErrDatabaseError)
+	}
+
+	return article, nil
+}
+
+// ExistingArticleURLs returns which of the canonical URLs already have an article in any status
+// Unlike FindDuplicateArticle, failed articles count, so feed pollers do not retry them every poll.
+func (db *DB) ExistingArticleURLs(ctx context.Context, canonicalURLs []string) (map[string]bool, error) {
+	rows, err := db.QueryContext(ctx,
+		`SELECT canonical_url FROM articles WHERE canonical_url = ANY($1)`, pq.Array(canonicalURLs))
+	if err != nil {
+		return nil, errors.Wrap(err, errors.ErrDatabaseError)
+	}
+	defer rows.Close()
+
+	existing := make(map[string]bool)
+	for rows.Next() {
+		var canonicalURL string
+		if err := rows.Scan(&canonicalURL); err != nil {
+			return nil, errors.Wrap(err, errors.ErrDatabaseError)
+		}
+		existing[canonicalURL] = true
+	}
+	if err := rows.Err(); err != nil {
+		return nil, errors.Wrap(err, errors.ErrDatabaseError)
+	}
+
+	return existing, nil
+}
+
+// GetArticle retrieves an article by ID
+func (db *DB) GetArticle(ctx context.Context, articleID uuid.UUID) (*models.Article, error) {
+	query := `SELECT ` + articleColumns + ` FROM articles WHERE id = $1`
+
+	article, err := scanArticle(db.QueryRowContext(ctx, query, articleID))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errors.New(errors.ErrArticleNotFound, "Article not found")
+		}
+		return nil, errors.Wrap(err, errors.ErrDatabaseError)
+	}
+
+	return article, nil
+}
+
+// ListArticles lists the articles matching filter
+// Returns the cursors of the neighbouring pages; a cursor from PageCursors.Prev pages backwards.
+func (db *DB) ListArticles(ctx context.Context, filter *models.ArticleFilter) ([]models.Article, PageCursors, error) {
+	var cursors PageCursors
+
+	sort, ok := articleSorts[filter.Sort]
+	if !ok {
+		return nil, cursors, errors.New(errors.ErrValidationFailed, "Unsupported sort")
+	}
+
+	where, args := articleFilterClause(filter)
+
+	direction, comparison := "DESC", "<"
+	if !filter.Desc {
+		direction, comparison = "ASC", ">"
+	}
+
+	var cursor articleCursor
+	if filter.Cursor != "" {
+		if err := DecodeCursor(filter.Cursor, &cursor); err != nil || cursor.Sort != filter.Sort || cursor.Desc != filter.Desc {
+			return nil, cursors, errors.New(errors.ErrValidationFailed, "Invalid cursor")
+		}
+
+		// Paging backwards reads the list in reverse from the cursor
+		if cursor.Before {
+			direction, comparison = reverseDirection(direction), reverseComparison(comparison)
+		}
+
+		args = append(args, cursor.Value, cursor.ID)
+		n := len(args)
+		where += fmt.Sprintf(" AND (%s, id) %s ($%d::%s, $%d::uuid)", sort.expr, comparison, n-1, sort.cast, n)
+	}
+
+	columns := articleSummaryColumns
+	if filter.IncludeContent {
+		columns = articleColumns
+	}
+
+	// One extra row tells whether another page exists
+	args = append(args, filter.Limit+1)
+	query := fmt.Sprintf(`
+		SELECT %s
+		FROM articles
+		WHERE %s
+		ORDER BY %s %s, id %s
+		LIMIT $%d`,
+		columns, where, sort.expr, direction, direction, len(args))
+
+	articles, err := db.queryArticles(ctx, query, args...)
+	if err != nil {
+		return nil, cursors, err
+	}
+
+	more := len(articles) > filter.Limit
+	if more {
+		articles = articles[:filter.Limit]
+	}
+	if cursor.Before {
+		slices.Reverse(articles)
+	}
+	if len(articles) == 0 {
+		return articles, cursors, nil
+	}
+
+	// Backwards there is always a next page (the cursor's own position); forwards there is
+	// a previous page whenever this one did not start the list
+	if more || cursor.Before {
+		if cursors.Next, err = encodeArticleCursor(filter, articles[len(articles)-1], false); err != nil {
+			return nil, cursors, err
+		}
+	}
+	if cursor.Before && more || !cursor.Before && filter.Cursor != "" {
+		if cursors.Prev, err = encodeArticleCursor(filter, articles[0], true); err != nil {
+			return nil, cursors, err
+		}
+	}
+
+	return articles, cursors, nil
+}
+
+// CountArticles returns the number of articles matching filter
+func (db *DB) CountArticles(ctx context.Context, filter *models.ArticleFilter) (int, error) {
+	where, args := articleFilterClause(filter)
+	query := `SELECT COUNT(*) FROM articles WHERE ` + where
+
+	var count int
+	if err := db.QueryRowContext(ctx, query, args...).Scan(&count); err != nil {
+		return 0, errors.Wrap(err, errors.ErrDatabaseError)
+	}
+
+	return count, nil
+}
+
+// encodeArticleCursor builds the cursor for the page after (or before) article
+func encodeArticleCursor(filter *models.ArticleFilter, article models.Article, before bool) (string, error) {
+	cursor := articleCursor{
+		Sort:   filter.Sort,
+		Desc:   filter.Desc,
+		ID:     article.ID,
+		Before: before,
+	}
+	switch filter.Sort {
+	case "fetched_at":
+		cursor.Value = cursorTime(article.FetchedAt)
+	case "published_at":
+		cursor.Value = cursorTime(article.PublishedAt)
+	case "title":
+		cursor.Value = article.Title
+	}
+
+	encoded, err := EncodeCursor(cursor)
+	if err != nil {
+		return "", errors.Wrap(err, errors.ErrInternalServer)
+	}
+	return encoded, nil
+}
+
+// cursorTime formats a sort timestamp for a cursor; a missing date is -infinity, as in articleSorts
+func cursorTime(t time.Time) string {
+	if t.IsZero() {
+		return "-infinity"
+	}
+	return t.Format(cursorTimeLayout)
+}
+
+// articleFilterClause builds the WHERE clause shared by listing and counting articles
+func articleFilterClause(filter *models.ArticleFilter) (string, []interface{}) {
+	conditions := []string{"TRUE"}
+	args := []interface{}{}
+
+	if len(filter.Statuses) > 0 {
+		args = append(args, pq.Array(filter.Statuses))
+		conditions = append(conditions, fmt.Sprintf("status = ANY($%d)", len(args)))
+	}
+	if filter.Domain != "" {
+		args = append(args, filter.Domain)
+		conditions = append(conditions, fmt.Sprintf("(domain = $%d OR domain LIKE '%%.' || $%d)", len(args), len(args)))
+	}
+	if filter.Tag != "" {
+		args = append(args, filter.Tag)
+		conditions = append(conditions, fmt.Sprintf("metadata -> 'tags' ? $%d", len(args)))
+	}
+	if filter.Source != "" {
+		args = append(args, filter.Source)
+		conditions = append(conditions, fmt.Sprintf("source = $%d", len(args)))
+	}
+	if filter.SubmittedBy != nil {
+		args = append(args, *filter.SubmittedBy)
+		conditions = append(conditions, fmt.Sprintf("submitted_by = $%d", len(args)))
+	}
+
+	// DateField is checked by the handler; only the two timestamp columns are accepted
+	dateColumn := "fetched_at"
+	if filter.DateField == "published_at" {
+		dateColumn = "published_at"
+	}
+	if filter.From != nil {
+		args = append(args, *filter.From)
+		conditions = append(conditions, fmt.Sprintf("%s >= $%d", dateColumn, len(args)))
+	}
+	if filter.To != nil {
+		args = append(args, *filter.To)
+		conditions = append(conditions, fmt.Sprintf("%s < $%d", dateColumn, len(args)))
+	}
+
+	if filter.Query != "" {
+		args = append(args, filter.Query)
+		conditions = append(conditions, fmt.Sprintf("search_tsv @@ websearch_to_tsquery('english', $%d)", len(args)))
+	}
+
+	return strings.Join(conditions, " AND "), args
+}
+
+// UpdateArticleStatus records an ingestion status transition
+// The chunk count and processed_at are set when the article is indexed, which also clears the
+// failure state.
+func (db *DB) UpdateArticleStatus(ctx context.Context, articleID uuid.UUID, status string, chunkCount int) error {
+	query := `
+		UPDATE articles
+		SET status = $2,
+			chunk_count = CASE WHEN $2 = 'indexed' THEN $3 ELSE chunk_count END,
+			processed_at = CASE WHEN $2 = 'indexed' THEN NOW() ELSE processed_at END,
+			last_error = CASE WHEN $2 = 'indexed' THEN NULL ELSE last_error END,
+			attempts = CASE WHEN $2 = 'indexed' THEN 0 ELSE attempts END,
+			next_retry_at = CASE WHEN $2 = 'indexed' THEN NULL ELSE next_retry_at END,
+			retry_leased_until = CASE WHEN $2 = 'indexed' THEN NULL ELSE retry_leased_until END
+		WHERE id = $1`
+
+	result, err := db.ExecContext(ctx, query, articleID, status, chunkCount)
+	if err != nil {
+		return errors.Wrap(err, errors.ErrDatabaseError)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return errors.Wrap(err, errors.ErrDatabaseError)
+	}
+
+	if rowsAffected == 0 {
+		return errors.New(errors.ErrArticleNotFound, "Article not found")
+	}
+
+	return nil
+}
+
+// RecordArticleFailure marks an article failed with the error and its attempt count
+// retryDelay schedules the next automatic retry in seconds; 0 schedules none.
+func (db *DB) RecordArticleFailure(ctx context.Context, articleID uuid.UUID, message string, attempts, retryDelay int) error {
+	query := `
+		UPDATE articles
+		SET status = 'failed', last_error = $2, attempts = $3,
+			next_retry_at = CASE WHEN $4 > 0 THEN NOW() + make_interval(secs => $4) END,
+			retry_leased_until = NULL
+		WHERE id = $1`
+
+	result, err := db.ExecContext(ctx, query, articleID, message, attempts, retryDelay)
+	if err != nil {
+		return errors.Wrap(err, errors.ErrDatabaseError)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return errors.Wrap(err, errors.ErrDatabaseError)
+	}
+
+	if rowsAffected == 0 {
+		return errors.New(errors.ErrArticleNotFound, "Article not found")
+	}
+
+	return nil
+}
+
+// ClaimArticlesForRetry returns up to limit failed articles whose retry is due
+// They are leased and their next retry moves lease seconds ahead, so concurrent schedulers
+// and manual retries never claim the same article and an interrupted retry runs again later.
+func (db *DB) ClaimArticlesForRetry(ctx context.Context, lease, limit int) ([]models.Article, error) {
+	query := `
+		UPDATE articles
+		SET next_retry_at = NOW() + make_interval(secs => $1),
+			retry_leased_until = NOW() + make_interval(secs => $1)
+		WHERE id IN (
+			SELECT id FROM articles
+			WHERE status = 'failed' AND next_retry_at <= NOW()
+				AND (retry_leased_until IS NULL OR retry_leased_until <= NOW())
+			ORDER BY next_retry_at
+			LIMIT $2
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING ` + articleColumns
+
+	return db.queryArticles(ctx, query, lease, limit)
+}
+
+// ClaimArticleForRetry leases one failed article for lease seconds, whether or not its
+// automatic retry is due. It returns nil when the article is no longer failed or a retry
+// already holds the lease; recording the outcome of the retry releases it.
+func (db *DB) ClaimArticleForRetry(ctx context.Context, articleID uuid.UUID, lease int) (*models.Article, error) {
+	query := `
+		UPDATE articles
+		SET retry_leased_until = NOW() + make_interval(secs => $2)
+		WHERE id = $1 AND status = 'failed'
+			AND (retry_leased_until IS NULL OR retry_leased_until <= NOW())
+		RETURNING ` + articleColumns
+
+	article, err := scanArticle(db.QueryRowContext(ctx, query, articleID, lease))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, errors.Wrap(err, errors.ErrDatabaseError)
+	}
+
+	return article, nil
+}
+
+// ClaimArticlesForRefresh returns up to limit indexed articles due for a refresh check and
+// stamps them as checked, so concurrent schedulers never claim the same article
+// defaultInterval applies to articles without their own interval; 0 disables those. Uploaded
+// documents have no page to re-fetch.
+func (db *DB) ClaimArticlesForRefresh(ctx context.Context, defaultInterval, limit int) ([]models.Article, error) {
+	query := `
+		UPDATE articles
+		SET last_checked_at = NOW()
+		WHERE id IN (
+			SELECT id FROM articles
+			WHERE status = 'indexed'
+				AND source <> 'upload'
+				AND COALESCE(refresh_interval, $1) > 0
+				AND COALESCE(last_checked_at, processed_at, created_at)
+					+ make_interval(secs => COALESCE(refresh_interval, $1)) <= NOW()
+			ORDER BY COALESCE(last_checked_at, processed_at, created_at)
+			LIMIT $2
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING ` + articleColumns
+
+	return db.queryArticles(ctx, query, defaultInterval, limit)
+}
+
+// UpdateArticleValidators stores new HTTP cache validators for unchanged content
+func (db *DB) UpdateArticleValidators(ctx context.Context, articleID uuid.UUID, etag, lastModified string) error {
+	query := `UPDATE articles SET etag = NULLIF($2, ''), last_modified = NULLIF($3, '') WHERE id = $1`
+
+	if _, err := db.ExecContext(ctx, query, articleID, etag, lastModified); err != nil {
+		return errors.Wrap(err, errors.ErrDatabaseError)
+	}
+	return nil
+}
+
+// UpdateArticleContent replaces an article's content after a re-index and returns the new version
+// The replaced content is archived in article_versions.
+func (db *DB) UpdateArticleContent(ctx context.Context, article *models.Article, chunkCount int) (int, error) {
+	metadata, err := json.Marshal(article.Metadata)
+	if err != nil {
+		return 0, errors.Wrap(err, errors.ErrInternalServer)
+	}
+
+	var version int
+	err = db.Transaction(func(tx *sql.Tx) error {
+		_, err := tx.ExecContext(ctx, `
+			INSERT INTO article_versions (article_id, version, title, content, content_hash, fetched_at)
+			SELECT id, version, title, content, content_hash, fetched_at FROM articles WHERE id = $1`,
+			article.ID)
+		if err != nil {
+			return errors.Wrap(err, errors.ErrDatabaseError)
+		}
+
+		err = tx.QueryRowContext(ctx, `
+			UPDATE articles
+			SET content_hash = NULLIF($2, ''), title = $3, content = $4, raw_html = NULLIF($5, ''),
+				author = NULLIF($6, ''), metadata = $7, published_at = $8, fetched_at = $9,
+				etag = NULLIF($10, ''), last_modified = NULLIF($11, ''), chunk_count = $12,
+				status = 'indexed', processed_at = NOW(), version = version + 1
+			WHERE id = $1
+			RETURNING version`,
+			article.ID,
+			article.ContentHash,
+			article.Title,
+			article.Content,
+			article.RawHTML,
+			article.Author,
+			metadata,
+			zeroTimeToNull(article.PublishedAt),
+			zeroTimeToNull(article.FetchedAt),
+			article.ETag,
+			article.LastModified,
+			chunkCount,
+		).Scan(&version)
+		if err != nil {
+			if err == sql.ErrNoRows {
+				return errors.New(errors.ErrArticleNotFound, "Article not found")
+			}
+			return errors.Wrap(err, errors.ErrDatabaseError)
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return version, nil
+}
+
+// SetArticleRefreshInterval sets the seconds between refresh checks; nil restores the default
+func (db *DB) SetArticleRefreshInterval(ctx context.Context, articleID uuid.UUID, interval *int) error {
+	result, err := db.ExecContext(ctx, `UPDATE articles SET refresh_interval = $2 WHERE id = $1`, articleID, interval)
+	if err != nil {
+		return errors.Wrap(err, errors.ErrDatabaseError)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return errors.Wrap(err, errors.ErrDatabaseError)
+	}
+
+	if rowsAffected == 0 {
+		return errors.New(errors.ErrArticleNotFound, "Article not found")
+	}
+
+	return nil
+}
+
+// GetArticleVersions lists an article's versions, the current one first
+func (db *DB) GetArticleVersions(ctx context.Context, articleID uuid.UUID) ([]models.ArticleVersion, error) {
+	query := `
+		SELECT version, title, COALESCE(content_hash, ''), fetched_at, NULL::timestamp
+		FROM articles WHERE id = $1
+		UNION ALL
+		SELECT version, title, COALESCE(content_hash, ''), fetched_at, replaced_at
+		FROM article_versions WHERE article_id = $1
+		ORDER BY version DESC`
+
+	rows, err := db.QueryContext(ctx, query, articleID)
+	if err != nil {
+		return nil, errors.Wrap(err, errors.ErrDatabaseError)
+	}
+	defer rows.Close()
+
+	versions := []models.ArticleVersion{}
+	for rows.Next() {
+		var version models.ArticleVersion
+		var fetchedAt, replacedAt sql.NullTime
+		if err := rows.Scan(&version.Version, &version.Title, &version.ContentHash, &fetchedAt, &replacedAt); err != nil {
+			return nil, errors.Wrap(err, errors.ErrDatabaseError)
+		}
+		version.FetchedAt = fetchedAt.Time
+		version.ReplacedAt = NullTimeToTime(replacedAt)
+		versions = append(versions, version)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, errors.Wrap(err, errors.ErrDatabaseError)
+	}
+
+	if len(versions) == 0 {
+		return nil, errors.New(errors.ErrArticleNotFound, "Article not found")
+	}
+
+	return versions, nil
+}
+
+// DeleteArticle removes an article
+func (db *DB) DeleteArticle(ctx context.Context, articleID uuid.UUID) error {
+	result, err := db.ExecContext(ctx, `DELETE FROM articles WHERE id = $1`, articleID)
+	if err != nil {
+		return errors.Wrap(err, errors.ErrDatabaseError)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return errors.Wrap(err, errors.ErrDatabaseError)
+	}
+
+	if rowsAffected == 0 {
+		return errors.New(errors.ErrArticleNotFound, "Article not found")
+	}
+
+	return nil
+}
+
+// queryArticles runs a query selecting articleColumns
+func (db *DB) queryArticles(ctx context.Context, query string, args ...interface{}) ([]models.Article, error) {
+	rows, err := db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, errors.Wrap(err, errors.ErrDatabaseError)
+	}
+	defer rows.Close()
+
+	articles := []models.Article{}
+	for rows.Next() {
+		article, err := scanArticle(rows)
+		if err != nil {
+			return nil, errors.Wrap(err, errors.ErrDatabaseError)
+		}
+		articles = append(articles, *article)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, errors.Wrap(err, errors.ErrDatabaseError)
+	}
+
+	return articles, nil
+}
+
+// scanArticle reads a row selected with articleColumns
+func scanArticle(row interface{ Scan(...interface{}) error }) (*models.Article, error) {
+	var article models.Article
+	var metadata []byte
+	var publishedAt, fetchedAt, processedAt, lastCheckedAt, nextRetryAt sql.NullTime
+	var refreshInterval sql.NullInt64
+
+	err := row.Scan(
+		&article.ID,
+		&article.URL,
+		&article.CanonicalURL,
+		&article.ContentHash,
+		&article.Title,
+		&article.Content,
+		&article.RawHTML,
+		&article.Author,
+		&article.Source,
+		&article.Status,
+		&article.ChunkCount,
+		&metadata,
+		&publishedAt,
+		&fetchedAt,
+		&processedAt,
+		&article.Version,
+		&refreshInterval,
+		&lastCheckedAt,
+		&article.UpdatedAt,
+		&article.ETag,
+		&article.LastModified,
+		&article.FeedID,
+		&article.SubmittedBy,
+		&article.LastError,
+		&article.Attempts,
+		&nextRetryAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	article.PublishedAt = publishedAt.Time
+	article.FetchedAt = fetchedAt.Time
+	article.ProcessedAt = processedAt.Time
+	article.LastCheckedAt = NullTimeToTime(lastCheckedAt)
+	article.NextRetryAt = NullTimeToTime(nextRetryAt)
+	if refreshInterval.Valid {
+		interval := int(refreshInterval.Int64)
+		article.RefreshInterval = &interval
+	}
+	if err := json.Unmarshal(metadata, &article.Metadata); err != nil {
+		return nil, err
+	}
+
+	return &article, nil
+}
+
+// zeroTimeToNull stores unset model timestamps as NULL
+func zeroTimeToNull(t time.Time) sql.NullTime {
+	return sql.NullTime{Time: t, Valid: !t.IsZero()}
+}
@@ -0,0 +1,278 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+
+	"article-chat-system/server/internal/errors"
+	"article-chat-system/server/internal/models"
+	"github.com/google/uuid"
+)
+
+// Messages form a tree through parent_id. Editing a user message or regenerating an answer
+// adds a sibling, and conversations.active_leaf_id selects which branch is shown and sent
+// to the RAG service as history. conversations.message_count is the length of that branch.
+
+// branchPathCTE walks from the message matched by the anchor condition up to the root
+// Siblings created in the same transaction share created_at, so user messages sort first
+const branchPathCTE = `
+	WITH RECURSIVE path AS (
+		SELECT m.*, 0 AS depth FROM messages m WHERE %s
+		UNION ALL
+		SELECT p.*, path.depth + 1 FROM messages p JOIN path ON p.id = path.parent_id
+	)`
+
+// Anchors for branchPathCTE: a specific message, or the conversation's active leaf
+const (
+	messageAnchor    = `m.id = $1`
+	activeLeafAnchor = `m.id = (SELECT active_leaf_id FROM conversations WHERE id = $1)`
+)
+
+// branchPathColumns selects path messages with their position among siblings
+const branchPathColumns = `
+	SELECT path.id, path.conversation_id, path.parent_id, path.role, path.content, path.metadata, path.created_at,
+	       (SELECT COUNT(*) FROM messages s
+	        WHERE s.conversation_id = path.conversation_id
+	          AND s.parent_id IS NOT DISTINCT FROM path.parent_id
+	          AND (s.created_at, (s.role = 'assistant'), s.id) <= (path.created_at, (path.role = 'assistant'), path.id)) AS branch_index,
+	       (SELECT COUNT(*) FROM messages s
+	        WHERE s.conversation_id = path.conversation_id
+	          AND s.parent_id IS NOT DISTINCT FROM path.parent_id) AS branch_count
+	FROM path`
+
+// GetMessagePath returns the branch ending at messageID, ordered from the first message
+func (db *DB) GetMessagePath(ctx context.Context, messageID uuid.UUID) ([]models.Message, error) {
+	query := fmt.Sprintf(branchPathCTE, messageAnchor) + branchPathColumns + ` ORDER BY path.depth DESC`
+
+	rows, err := db.QueryContext(ctx, query, messageID)
+	if err != nil {
+		return nil, errors.Wrap(err, errors.ErrDatabaseError)
+	}
+	defer rows.Close()
+
+	return scanBranchMessages(rows)
+}
+
+// GetMessageSiblings returns all alternatives for a message (itself included), oldest first
+func (db *DB) GetMessageSiblings(ctx context.Context, messageID uuid.UUID) ([]models.Message, error) {
+	query := `
+		SELECT s.id, s.conversation_id, s.parent_id, s.role, s.content, s.metadata, s.created_at,
+		       ROW_NUMBER() OVER (ORDER BY s.created_at, (s.role = 'assistant'), s.id) AS branch_index,
+		       COUNT(*) OVER () AS branch_count
+		FROM messages s
+		JOIN messages m ON m.id = $1
+		WHERE s.conversation_id = m.conversation_id
+		  AND s.parent_id IS NOT DISTINCT FROM m.parent_id
+		ORDER BY branch_index`
+
+	rows, err := db.QueryContext(ctx, query, messageID)
+	if err != nil {
+		return nil, errors.Wrap(err, errors.ErrDatabaseError)
+	}
+	defer rows.Close()
+
+	return scanBranchMessages(rows)
+}
+
+// SetActiveBranch makes the branch through messageID active
+// From messageID the newest reply is followed at every level, so switching to an earlier
+// message restores the most recent continuation of that branch. Returns the new leaf.
+func (db *DB) SetActiveBranch(ctx context.Context, conversationID, messageID uuid.UUID) (uuid.UUID, error) {
+	query := `
+		WITH RECURSIVE descent AS (
+			SELECT id, 0 AS depth FROM messages WHERE id = $1 AND conversation_id = $2
+			UNION ALL
+			SELECT child.id, descent.depth + 1
+			FROM descent
+			CROSS JOIN LATERAL (
+				SELECT c.id FROM messages c
+				WHERE c.parent_id = descent.id
+				ORDER BY c.created_at DESC, (c.role = 'assistant') DESC, c.id DESC
+				LIMIT 1
+			) child
+		)
+		SELECT id FROM descent ORDER BY depth DESC LIMIT 1`
+
+	var leafID uuid.UUID
+	err := db.Transaction(func(tx *sql.Tx) error {
+		if err := lockConversation(ctx, tx, conversationID); err != nil {
+			return err
+		}
+
+		err := tx.QueryRowContext(ctx, query, messageID, conversationID).Scan(&leafID)
+		if err != nil {
+			if err == sql.ErrNoRows {
+				return errors.New(errors.ErrResourceNotFound, "Message not found")
+			}
+			return errors.Wrap(err, errors.ErrDatabaseError)
+		}
+
+		return setActiveLeaf(ctx, tx, conversationID, leafID)
+	})
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	return leafID, nil
+}
+
+// CreateBranchPair adds a user message and its answer under parentID (nil for a new first
+// message) and makes the new answer the active leaf
+func (db *DB) CreateBranchPair(ctx context.Context, conversationID uuid.UUID, parentID *uuid.UUID, userContent, assistantContent string, assistantMetadata map[string]interface{}) (*models.Message, *models.Message, error) {
+	var userMessage, assistantMessage *models.Message
+
+	err := db.Transaction(func(tx *sql.Tx) error {
+		if err := lockConversation(ctx, tx, conversationID); err != nil {
+			return err
+		}
+
+		var err error
+		userMessage, err = insertBranchMessage(ctx, tx, conversationID, parentID, "user", userContent, nil)
+		if err != nil {
+			return err
+		}
+
+		assistantMessage, err = insertBranchMessage(ctx, tx, conversationID, &userMessage.ID, "assistant", assistantContent, assistantMetadata)
+		if err != nil {
+			return err
+		}
+
+		return setActiveLeaf(ctx, tx, conversationID, assistantMessage.ID)
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return userMessage, assistantMessage, nil
+}
+
+// CreateRegeneratedReply adds an alternative answer to a user message and makes it the active leaf
+func (db *DB) CreateRegeneratedReply(ctx context.Context, conversationID, userMessageID uuid.UUID, content string, metadata map[string]interface{}) (*models.Message, error) {
+	var reply *models.Message
+
+	err := db.Transaction(func(tx *sql.Tx) error {
+		if err := lockConversation(ctx, tx, conversationID); err != nil {
+			return err
+		}
+
+		var err error
+		reply, err = insertBranchMessage(ctx, tx, conversationID, &userMessageID, "assistant", content, metadata)
+		if err != nil {
+			return err
+		}
+
+		return setActiveLeaf(ctx, tx, conversationID, reply.ID)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return reply, nil
+}
+
+// insertBranchMessage inserts a message under parentID inside tx
+func insertBranchMessage(ctx context.Context, tx *sql.Tx, conversationID uuid.UUID, parentID *uuid.UUID, role, content string, metadata map[string]interface{}) (*models.Message, error) {
+	var metadataJSON []byte
+	if metadata != nil {
+		var err error
+		metadataJSON, err = json.Marshal(metadata)
+		if err != nil {
+			return nil, errors.Wrap(err, errors.ErrInvalidDataType)
+		}
+	}
+
+	query := `
+		INSERT INTO messages (conversation_id, parent_id, role, content, metadata)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id, created_at`
+
+	message := &models.Message{
+		ConversationID: conversationID,
+		ParentID:       parentID,
+		Role:           role,
+		Content:        content,
+		Metadata:       metadata,
+	}
+
+	err := tx.QueryRowContext(ctx, query, conversationID, parentID, role, content, metadataJSON).Scan(&message.ID, &message.CreatedAt)
+	if err != nil {
+		return nil, errors.Wrap(err, errors.ErrDatabaseError)
+	}
+
+	return message, nil
+}
+
+// lockConversation locks the conversation row inside tx, like CreateMessagePair, so
+// concurrent edits, regenerations and branch switches apply one after another
+func lockConversation(ctx context.Context, tx *sql.Tx, conversationID uuid.UUID) error {
+	var id uuid.UUID
+	err := tx.QueryRowContext(ctx, `SELECT id FROM conversations WHERE id = $1 FOR UPDATE`, conversationID).Scan(&id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return errors.New(errors.ErrResourceNotFound, "Conversation not found")
+		}
+		return errors.Wrap(err, errors.ErrDatabaseError)
+	}
+	return nil
+}
+
+// setActiveLeaf points the conversation at a new leaf message inside tx and recounts
+// message_count as the length of the branch ending there
+func setActiveLeaf(ctx context.Context, tx *sql.Tx, conversationID, leafID uuid.UUID) error {
+	query := fmt.Sprintf(branchPathCTE, `m.id = $2`) + `
+		UPDATE conversations
+		SET active_leaf_id = $2, message_count = (SELECT COUNT(*) FROM path), updated_at = NOW()
+		WHERE id = $1`
+
+	_, err := tx.ExecContext(ctx, query, conversationID, leafID)
+	if err != nil {
+		return errors.Wrap(err, errors.ErrDatabaseError)
+	}
+	return nil
+}
+
+// scanBranchMessages reads rows produced by branchPathColumns or GetMessageSiblings
+func scanBranchMessages(rows *sql.Rows) ([]models.Message, error) {
+	messages := []models.Message{}
+	for rows.Next() {
+		var message models.Message
+		var parentID uuid.NullUUID
+		var metadataStr sql.NullString
+
+		err := rows.Scan(
+			&message.ID,
+			&message.ConversationID,
+			&parentID,
+			&message.Role,
+			&message.Content,
+			&metadataStr,
+			&message.CreatedAt,
+			&message.BranchIndex,
+			&message.BranchCount,
+		)
+		if err != nil {
+			return nil, errors.Wrap(err, errors.ErrDatabaseError)
+		}
+
+		if parentID.Valid {
+			message.ParentID = &parentID.UUID
+		}
+
+		// Parse metadata if exists
+		if metadataStr.Valid {
+			if err := json.Unmarshal([]byte(metadataStr.String), &message.Metadata); err != nil {
+				return nil, errors.Wrap(err, errors.ErrDatabaseError)
+			}
+		}
+
+		messages = append(messages, message)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, errors.Wrap(err, errors.ErrDatabaseError)
+	}
+
+	return messages, nil
+}
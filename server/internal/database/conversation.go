@@ -3,55 +3,81 @@ package database
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
 
 	"article-chat-system/server/internal/errors"
 	"article-chat-system/server/internal/models"
 	"github.com/google/uuid"
+	"github.com/lib/pq"
 )
 
+// conversationColumns selects a conversation row aliased as c, including its tags as a JSON array
+const conversationColumns = `
+	c.id, c.user_id, COALESCE(c.title, ''), c.created_at, c.updated_at, c.message_count,
+	c.pinned_at, c.archived_at, c.folder_id, c.title_manual,
+	COALESCE((
+		SELECT json_agg(json_build_object('id', t.id, 'name', t.name) ORDER BY t.name)
+		FROM conversation_tags ct JOIN tags t ON t.id = ct.tag_id
+		WHERE ct.conversation_id = c.id
+	), '[]')`
+
+// conversationSorts maps the accepted sort names to their SQL expression and cursor cast
+var conversationSorts = map[string]struct{ expr, cast string }{
+	"updated_at":    {"c.updated_at", "timestamp"},
+	"created_at":    {"c.created_at", "timestamp"},
+	"title":         {"COALESCE(c.title, '')", "text"},
+	"message_count": {"c.message_count", "int"},
+}
+
+// IsConversationSort reports whether sort is a supported conversation list sort
+func IsConversationSort(sort string) bool {
+	_, ok := conversationSorts[sort]
+	return ok
+}
+
+// conversationCursor is a position in a conversation list
+// Pinned conversations always come first, so the pinned flag is part of the key.
+// Before cursors page backwards from the first conversation of a page.
+type conversationCursor struct {
+	Sort   string    `json:"s"`
+	Desc   bool      `json:"d"`
+	Pinned bool      `json:"p"`
+	Value  string    `json:"v"`
+	ID     uuid.UUID `json:"id"`
+	Before bool      `json:"b,omitempty"`
+}
+
+// cursorTimeLayout keeps the microsecond precision of PostgreSQL timestamps
+const cursorTimeLayout = "2006-01-02 15:04:05.999999"
+
 // CreateConversation creates a new conversation for a user
-func (db *DB) CreateConversation(ctx context.Context, userID uuid.UUID, title string) (*models.Conversation, error) {
+// manual marks a title chosen by the user, which automatic titles never replace
+func (db *DB) CreateConversation(ctx context.Context, userID uuid.UUID, title string, manual bool) (*models.Conversation, error) {
 	query := `
-		INSERT INTO conversations (user_id, title)
-		VALUES ($1, $2)
-		RETURNING id, user_id, title, created_at, updated_at, message_count
-	`
-
-	var conv models.Conversation
-	err := db.QueryRowContext(ctx, query, userID, title).Scan(
-		&conv.ID,
-		&conv.UserID,
-		&conv.Title,
-		&conv.CreatedAt,
-		&conv.UpdatedAt,
-		&conv.MessageCount,
-	)
+		WITH c AS (
+			INSERT INTO conversations (user_id, title, title_manual)
+			VALUES ($1, $2, $3)
+			RETURNING *
+		)
+		SELECT ` + conversationColumns + ` FROM c`
 
+	conv, err := scanConversation(db.QueryRowContext(ctx, query, userID, title, manual))
 	if err != nil {
 		return nil, errors.Wrap(err, errors.ErrDatabaseError)
 	}
 
-	return &conv, nil
+	return conv, nil
 }
 
 // GetConversation retrieves a conversation by ID
 func (db *DB) GetConversation(ctx context.Context, conversationID uuid.UUID) (*models.Conversation, error) {
-	query := `
-		SELECT id, user_id, title, created_at, updated_at, message_count
-		FROM conversations
-		WHERE id = $1
-	`
-
-	var conv models.Conversation
-	err := db.QueryRowContext(ctx, query, conversationID).Scan(
-		&conv.ID,
-		&conv.UserID,
-		&conv.Title,
-		&conv.CreatedAt,
-		&conv.UpdatedAt,
-		&conv.MessageCount,
-	)
+	query := `SELECT ` + conversationColumns + ` FROM conversations c WHERE c.id = $1`
 
+	conv, err := scanConversation(db.QueryRowContext(ctx, query, conversationID))
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return nil, errors.New(errors.ErrResourceNotFound, "Conversation not found")
@@ -59,47 +85,210 @@ func (db *DB) GetConversation(ctx context.Context, conversationID uuid.UUID) (*m
 		return nil, errors.Wrap(err, errors.ErrDatabaseError)
 	}
 
-	return &conv, nil
+	return conv, nil
 }
 
-// GetUserConversations retrieves all conversations for a user, ordered by updated_at desc
-func (db *DB) GetUserConversations(ctx context.Context, userID uuid.UUID, limit, offset int) ([]models.Conversation, error) {
-	query := `
-		SELECT id, user_id, title, created_at, updated_at, message_count
-		FROM conversations
-		WHERE user_id = $1
-		ORDER BY updated_at DESC
-		LIMIT $2 OFFSET $3
-	`
+// GetUserConversations lists a user's conversations matching filter, pinned conversations first
+// Returns the cursors of the neighbouring pages; a cursor from PageCursors.Prev pages backwards.
+func (db *DB) GetUserConversations(ctx context.Context, userID uuid.UUID, filter *models.ConversationFilter) ([]models.Conversation, PageCursors, error) {
+	var cursors PageCursors
+
+	sort, ok := conversationSorts[filter.Sort]
+	if !ok {
+		return nil, cursors, errors.New(errors.ErrValidationFailed, "Unsupported sort")
+	}
+
+	where, args := conversationFilterClause(userID, filter)
+
+	// Each key runs in the list direction; the pinned key is inverted for ascending lists
+	pinnedKey, direction, comparison := "(c.pinned_at IS NOT NULL)", "DESC", "<"
+	if !filter.Desc {
+		pinnedKey, direction, comparison = "(c.pinned_at IS NULL)", "ASC", ">"
+	}
+
+	var cursor conversationCursor
+	if filter.Cursor != "" {
+		if err := DecodeCursor(filter.Cursor, &cursor); err != nil || cursor.Sort != filter.Sort || cursor.Desc != filter.Desc {
+			return nil, cursors, errors.New(errors.ErrValidationFailed, "Invalid cursor")
+		}
+
+		// Paging backwards reads the list in reverse from the cursor
+		if cursor.Before {
+			direction, comparison = reverseDirection(direction), reverseComparison(comparison)
+		}
+
+		args = append(args, cursor.Pinned, cursor.Value, cursor.ID)
+		n := len(args)
+		where += fmt.Sprintf(" AND (%s, %s, c.id) %s ($%d, $%d::%s, $%d)",
+			pinnedKey, sort.expr, comparison, n-2, n-1, sort.cast, n)
+	}
 
-	rows, err := db.QueryContext(ctx, query, userID, limit, offset)
+	// One extra row tells whether another page exists
+	args = append(args, filter.Limit+1)
+	query := fmt.Sprintf(`
+		SELECT %s
+		FROM conversations c
+		WHERE %s
+		ORDER BY %s %s, %s %s, c.id %s
+		LIMIT $%d`,
+		conversationColumns, where, pinnedKey, direction, sort.expr, direction, direction, len(args))
+	if filter.Cursor == "" && filter.Offset > 0 {
+		args = append(args, filter.Offset)
+		query += fmt.Sprintf(" OFFSET $%d", len(args))
+	}
+
+	rows, err := db.QueryContext(ctx, query, args...)
 	if err != nil {
-		return nil, errors.Wrap(err, errors.ErrDatabaseError)
+		return nil, cursors, errors.Wrap(err, errors.ErrDatabaseError)
 	}
 	defer rows.Close()
 
-	var conversations []models.Conversation
+	conversations := []models.Conversation{}
 	for rows.Next() {
-		var conv models.Conversation
-		err := rows.Scan(
-			&conv.ID,
-			&conv.UserID,
-			&conv.Title,
-			&conv.CreatedAt,
-			&conv.UpdatedAt,
-			&conv.MessageCount,
-		)
+		conv, err := scanConversation(rows)
 		if err != nil {
-			return nil, errors.Wrap(err, errors.ErrDatabaseError)
+			return nil, cursors, errors.Wrap(err, errors.ErrDatabaseError)
 		}
-		conversations = append(conversations, conv)
+		conversations = append(conversations, *conv)
 	}
 
 	if err = rows.Err(); err != nil {
-		return nil, errors.Wrap(err, errors.ErrDatabaseError)
+		return nil, cursors, errors.Wrap(err, errors.ErrDatabaseError)
+	}
+
+	more := len(conversations) > filter.Limit
+	if more {
+		conversations = conversations[:filter.Limit]
+	}
+	if cursor.Before {
+		slices.Reverse(conversations)
+	}
+	if len(conversations) == 0 {
+		return conversations, cursors, nil
 	}
 
-	return conversations, nil
+	// Backwards there is always a next page (the cursor's own position); forwards there is
+	// a previous page whenever this one did not start the list
+	hasNext := more || cursor.Before
+	hasPrev := cursor.Before && more || !cursor.Before && (filter.Cursor != "" || filter.Offset > 0)
+
+	if hasNext {
+		if cursors.Next, err = encodeConversationCursor(filter, conversations[len(conversations)-1], false); err != nil {
+			return nil, cursors, err
+		}
+	}
+	if hasPrev {
+		if cursors.Prev, err = encodeConversationCursor(filter, conversations[0], true); err != nil {
+			return nil, cursors, err
+		}
+	}
+
+	return conversations, cursors, nil
+}
+
+// encodeConversationCursor builds the cursor for the page after (or before) conv
+func encodeConversationCursor(filter *models.ConversationFilter, conv models.Conversation, before bool) (string, error) {
+	cursor := conversationCursor{
+		Sort:   filter.Sort,
+		Desc:   filter.Desc,
+		Pinned: conv.Pinned == filter.Desc,
+		ID:     conv.ID,
+		Before: before,
+	}
+	switch filter.Sort {
+	case "updated_at":
+		cursor.Value = conv.UpdatedAt.Format(cursorTimeLayout)
+	case "created_at":
+		cursor.Value = conv.CreatedAt.Format(cursorTimeLayout)
+	case "title":
+		cursor.Value = conv.Title
+	case "message_count":
+		cursor.Value = strconv.Itoa(conv.MessageCount)
+	}
+
+	encoded, err := EncodeCursor(cursor)
+	if err != nil {
+		return "", errors.Wrap(err, errors.ErrInternalServer)
+	}
+	return encoded, nil
+}
+
+// conversationFilterClause builds the WHERE clause shared by listing and counting conversations
+func conversationFilterClause(userID uuid.UUID, filter *models.ConversationFilter) (string, []interface{}) {
+	conditions := []string{"c.user_id = $1"}
+	args := []interface{}{userID}
+
+	switch filter.Archived {
+	case models.ArchivedOnly:
+		conditions = append(conditions, "c.archived_at IS NOT NULL")
+	case models.ArchivedInclude:
+	default:
+		conditions = append(conditions, "c.archived_at IS NULL")
+	}
+
+	if filter.Pinned != nil {
+		if *filter.Pinned {
+			conditions = append(conditions, "c.pinned_at IS NOT NULL")
+		} else {
+			conditions = append(conditions, "c.pinned_at IS NULL")
+		}
+	}
+
+	if filter.Unfiled {
+		conditions = append(conditions, "c.folder_id IS NULL")
+	} else if filter.FolderID != nil {
+		args = append(args, *filter.FolderID)
+		conditions = append(conditions, fmt.Sprintf("c.folder_id = $%d", len(args)))
+	}
+
+	if len(filter.TagIDs) > 0 {
+		args = append(args, pq.Array(filter.TagIDs), len(filter.TagIDs))
+		conditions = append(conditions, fmt.Sprintf(`c.id IN (
+			SELECT conversation_id FROM conversation_tags
+			WHERE tag_id = ANY($%d::uuid[])
+			GROUP BY conversation_id
+			HAVING COUNT(*) = $%d)`, len(args)-1, len(args)))
+	}
+
+	return strings.Join(conditions, " AND "), args
+}
+
+// scanConversation reads a row selected with conversationColumns
+func scanConversation(row interface{ Scan(...interface{}) error }) (*models.Conversation, error) {
+	var conv models.Conversation
+	var pinnedAt, archivedAt sql.NullTime
+	var folderID uuid.NullUUID
+	var tags []byte
+
+	err := row.Scan(
+		&conv.ID,
+		&conv.UserID,
+		&conv.Title,
+		&conv.CreatedAt,
+		&conv.UpdatedAt,
+		&conv.MessageCount,
+		&pinnedAt,
+		&archivedAt,
+		&folderID,
+		&conv.TitleManual,
+		&tags,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	conv.PinnedAt = NullTimeToTime(pinnedAt)
+	conv.ArchivedAt = NullTimeToTime(archivedAt)
+	conv.Pinned = conv.PinnedAt != nil
+	conv.Archived = conv.ArchivedAt != nil
+	if folderID.Valid {
+		conv.FolderID = &folderID.UUID
+	}
+	if err := json.Unmarshal(tags, &conv.Tags); err != nil {
+		return nil, err
+	}
+
+	return &conv, nil
 }
 
 // GetConversationWithMessages retrieves a conversation with all its messages
@@ -123,32 +312,30 @@ func (db *DB) GetConversationWithMessages(ctx context.Context, conversationID uu
 }
 
 // UpdateConversation updates a conversation's title
-func (db *DB) UpdateConversation(ctx context.Context, conversationID uuid.UUID, title string) (*models.Conversation, error) {
+// A manual update marks the title as chosen by the user. An automatic update (manual false) leaves
+// user-chosen titles untouched and then returns the conversation unchanged.
+func (db *DB) UpdateConversation(ctx context.Context, conversationID uuid.UUID, title string, manual bool) (*models.Conversation, error) {
 	query := `
-		UPDATE conversations
-		SET title = $2, updated_at = NOW()
-		WHERE id = $1
-		RETURNING id, user_id, title, created_at, updated_at, message_count
-	`
-
-	var conv models.Conversation
-	err := db.QueryRowContext(ctx, query, conversationID, title).Scan(
-		&conv.ID,
-		&conv.UserID,
-		&conv.Title,
-		&conv.CreatedAt,
-		&conv.UpdatedAt,
-		&conv.MessageCount,
-	)
+		WITH c AS (
+			UPDATE conversations
+			SET title = $2, title_manual = title_manual OR $3, updated_at = NOW()
+			WHERE id = $1 AND ($3 OR NOT title_manual)
+			RETURNING *
+		)
+		SELECT ` + conversationColumns + ` FROM c`
 
+	conv, err := scanConversation(db.QueryRowContext(ctx, query, conversationID, title, manual))
 	if err != nil {
 		if err == sql.ErrNoRows {
+			if !manual {
+				return db.GetConversation(ctx, conversationID)
+			}
 			return nil, errors.New(errors.ErrResourceNotFound, "Conversation not found")
 		}
 		return nil, errors.Wrap(err, errors.ErrDatabaseError)
 	}
 
-	return &conv, nil
+	return conv, nil
 }
 
 // DeleteConversation deletes a conversation and all its messages
@@ -204,12 +391,13 @@ func (db *DB) IncrementMessageCount(ctx context.Context, conversationID uuid.UUI
 	return nil
 }
 
-// GetConversationCount returns the total number of conversations for a user
-func (db *DB) GetConversationCount(ctx context.Context, userID uuid.UUID) (int, error) {
-	query := `SELECT COUNT(*) FROM conversations WHERE user_id = $1`
+// GetConversationCount returns the number of a user's conversations matching filter
+func (db *DB) GetConversationCount(ctx context.Context, userID uuid.UUID, filter *models.ConversationFilter) (int, error) {
+	where, args := conversationFilterClause(userID, filter)
+	query := `SELECT COUNT(*) FROM conversations c WHERE ` + where
 
 	var count int
-	err := db.QueryRowContext(ctx, query, userID).Scan(&count)
+	err := db.QueryRowContext(ctx, query, args...).Scan(&count)
 	if err != nil {
 		return 0, errors.Wrap(err, errors.ErrDatabaseError)
 	}
@@ -217,18 +405,32 @@ func (db *DB) GetConversationCount(ctx context.Context, userID uuid.UUID) (int,
 	return count, nil
 }
 
+// maxFallbackTitleLength is the length, in characters, of titles cut from the first message
+const maxFallbackTitleLength = 50
+
 // GenerateConversationTitle generates a conversation title based on the first user message
 // If no title is provided and no messages exist, returns "New Conversation"
 func GenerateConversationTitle(firstMessage string) string {
+	firstMessage = strings.Join(strings.Fields(firstMessage), " ")
 	if firstMessage == "" {
 		return "New Conversation"
 	}
 
-	// Truncate to first 50 characters and add ellipsis if needed
-	const maxLength = 50
-	if len(firstMessage) > maxLength {
-		return firstMessage[:maxLength] + "..."
+	return TruncateTitle(firstMessage, maxFallbackTitleLength)
+}
+
+// TruncateTitle shortens a title to maxRunes characters without splitting UTF-8 sequences,
+// preferring to cut at a word boundary and marking the cut with an ellipsis
+func TruncateTitle(title string, maxRunes int) string {
+	runes := []rune(title)
+	if len(runes) <= maxRunes {
+		return title
+	}
+
+	cut := string(runes[:maxRunes])
+	if i := strings.LastIndex(cut, " "); i > len(cut)/2 {
+		cut = cut[:i]
 	}
 
-	return firstMessage
+	return strings.TrimRight(cut, " ,.;:-") + "..."
 }
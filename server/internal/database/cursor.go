@@ -0,0 +1,47 @@
+package database
+
+import (
+	"encoding/base64"
+	"encoding/json"
+)
+
+// EncodeCursor serializes a pagination position into an opaque, URL-safe token
+func EncodeCursor(position interface{}) (string, error) {
+	data, err := json.Marshal(position)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(data), nil
+}
+
+// DecodeCursor parses a token produced by EncodeCursor into position
+func DecodeCursor(cursor string, position interface{}) error {
+	data, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, position)
+}
+
+// PageCursors holds the cursors of the pages around a keyset-paginated page
+// Either is empty when there is no page in that direction.
+type PageCursors struct {
+	Next string
+	Prev string
+}
+
+// reverseDirection flips an ORDER BY direction for paging backwards
+func reverseDirection(direction string) string {
+	if direction == "DESC" {
+		return "ASC"
+	}
+	return "DESC"
+}
+
+// reverseComparison flips a keyset comparison for paging backwards
+func reverseComparison(comparison string) string {
+	if comparison == "<" {
+		return ">"
+	}
+	return "<"
+}
@@ -0,0 +1,207 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+
+	"article-chat-system/server/internal/errors"
+	"article-chat-system/server/internal/models"
+	"github.com/google/uuid"
+)
+
+// feedColumns selects a feed row in the order read by scanFeed; the table is aliased f
+const feedColumns = `
+	f.id, f.url, f.title, f.category, f.poll_interval, f.enabled, f.error_count, COALESCE(f.last_error, ''),
+	f.last_polled_at, f.next_poll_at, COALESCE(f.created_by::text, ''), f.created_at, f.updated_at,
+	COALESCE(f.etag, ''), COALESCE(f.last_modified, ''),
+	(SELECT COUNT(*) FROM articles a WHERE a.feed_id = f.id)`
+
+// CreateFeed subscribes to a feed; it is polled on the scheduler's next pass
+func (db *DB) CreateFeed(ctx context.Context, feed *models.Feed, createdBy uuid.UUID) (*models.Feed, error) {
+	query := `
+		INSERT INTO feeds AS f (url, title, category, poll_interval, enabled, created_by)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING ` + feedColumns
+
+	created, err := scanFeed(db.QueryRowContext(ctx, query,
+		feed.URL, feed.Title, feed.Category, feed.PollInterval, feed.Enabled, createdBy))
+	if err != nil {
+		if isUniqueViolation(err) {
+			return nil, errors.New(errors.ErrValidationFailed, "Already subscribed to this feed")
+		}
+		return nil, errors.Wrap(err, errors.ErrDatabaseError)
+	}
+
+	return created, nil
+}
+
+// ListFeeds returns all feed subscriptions by title
+func (db *DB) ListFeeds(ctx context.Context) ([]models.Feed, error) {
+	query := `SELECT ` + feedColumns + ` FROM feeds f ORDER BY f.title, f.url`
+
+	return db.queryFeeds(ctx, query)
+}
+
+// GetFeed retrieves a feed by ID
+func (db *DB) GetFeed(ctx context.Context, feedID uuid.UUID) (*models.Feed, error) {
+	query := `SELECT ` + feedColumns + ` FROM feeds f WHERE f.id = $1`
+
+	feed, err := scanFeed(db.QueryRowContext(ctx, query, feedID))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errors.New(errors.ErrResourceNotFound, "Feed not found")
+		}
+		return nil, errors.Wrap(err, errors.ErrDatabaseError)
+	}
+
+	return feed, nil
+}
+
+// UpdateFeed changes a subscription; nil fields are left unchanged
+// Re-enabling a feed clears its error backoff and schedules it right away.
+func (db *DB) UpdateFeed(ctx context.Context, feedID uuid.UUID, change *models.UpdateFeedRequest) (*models.Feed, error) {
+	query := `
+		UPDATE feeds f
+		SET title = COALESCE($2, f.title),
+			category = COALESCE($3, f.category),
+			poll_interval = COALESCE($4, f.poll_interval),
+			enabled = COALESCE($5, f.enabled),
+			error_count = CASE WHEN $5::boolean AND NOT f.enabled THEN 0 ELSE f.error_count END,
+			next_poll_at = CASE WHEN $5::boolean AND NOT f.enabled THEN NOW() ELSE f.next_poll_at END
+		WHERE f.id = $1
+		RETURNING ` + feedColumns
+
+	feed, err := scanFeed(db.QueryRowContext(ctx, query,
+		feedID, change.Title, change.Category, change.PollInterval, change.Enabled))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errors.New(errors.ErrResourceNotFound, "Feed not found")
+		}
+		return nil, errors.Wrap(err, errors.ErrDatabaseError)
+	}
+
+	return feed, nil
+}
+
+// DeleteFeed removes a subscription; articles ingested from it are kept
+func (db *DB) DeleteFeed(ctx context.Context, feedID uuid.UUID) error {
+	result, err := db.ExecContext(ctx, `DELETE FROM feeds WHERE id = $1`, feedID)
+	if err != nil {
+		return errors.Wrap(err, errors.ErrDatabaseError)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return errors.Wrap(err, errors.ErrDatabaseError)
+	}
+
+	if rowsAffected == 0 {
+		return errors.New(errors.ErrResourceNotFound, "Feed not found")
+	}
+
+	return nil
+}
+
+// ClaimDueFeeds returns up to limit enabled feeds due for a poll
+// Claimed feeds are pushed back by lease seconds, so concurrent schedulers never poll the same
+// feed and a poll that never records its outcome is retried after the lease.
+func (db *DB) ClaimDueFeeds(ctx context.Context, lease, limit int) ([]models.Feed, error) {
+	query := `
+		UPDATE feeds f
+		SET next_poll_at = NOW() + make_interval(secs => $1)
+		WHERE f.id IN (
+			SELECT id FROM feeds
+			WHERE enabled AND next_poll_at <= NOW()
+			ORDER BY next_poll_at
+			LIMIT $2
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING ` + feedColumns
+
+	return db.queryFeeds(ctx, query, lease, limit)
+}
+
+// RecordFeedPoll stores a successful poll and schedules the next one in delay seconds
+// The feed's title is only filled in when it has none.
+func (db *DB) RecordFeedPoll(ctx context.Context, feedID uuid.UUID, title, etag, lastModified string, delay int) error {
+	query := `
+		UPDATE feeds
+		SET title = CASE WHEN title = '' THEN $2 ELSE title END,
+			etag = NULLIF($3, ''), last_modified = NULLIF($4, ''),
+			error_count = 0, last_error = NULL, last_polled_at = NOW(),
+			next_poll_at = NOW() + make_interval(secs => $5)
+		WHERE id = $1`
+
+	if _, err := db.ExecContext(ctx, query, feedID, title, etag, lastModified, delay); err != nil {
+		return errors.Wrap(err, errors.ErrDatabaseError)
+	}
+	return nil
+}
+
+// RecordFeedError stores a failed poll and schedules a retry in delay seconds
+func (db *DB) RecordFeedError(ctx context.Context, feedID uuid.UUID, message string, delay int) error {
+	query := `
+		UPDATE feeds
+		SET error_count = error_count + 1, last_error = $2, last_polled_at = NOW(),
+			next_poll_at = NOW() + make_interval(secs => $3)
+		WHERE id = $1`
+
+	if _, err := db.ExecContext(ctx, query, feedID, message, delay); err != nil {
+		return errors.Wrap(err, errors.ErrDatabaseError)
+	}
+	return nil
+}
+
+// queryFeeds runs a query selecting feedColumns
+func (db *DB) queryFeeds(ctx context.Context, query string, args ...interface{}) ([]models.Feed, error) {
+	rows, err := db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, errors.Wrap(err, errors.ErrDatabaseError)
+	}
+	defer rows.Close()
+
+	feeds := []models.Feed{}
+	for rows.Next() {
+		feed, err := scanFeed(rows)
+		if err != nil {
+			return nil, errors.Wrap(err, errors.ErrDatabaseError)
+		}
+		feeds = append(feeds, *feed)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, errors.Wrap(err, errors.ErrDatabaseError)
+	}
+
+	return feeds, nil
+}
+
+// scanFeed reads a row selected with feedColumns
+func scanFeed(row interface{ Scan(...interface{}) error }) (*models.Feed, error) {
+	var feed models.Feed
+	var lastPolledAt sql.NullTime
+
+	err := row.Scan(
+		&feed.ID,
+		&feed.URL,
+		&feed.Title,
+		&feed.Category,
+		&feed.PollInterval,
+		&feed.Enabled,
+		&feed.ErrorCount,
+		&feed.LastError,
+		&lastPolledAt,
+		&feed.NextPollAt,
+		&feed.CreatedBy,
+		&feed.CreatedAt,
+		&feed.UpdatedAt,
+		&feed.ETag,
+		&feed.LastModified,
+		&feed.ArticleCount,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	feed.LastPolledAt = NullTimeToTime(lastPolledAt)
+	return &feed, nil
+}
@@ -0,0 +1,193 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"article-chat-system/server/internal/errors"
+	"article-chat-system/server/internal/models"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// feedbackStatsColumns counts ratings for the current group of a report query
+const feedbackStatsColumns = `
+	COUNT(*) FILTER (WHERE rating = 'up'),
+	COUNT(*) FILTER (WHERE rating = 'down'),
+	COUNT(*)`
+
+// UpsertMessageFeedback stores a user's rating of a message, replacing an earlier rating
+func (db *DB) UpsertMessageFeedback(ctx context.Context, messageID, userID uuid.UUID, input *models.MessageFeedbackInput) (*models.MessageFeedback, error) {
+	query := `
+		INSERT INTO message_feedback (message_id, user_id, rating, reasons, comment)
+		VALUES ($1, $2, $3, $4, NULLIF($5, ''))
+		ON CONFLICT (message_id, user_id) DO UPDATE
+		SET rating = EXCLUDED.rating, reasons = EXCLUDED.reasons, comment = EXCLUDED.comment, updated_at = NOW()
+		RETURNING id, message_id, user_id, rating, reasons, COALESCE(comment, ''), created_at, updated_at`
+
+	reasons := input.Reasons
+	if reasons == nil {
+		reasons = []string{}
+	}
+
+	var feedback models.MessageFeedback
+	err := db.QueryRowContext(ctx, query, messageID, userID, input.Rating, pq.Array(reasons), input.Comment).Scan(
+		&feedback.ID,
+		&feedback.MessageID,
+		&feedback.UserID,
+		&feedback.Rating,
+		pq.Array(&feedback.Reasons),
+		&feedback.Comment,
+		&feedback.CreatedAt,
+		&feedback.UpdatedAt,
+	)
+	if err != nil {
+		return nil, errors.Wrap(err, errors.ErrDatabaseError)
+	}
+
+	return &feedback, nil
+}
+
+// GetFeedbackReport aggregates feedback given in [from, to) by cited article, cache status, day and reason
+// articleLimit caps the article list, which is ordered by negative ratings
+func (db *DB) GetFeedbackReport(ctx context.Context, from, to time.Time, articleLimit int) (*models.FeedbackReport, error) {
+	report := &models.FeedbackReport{
+		From:      from,
+		To:        to,
+		ByArticle: []models.ArticleFeedback{},
+		ByCache:   []models.CacheFeedback{},
+		ByDay:     []models.DailyFeedback{},
+		ByReason:  []models.FeedbackReason{},
+	}
+
+	// Totals
+	var up, down, total int
+	query := `SELECT ` + feedbackStatsColumns + ` FROM message_feedback WHERE updated_at >= $1 AND updated_at < $2`
+	if err := db.QueryRowContext(ctx, query, from, to).Scan(&up, &down, &total); err != nil {
+		return nil, errors.Wrap(err, errors.ErrDatabaseError)
+	}
+	report.Totals = newFeedbackStats(up, down, total)
+
+	// By cited article; an answer citing several chunks of one article counts once
+	query = `
+		WITH cited AS (
+			SELECT f.id, f.rating, src->>'article_id' AS article_id, MAX(src->>'article_title') AS article_title
+			FROM message_feedback f
+			JOIN messages m ON m.id = f.message_id
+			CROSS JOIN LATERAL jsonb_array_elements(
+				CASE WHEN jsonb_typeof(m.metadata->'sources') = 'array' THEN m.metadata->'sources' ELSE '[]'::jsonb END
+			) src
+			WHERE f.updated_at >= $1 AND f.updated_at < $2 AND src->>'article_id' IS NOT NULL
+			GROUP BY f.id, f.rating, src->>'article_id'
+		)
+		SELECT article_id, COALESCE(MAX(article_title), ''),` + feedbackStatsColumns + `
+		FROM cited
+		GROUP BY article_id
+		ORDER BY COUNT(*) FILTER (WHERE rating = 'down') DESC, COUNT(*) DESC, article_id
+		LIMIT $3`
+	err := db.scanFeedbackRows(ctx, query, []interface{}{from, to, articleLimit}, func(rows *sql.Rows) error {
+		var item models.ArticleFeedback
+		if err := rows.Scan(&item.ArticleID, &item.ArticleTitle, &up, &down, &total); err != nil {
+			return err
+		}
+		item.FeedbackStats = newFeedbackStats(up, down, total)
+		report.ByArticle = append(report.ByArticle, item)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// By cache hit vs. miss, as recorded in the answer's metadata
+	query = `
+		SELECT COALESCE((m.metadata->>'cached')::boolean, false) AS cached,` + feedbackStatsColumns + `
+		FROM message_feedback f
+		JOIN messages m ON m.id = f.message_id
+		WHERE f.updated_at >= $1 AND f.updated_at < $2
+		GROUP BY cached
+		ORDER BY cached`
+	err = db.scanFeedbackRows(ctx, query, []interface{}{from, to}, func(rows *sql.Rows) error {
+		var item models.CacheFeedback
+		if err := rows.Scan(&item.Cached, &up, &down, &total); err != nil {
+			return err
+		}
+		item.FeedbackStats = newFeedbackStats(up, down, total)
+		report.ByCache = append(report.ByCache, item)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// By day
+	query = `
+		SELECT to_char(date_trunc('day', updated_at), 'YYYY-MM-DD') AS day,` + feedbackStatsColumns + `
+		FROM message_feedback
+		WHERE updated_at >= $1 AND updated_at < $2
+		GROUP BY day
+		ORDER BY day`
+	err = db.scanFeedbackRows(ctx, query, []interface{}{from, to}, func(rows *sql.Rows) error {
+		var item models.DailyFeedback
+		if err := rows.Scan(&item.Day, &up, &down, &total); err != nil {
+			return err
+		}
+		item.FeedbackStats = newFeedbackStats(up, down, total)
+		report.ByDay = append(report.ByDay, item)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// By reason tag
+	query = `
+		SELECT reason,` + feedbackStatsColumns + `
+		FROM message_feedback, unnest(reasons) AS reason
+		WHERE updated_at >= $1 AND updated_at < $2
+		GROUP BY reason
+		ORDER BY COUNT(*) DESC, reason`
+	err = db.scanFeedbackRows(ctx, query, []interface{}{from, to}, func(rows *sql.Rows) error {
+		var item models.FeedbackReason
+		if err := rows.Scan(&item.Reason, &up, &down, &total); err != nil {
+			return err
+		}
+		item.FeedbackStats = newFeedbackStats(up, down, total)
+		report.ByReason = append(report.ByReason, item)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return report, nil
+}
+
+// scanFeedbackRows runs a report query and hands each row to scan
+func (db *DB) scanFeedbackRows(ctx context.Context, query string, args []interface{}, scan func(*sql.Rows) error) error {
+	rows, err := db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return errors.Wrap(err, errors.ErrDatabaseError)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		if err := scan(rows); err != nil {
+			return errors.Wrap(err, errors.ErrDatabaseError)
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		return errors.Wrap(err, errors.ErrDatabaseError)
+	}
+
+	return nil
+}
+
+func newFeedbackStats(up, down, total int) models.FeedbackStats {
+	stats := models.FeedbackStats{Up: up, Down: down, Total: total}
+	if total > 0 {
+		stats.DownRate = float64(down) / float64(total)
+	}
+	return stats
+}
@@ -0,0 +1,42 @@
+package database
+
+import (
+	"database/sql"
+
+	"article-chat-system/server/internal/errors"
+	"github.com/google/uuid"
+)
+
+// GetUserIDByIdentity finds the local user linked to an external provider subject
+func (db *DB) GetUserIDByIdentity(provider, subject string) (uuid.UUID, error) {
+	var userID uuid.UUID
+
+	query := `SELECT user_id FROM user_identities WHERE provider = $1 AND subject = $2`
+
+	err := db.QueryRow(query, provider, subject).Scan(&userID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return uuid.Nil, errors.New(errors.ErrResourceNotFound, "Identity not linked")
+		}
+		return uuid.Nil, errors.Wrap(err, errors.ErrDatabaseError)
+	}
+
+	return userID, nil
+}
+
+// LinkUserIdentity links an external provider subject to a local user
+// Re-linking an existing subject only refreshes the stored email and login time
+func (db *DB) LinkUserIdentity(userID uuid.UUID, provider, subject, email string) error {
+	query := `
+		INSERT INTO user_identities (user_id, provider, subject, email, last_login)
+		VALUES ($1, $2, $3, $4, NOW())
+		ON CONFLICT (provider, subject) DO UPDATE
+		SET email = EXCLUDED.email, last_login = NOW()`
+
+	_, err := db.Exec(query, userID, provider, subject, StringToNullString(email))
+	if err != nil {
+		return errors.Wrap(err, errors.ErrDatabaseError)
+	}
+
+	return nil
+}
@@ -0,0 +1,112 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+
+	"article-chat-system/server/internal/errors"
+	"article-chat-system/server/internal/models"
+	"github.com/google/uuid"
+)
+
+// ImportConversations recreates exported conversations under userID in a single transaction
+//
+// Conversations and messages receive new IDs; the original conversation ID is kept in
+// imported_from. A conversation is reported as a duplicate (and skipped) when the user
+// already owns it, has imported it before, or it appears twice in the same import.
+// Original timestamps and message metadata are preserved.
+func (db *DB) ImportConversations(ctx context.Context, userID uuid.UUID, conversations []models.ConversationWithMessages) (*models.ImportResult, error) {
+	result := &models.ImportResult{
+		Imported:   []models.ImportedConversation{},
+		Duplicates: []models.ImportDuplicate{},
+	}
+
+	err := db.Transaction(func(tx *sql.Tx) error {
+		seen := make(map[uuid.UUID]bool)
+
+		for _, conv := range conversations {
+			duplicate := seen[conv.ID]
+			if !duplicate {
+				err := tx.QueryRowContext(ctx, `
+					SELECT EXISTS(
+						SELECT 1 FROM conversations
+						WHERE user_id = $1 AND (id = $2 OR imported_from = $2)
+					)`, userID, conv.ID).Scan(&duplicate)
+				if err != nil {
+					return errors.Wrap(err, errors.ErrDatabaseError)
+				}
+			}
+			seen[conv.ID] = true
+
+			if duplicate {
+				result.Duplicates = append(result.Duplicates, models.ImportDuplicate{SourceID: conv.ID, Title: conv.Title})
+				continue
+			}
+
+			imported, err := importConversation(ctx, tx, userID, conv)
+			if err != nil {
+				return err
+			}
+			result.Imported = append(result.Imported, *imported)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// importConversation inserts one conversation with its messages inside tx
+func importConversation(ctx context.Context, tx *sql.Tx, userID uuid.UUID, conv models.ConversationWithMessages) (*models.ImportedConversation, error) {
+	newID := uuid.New()
+
+	_, err := tx.ExecContext(ctx, `
+		INSERT INTO conversations (id, user_id, title, created_at, updated_at, imported_from)
+		VALUES ($1, $2, $3, $4, $5, $6)`,
+		newID, userID, conv.Title, conv.CreatedAt, conv.UpdatedAt, conv.ID)
+	if err != nil {
+		return nil, errors.Wrap(err, errors.ErrDatabaseError)
+	}
+
+	// Exports contain the active branch only, so messages are chained in order
+	var parentID *uuid.UUID
+	for _, msg := range conv.Messages {
+		var metadataJSON []byte
+		if msg.Metadata != nil {
+			metadataJSON, err = json.Marshal(msg.Metadata)
+			if err != nil {
+				return nil, errors.Wrap(err, errors.ErrInvalidDataType)
+			}
+		}
+
+		messageID := uuid.New()
+		_, err = tx.ExecContext(ctx, `
+			INSERT INTO messages (id, conversation_id, parent_id, role, content, metadata, created_at)
+			VALUES ($1, $2, $3, $4, $5, $6, $7)`,
+			messageID, newID, parentID, msg.Role, msg.Content, metadataJSON, msg.CreatedAt)
+		if err != nil {
+			return nil, errors.Wrap(err, errors.ErrDatabaseError)
+		}
+		parentID = &messageID
+	}
+
+	// The exported messages are the active branch; keep the exported updated_at
+	_, err = tx.ExecContext(ctx, `
+		UPDATE conversations SET message_count = $2, active_leaf_id = $3, updated_at = $4
+		WHERE id = $1`,
+		newID, len(conv.Messages), parentID, conv.UpdatedAt)
+	if err != nil {
+		return nil, errors.Wrap(err, errors.ErrDatabaseError)
+	}
+
+	return &models.ImportedConversation{
+		SourceID:       conv.ID,
+		ConversationID: newID,
+		Title:          conv.Title,
+		MessageCount:   len(conv.Messages),
+	}, nil
+}
@@ -4,6 +4,8 @@ import (
 	"context"
 	"database/sql"
 	"encoding/json"
+	"fmt"
+	"slices"
 
 	"article-chat-system/server/internal/errors"
 	"article-chat-system/server/internal/models"
@@ -22,18 +24,26 @@ func (db *DB) CreateMessage(ctx context.Context, conversationID uuid.UUID, role,
 		}
 	}
 
+	// The message extends the active branch and becomes its new leaf
 	query := `
-		INSERT INTO messages (conversation_id, role, content, metadata)
-		VALUES ($1, $2, $3, $4)
-		RETURNING id, conversation_id, role, content, metadata, created_at
+		WITH inserted AS (
+			INSERT INTO messages (conversation_id, parent_id, role, content, metadata)
+			VALUES ($1, (SELECT active_leaf_id FROM conversations WHERE id = $1), $2, $3, $4)
+			RETURNING id, conversation_id, parent_id, role, content, metadata, created_at
+		), activated AS (
+			UPDATE conversations SET active_leaf_id = (SELECT id FROM inserted), message_count = message_count + 1 WHERE id = $1
+		)
+		SELECT id, conversation_id, parent_id, role, content, metadata, created_at FROM inserted
 	`
 
 	var message models.Message
+	var parentID uuid.NullUUID
 	var metadataStr sql.NullString
 
 	err = db.QueryRowContext(ctx, query, conversationID, role, content, metadataJSON).Scan(
 		&message.ID,
 		&message.ConversationID,
+		&parentID,
 		&message.Role,
 		&message.Content,
 		&metadataStr,
@@ -44,6 +54,10 @@ func (db *DB) CreateMessage(ctx context.Context, conversationID uuid.UUID, role,
 		return nil, errors.Wrap(err, errors.ErrDatabaseError)
 	}
 
+	if parentID.Valid {
+		message.ParentID = &parentID.UUID
+	}
+
 	// Parse metadata if exists
 	if metadataStr.Valid && metadataStr.String != "" {
 		err = json.Unmarshal([]byte(metadataStr.String), &message.Metadata)
@@ -60,17 +74,19 @@ func (db *DB) CreateMessage(ctx context.Context, conversationID uuid.UUID, role,
 // GetMessage retrieves a message by ID
 func (db *DB) GetMessage(ctx context.Context, messageID uuid.UUID) (*models.Message, error) {
 	query := `
-		SELECT id, conversation_id, role, content, metadata, created_at
+		SELECT id, conversation_id, parent_id, role, content, metadata, created_at
 		FROM messages
 		WHERE id = $1
 	`
 
 	var message models.Message
+	var parentID uuid.NullUUID
 	var metadataStr sql.NullString
 
 	err := db.QueryRowContext(ctx, query, messageID).Scan(
 		&message.ID,
 		&message.ConversationID,
+		&parentID,
 		&message.Role,
 		&message.Content,
 		&metadataStr,
@@ -84,6 +100,10 @@ func (db *DB) GetMessage(ctx context.Context, messageID uuid.UUID) (*models.Mess
 		return nil, errors.Wrap(err, errors.ErrDatabaseError)
 	}
 
+	if parentID.Valid {
+		message.ParentID = &parentID.UUID
+	}
+
 	// Parse metadata if exists
 	if metadataStr.Valid && metadataStr.String != "" {
 		err = json.Unmarshal([]byte(metadataStr.String), &message.Metadata)
@@ -97,13 +117,10 @@ func (db *DB) GetMessage(ctx context.Context, messageID uuid.UUID) (*models.Mess
 	return &message, nil
 }
 
-// GetConversationMessages retrieves all messages for a conversation, ordered by created_at
+// GetConversationMessages retrieves the messages on the conversation's active branch, oldest first
 func (db *DB) GetConversationMessages(ctx context.Context, conversationID uuid.UUID) ([]models.Message, error) {
-	query := `
-		SELECT id, conversation_id, role, content, metadata, created_at
-		FROM messages
-		WHERE conversation_id = $1
-		ORDER BY created_at ASC
+	query := fmt.Sprintf(branchPathCTE, activeLeafAnchor) + branchPathColumns + `
+		ORDER BY path.depth DESC
 	`
 
 	rows, err := db.QueryContext(ctx, query, conversationID)
@@ -112,90 +129,104 @@ func (db *DB) GetConversationMessages(ctx context.Context, conversationID uuid.U
 	}
 	defer rows.Close()
 
-	var messages []models.Message
-	for rows.Next() {
-		var message models.Message
-		var metadataStr sql.NullString
+	return scanBranchMessages(rows)
+}
 
-		err := rows.Scan(
-			&message.ID,
-			&message.ConversationID,
-			&message.Role,
-			&message.Content,
-			&metadataStr,
-			&message.CreatedAt,
-		)
-		if err != nil {
-			return nil, errors.Wrap(err, errors.ErrDatabaseError)
-		}
+// messageCursor is a position on a conversation's active branch
+// A question and its answer are stored in one transaction and share created_at, so the
+// role breaks that tie the same way branch ordering does.
+type messageCursor struct {
+	CreatedAt string    `json:"t"`
+	Assistant bool      `json:"a"`
+	ID        uuid.UUID `json:"id"`
+	Before    bool      `json:"b,omitempty"`
+}
 
-		// Parse metadata if exists
-		if metadataStr.Valid {
-			err = json.Unmarshal([]byte(metadataStr.String), &message.Metadata)
-			if err != nil {
-				return nil, errors.Wrap(err, errors.ErrDatabaseError)
-			}
+// messageKey is the keyset of active branch messages, in conversation order
+const messageKey = `(path.created_at, (path.role = 'assistant'), path.id)`
+
+// GetConversationMessagesPaginated retrieves a page of messages on the active branch, oldest first
+// A cursor from the returned PageCursors continues the listing; offset is only used without a cursor.
+func (db *DB) GetConversationMessagesPaginated(ctx context.Context, conversationID uuid.UUID, limit, offset int, cursor string) ([]models.Message, PageCursors, error) {
+	var cursors PageCursors
+
+	where, direction := "", "ASC"
+	args := []interface{}{conversationID}
+
+	var position messageCursor
+	if cursor != "" {
+		if err := DecodeCursor(cursor, &position); err != nil {
+			return nil, cursors, errors.New(errors.ErrValidationFailed, "Invalid cursor")
 		}
 
-		messages = append(messages, message)
+		comparison := ">"
+		if position.Before {
+			direction, comparison = reverseDirection(direction), reverseComparison(comparison)
+		}
+		args = append(args, position.CreatedAt, position.Assistant, position.ID)
+		where = fmt.Sprintf(" WHERE %s %s ($2::timestamp, $3, $4)", messageKey, comparison)
 	}
 
-	if err = rows.Err(); err != nil {
-		return nil, errors.Wrap(err, errors.ErrDatabaseError)
+	// One extra row tells whether another page exists
+	args = append(args, limit+1)
+	query := fmt.Sprintf(branchPathCTE, activeLeafAnchor) + branchPathColumns + where +
+		fmt.Sprintf(" ORDER BY path.created_at %[1]s, (path.role = 'assistant') %[1]s, path.id %[1]s LIMIT $%[2]d", direction, len(args))
+	if cursor == "" && offset > 0 {
+		args = append(args, offset)
+		query += fmt.Sprintf(" OFFSET $%d", len(args))
 	}
 
-	return messages, nil
-}
-
-// GetConversationMessagesPaginated retrieves messages for a conversation with pagination
-func (db *DB) GetConversationMessagesPaginated(ctx context.Context, conversationID uuid.UUID, limit, offset int) ([]models.Message, error) {
-	query := `
-		SELECT id, conversation_id, role, content, metadata, created_at
-		FROM messages
-		WHERE conversation_id = $1
-		ORDER BY created_at ASC
-		LIMIT $2 OFFSET $3
-	`
-
-	rows, err := db.QueryContext(ctx, query, conversationID, limit, offset)
+	rows, err := db.QueryContext(ctx, query, args...)
 	if err != nil {
-		return nil, errors.Wrap(err, errors.ErrDatabaseError)
+		return nil, cursors, errors.Wrap(err, errors.ErrDatabaseError)
 	}
 	defer rows.Close()
 
-	var messages []models.Message
-	for rows.Next() {
-		var message models.Message
-		var metadataStr sql.NullString
+	messages, err := scanBranchMessages(rows)
+	if err != nil {
+		return nil, cursors, err
+	}
 
-		err := rows.Scan(
-			&message.ID,
-			&message.ConversationID,
-			&message.Role,
-			&message.Content,
-			&metadataStr,
-			&message.CreatedAt,
-		)
-		if err != nil {
-			return nil, errors.Wrap(err, errors.ErrDatabaseError)
-		}
+	more := len(messages) > limit
+	if more {
+		messages = messages[:limit]
+	}
+	if position.Before {
+		slices.Reverse(messages)
+	}
+	if len(messages) == 0 {
+		return messages, cursors, nil
+	}
 
-		// Parse metadata if exists
-		if metadataStr.Valid {
-			err = json.Unmarshal([]byte(metadataStr.String), &message.Metadata)
-			if err != nil {
-				return nil, errors.Wrap(err, errors.ErrDatabaseError)
-			}
-		}
+	hasNext := more || position.Before
+	hasPrev := position.Before && more || !position.Before && (cursor != "" || offset > 0)
 
-		messages = append(messages, message)
+	if hasNext {
+		if cursors.Next, err = encodeMessageCursor(messages[len(messages)-1], false); err != nil {
+			return nil, cursors, err
+		}
 	}
-
-	if err = rows.Err(); err != nil {
-		return nil, errors.Wrap(err, errors.ErrDatabaseError)
+	if hasPrev {
+		if cursors.Prev, err = encodeMessageCursor(messages[0], true); err != nil {
+			return nil, cursors, err
+		}
 	}
 
-	return messages, nil
+	return messages, cursors, nil
+}
+
+// encodeMessageCursor builds the cursor for the page after (or before) message
+func encodeMessageCursor(message models.Message, before bool) (string, error) {
+	encoded, err := EncodeCursor(messageCursor{
+		CreatedAt: message.CreatedAt.Format(cursorTimeLayout),
+		Assistant: message.Role == "assistant",
+		ID:        message.ID,
+		Before:    before,
+	})
+	if err != nil {
+		return "", errors.Wrap(err, errors.ErrInternalServer)
+	}
+	return encoded, nil
 }
 
 // DeleteMessage deletes a message by ID
@@ -219,9 +250,9 @@ func (db *DB) DeleteMessage(ctx context.Context, messageID uuid.UUID) error {
 	return nil
 }
 
-// GetMessageCount returns the total number of messages in a conversation
+// GetMessageCount returns the number of messages on the conversation's active branch
 func (db *DB) GetMessageCount(ctx context.Context, conversationID uuid.UUID) (int, error) {
-	query := `SELECT COUNT(*) FROM messages WHERE conversation_id = $1`
+	query := fmt.Sprintf(branchPathCTE, activeLeafAnchor) + `SELECT COUNT(*) FROM path`
 
 	var count int
 	err := db.QueryRowContext(ctx, query, conversationID).Scan(&count)
@@ -239,16 +270,28 @@ func (db *DB) CreateMessagePair(ctx context.Context, conversationID uuid.UUID, u
 	var err error
 
 	err = db.Transaction(func(tx *sql.Tx) error {
-		// Create user message
+		// Lock the conversation so concurrent pairs chain onto each other instead of forking
+		var activeLeafID uuid.NullUUID
+		err = tx.QueryRowContext(ctx, `SELECT active_leaf_id FROM conversations WHERE id = $1 FOR UPDATE`, conversationID).Scan(&activeLeafID)
+		if err != nil {
+			return errors.Wrap(err, errors.ErrDatabaseError)
+		}
+
+		var parentID *uuid.UUID
+		if activeLeafID.Valid {
+			parentID = &activeLeafID.UUID
+		}
+
+		// Create user message as a reply to the active leaf
 		query := `
-			INSERT INTO messages (conversation_id, role, content)
-			VALUES ($1, $2, $3)
+			INSERT INTO messages (conversation_id, parent_id, role, content)
+			VALUES ($1, $2, $3, $4)
 			RETURNING id, conversation_id, role, content, metadata, created_at
 		`
 
 		var userMsg models.Message
 		var userMetadataStr sql.NullString
-		err = tx.QueryRowContext(ctx, query, conversationID, "user", userContent).Scan(
+		err = tx.QueryRowContext(ctx, query, conversationID, parentID, "user", userContent).Scan(
 			&userMsg.ID,
 			&userMsg.ConversationID,
 			&userMsg.Role,
@@ -269,6 +312,7 @@ func (db *DB) CreateMessagePair(ctx context.Context, conversationID uuid.UUID, u
 		} else {
 			userMsg.Metadata = nil
 		}
+		userMsg.ParentID = parentID
 		userMessage = &userMsg
 
 		// Create assistant message
@@ -281,14 +325,14 @@ func (db *DB) CreateMessagePair(ctx context.Context, conversationID uuid.UUID, u
 		}
 
 		query = `
-			INSERT INTO messages (conversation_id, role, content, metadata)
-			VALUES ($1, $2, $3, $4)
+			INSERT INTO messages (conversation_id, parent_id, role, content, metadata)
+			VALUES ($1, $2, $3, $4, $5)
 			RETURNING id, conversation_id, role, content, metadata, created_at
 		`
 
 		var assistantMsg models.Message
 		var metadataStr sql.NullString
-		err = tx.QueryRowContext(ctx, query, conversationID, "assistant", assistantContent, metadataJSON).Scan(
+		err = tx.QueryRowContext(ctx, query, conversationID, userMsg.ID, "assistant", assistantContent, metadataJSON).Scan(
 			&assistantMsg.ID,
 			&assistantMsg.ConversationID,
 			&assistantMsg.Role,
@@ -309,15 +353,16 @@ func (db *DB) CreateMessagePair(ctx context.Context, conversationID uuid.UUID, u
 		} else {
 			assistantMsg.Metadata = nil
 		}
+		assistantMsg.ParentID = &userMsg.ID
 		assistantMessage = &assistantMsg
 
-		// Update conversation message count (+2 for both messages)
+		// The active branch grows by both messages; make the reply its leaf
 		updateQuery := `
 			UPDATE conversations
-			SET message_count = message_count + 2, updated_at = NOW()
+			SET message_count = message_count + 2, active_leaf_id = $2, updated_at = NOW()
 			WHERE id = $1
 		`
-		_, err = tx.ExecContext(ctx, updateQuery, conversationID)
+		_, err = tx.ExecContext(ctx, updateQuery, conversationID, assistantMsg.ID)
 		if err != nil {
 			return errors.Wrap(err, errors.ErrDatabaseError)
 		}
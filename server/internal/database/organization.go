@@ -0,0 +1,303 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+
+	"article-chat-system/server/internal/errors"
+	"article-chat-system/server/internal/models"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// isUniqueViolation reports whether err is a PostgreSQL unique constraint violation
+func isUniqueViolation(err error) bool {
+	pqErr, ok := err.(*pq.Error)
+	return ok && pqErr.Code == "23505"
+}
+
+// CreateFolder creates a folder for a user; names are unique per user
+func (db *DB) CreateFolder(ctx context.Context, userID uuid.UUID, name string) (*models.Folder, error) {
+	query := `
+		INSERT INTO folders (user_id, name)
+		VALUES ($1, $2)
+		RETURNING id, name, created_at, updated_at`
+
+	var folder models.Folder
+	err := db.QueryRowContext(ctx, query, userID, name).Scan(&folder.ID, &folder.Name, &folder.CreatedAt, &folder.UpdatedAt)
+	if err != nil {
+		if isUniqueViolation(err) {
+			return nil, errors.New(errors.ErrValidationFailed, "A folder with this name already exists")
+		}
+		return nil, errors.Wrap(err, errors.ErrDatabaseError)
+	}
+
+	return &folder, nil
+}
+
+// GetUserFolders lists a user's folders by name with the number of conversations in each
+func (db *DB) GetUserFolders(ctx context.Context, userID uuid.UUID) ([]models.Folder, error) {
+	query := `
+		SELECT f.id, f.name, f.created_at, f.updated_at,
+		       (SELECT COUNT(*) FROM conversations c WHERE c.folder_id = f.id)
+		FROM folders f
+		WHERE f.user_id = $1
+		ORDER BY f.name`
+
+	rows, err := db.QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, errors.Wrap(err, errors.ErrDatabaseError)
+	}
+	defer rows.Close()
+
+	folders := []models.Folder{}
+	for rows.Next() {
+		var folder models.Folder
+		if err := rows.Scan(&folder.ID, &folder.Name, &folder.CreatedAt, &folder.UpdatedAt, &folder.ConversationCount); err != nil {
+			return nil, errors.Wrap(err, errors.ErrDatabaseError)
+		}
+		folders = append(folders, folder)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, errors.Wrap(err, errors.ErrDatabaseError)
+	}
+
+	return folders, nil
+}
+
+// RenameFolder renames one of the user's folders
+func (db *DB) RenameFolder(ctx context.Context, userID, folderID uuid.UUID, name string) (*models.Folder, error) {
+	query := `
+		UPDATE folders SET name = $3
+		WHERE id = $1 AND user_id = $2
+		RETURNING id, name, created_at, updated_at,
+		          (SELECT COUNT(*) FROM conversations c WHERE c.folder_id = folders.id)`
+
+	var folder models.Folder
+	err := db.QueryRowContext(ctx, query, folderID, userID, name).Scan(
+		&folder.ID, &folder.Name, &folder.CreatedAt, &folder.UpdatedAt, &folder.ConversationCount)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errors.New(errors.ErrResourceNotFound, "Folder not found")
+		}
+		if isUniqueViolation(err) {
+			return nil, errors.New(errors.ErrValidationFailed, "A folder with this name already exists")
+		}
+		return nil, errors.Wrap(err, errors.ErrDatabaseError)
+	}
+
+	return &folder, nil
+}
+
+// DeleteFolder deletes one of the user's folders; its conversations become unfiled
+func (db *DB) DeleteFolder(ctx context.Context, userID, folderID uuid.UUID) error {
+	result, err := db.ExecContext(ctx, `DELETE FROM folders WHERE id = $1 AND user_id = $2`, folderID, userID)
+	if err != nil {
+		return errors.Wrap(err, errors.ErrDatabaseError)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return errors.Wrap(err, errors.ErrDatabaseError)
+	}
+
+	if rowsAffected == 0 {
+		return errors.New(errors.ErrResourceNotFound, "Folder not found")
+	}
+
+	return nil
+}
+
+// CreateTag creates a tag for a user; names are unique per user
+func (db *DB) CreateTag(ctx context.Context, userID uuid.UUID, name string) (*models.Tag, error) {
+	query := `
+		INSERT INTO tags (user_id, name)
+		VALUES ($1, $2)
+		RETURNING id, name`
+
+	var tag models.Tag
+	err := db.QueryRowContext(ctx, query, userID, name).Scan(&tag.ID, &tag.Name)
+	if err != nil {
+		if isUniqueViolation(err) {
+			return nil, errors.New(errors.ErrValidationFailed, "A tag with this name already exists")
+		}
+		return nil, errors.Wrap(err, errors.ErrDatabaseError)
+	}
+
+	count := 0
+	tag.ConversationCount = &count
+	return &tag, nil
+}
+
+// GetUserTags lists a user's tags by name with the number of conversations using each
+func (db *DB) GetUserTags(ctx context.Context, userID uuid.UUID) ([]models.Tag, error) {
+	query := `
+		SELECT t.id, t.name,
+		       (SELECT COUNT(*) FROM conversation_tags ct WHERE ct.tag_id = t.id)
+		FROM tags t
+		WHERE t.user_id = $1
+		ORDER BY t.name`
+
+	rows, err := db.QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, errors.Wrap(err, errors.ErrDatabaseError)
+	}
+	defer rows.Close()
+
+	tags := []models.Tag{}
+	for rows.Next() {
+		var tag models.Tag
+		var count int
+		if err := rows.Scan(&tag.ID, &tag.Name, &count); err != nil {
+			return nil, errors.Wrap(err, errors.ErrDatabaseError)
+		}
+		tag.ConversationCount = &count
+		tags = append(tags, tag)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, errors.Wrap(err, errors.ErrDatabaseError)
+	}
+
+	return tags, nil
+}
+
+// RenameTag renames one of the user's tags
+func (db *DB) RenameTag(ctx context.Context, userID, tagID uuid.UUID, name string) (*models.Tag, error) {
+	query := `
+		UPDATE tags SET name = $3
+		WHERE id = $1 AND user_id = $2
+		RETURNING id, name, (SELECT COUNT(*) FROM conversation_tags ct WHERE ct.tag_id = tags.id)`
+
+	var tag models.Tag
+	var count int
+	err := db.QueryRowContext(ctx, query, tagID, userID, name).Scan(&tag.ID, &tag.Name, &count)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errors.New(errors.ErrResourceNotFound, "Tag not found")
+		}
+		if isUniqueViolation(err) {
+			return nil, errors.New(errors.ErrValidationFailed, "A tag with this name already exists")
+		}
+		return nil, errors.Wrap(err, errors.ErrDatabaseError)
+	}
+
+	tag.ConversationCount = &count
+	return &tag, nil
+}
+
+// DeleteTag deletes one of the user's tags and removes it from all conversations
+func (db *DB) DeleteTag(ctx context.Context, userID, tagID uuid.UUID) error {
+	result, err := db.ExecContext(ctx, `DELETE FROM tags WHERE id = $1 AND user_id = $2`, tagID, userID)
+	if err != nil {
+		return errors.Wrap(err, errors.ErrDatabaseError)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return errors.Wrap(err, errors.ErrDatabaseError)
+	}
+
+	if rowsAffected == 0 {
+		return errors.New(errors.ErrResourceNotFound, "Tag not found")
+	}
+
+	return nil
+}
+
+// OrganizeConversation applies pinned, archived, folder and tag changes in one transaction
+// folderID is only used when change.FolderID is set; uuid.Nil removes the conversation from its folder.
+// Folders and tags must belong to userID. Organizing does not change updated_at.
+func (db *DB) OrganizeConversation(ctx context.Context, userID, conversationID uuid.UUID, change *models.ConversationOrganize, folderID uuid.UUID) (*models.Conversation, error) {
+	var conv *models.Conversation
+
+	err := db.Transaction(func(tx *sql.Tx) error {
+		if change.Pinned != nil {
+			_, err := tx.ExecContext(ctx, `
+				UPDATE conversations
+				SET pinned_at = CASE WHEN $2 THEN COALESCE(pinned_at, NOW()) END
+				WHERE id = $1`, conversationID, *change.Pinned)
+			if err != nil {
+				return errors.Wrap(err, errors.ErrDatabaseError)
+			}
+		}
+
+		if change.Archived != nil {
+			_, err := tx.ExecContext(ctx, `
+				UPDATE conversations
+				SET archived_at = CASE WHEN $2 THEN COALESCE(archived_at, NOW()) END
+				WHERE id = $1`, conversationID, *change.Archived)
+			if err != nil {
+				return errors.Wrap(err, errors.ErrDatabaseError)
+			}
+		}
+
+		if change.FolderID != nil {
+			var folder uuid.NullUUID
+			if folderID != uuid.Nil {
+				err := tx.QueryRowContext(ctx, `SELECT id FROM folders WHERE id = $1 AND user_id = $2`, folderID, userID).Scan(&folder)
+				if err == sql.ErrNoRows {
+					return errors.New(errors.ErrResourceNotFound, "Folder not found")
+				}
+				if err != nil {
+					return errors.Wrap(err, errors.ErrDatabaseError)
+				}
+			}
+
+			if _, err := tx.ExecContext(ctx, `UPDATE conversations SET folder_id = $2 WHERE id = $1`, conversationID, folder); err != nil {
+				return errors.Wrap(err, errors.ErrDatabaseError)
+			}
+		}
+
+		if change.TagIDs != nil {
+			if err := replaceConversationTags(ctx, tx, userID, conversationID, *change.TagIDs); err != nil {
+				return err
+			}
+		}
+
+		var err error
+		conv, err = scanConversation(tx.QueryRowContext(ctx, `SELECT `+conversationColumns+` FROM conversations c WHERE c.id = $1`, conversationID))
+		if err != nil {
+			if err == sql.ErrNoRows {
+				return errors.New(errors.ErrResourceNotFound, "Conversation not found")
+			}
+			return errors.Wrap(err, errors.ErrDatabaseError)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return conv, nil
+}
+
+// replaceConversationTags sets the conversation's tags to exactly tagIDs inside tx
+func replaceConversationTags(ctx context.Context, tx *sql.Tx, userID, conversationID uuid.UUID, tagIDs []uuid.UUID) error {
+	var owned int
+	err := tx.QueryRowContext(ctx, `
+		SELECT COUNT(*) FROM tags WHERE user_id = $1 AND id = ANY($2::uuid[])`,
+		userID, pq.Array(tagIDs)).Scan(&owned)
+	if err != nil {
+		return errors.Wrap(err, errors.ErrDatabaseError)
+	}
+	if owned != len(tagIDs) {
+		return errors.New(errors.ErrResourceNotFound, "Tag not found")
+	}
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM conversation_tags WHERE conversation_id = $1`, conversationID); err != nil {
+		return errors.Wrap(err, errors.ErrDatabaseError)
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO conversation_tags (conversation_id, tag_id)
+		SELECT $1, unnest($2::uuid[])`,
+		conversationID, pq.Array(tagIDs))
+	if err != nil {
+		return errors.Wrap(err, errors.ErrDatabaseError)
+	}
+
+	return nil
+}
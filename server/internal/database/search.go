@@ -0,0 +1,83 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"article-chat-system/server/internal/errors"
+	"article-chat-system/server/internal/models"
+	"github.com/google/uuid"
+)
+
+// Highlight markers used by ts_headline; control characters cannot appear in the
+// snippet by accident, so callers can escape the text and then swap in real markup
+const (
+	SearchHighlightStart = "\x02"
+	SearchHighlightStop  = "\x03"
+)
+
+// SearchMessages runs a full-text search over every message in the user's conversations
+// Results are ordered by relevance, newest first on ties
+func (db *DB) SearchMessages(ctx context.Context, userID uuid.UUID, search *models.MessageSearch, limit, offset int) ([]models.MessageSearchHit, error) {
+	conditions := []string{"c.user_id = $1", "m.content_tsv @@ q.query"}
+	headlineOptions := fmt.Sprintf("StartSel=%s, StopSel=%s, MaxWords=35, MinWords=15, MaxFragments=2",
+		SearchHighlightStart, SearchHighlightStop)
+	args := []interface{}{userID, search.Query, headlineOptions}
+
+	if search.Role != "" {
+		args = append(args, search.Role)
+		conditions = append(conditions, fmt.Sprintf("m.role = $%d", len(args)))
+	}
+	if search.From != nil {
+		args = append(args, *search.From)
+		conditions = append(conditions, fmt.Sprintf("m.created_at >= $%d", len(args)))
+	}
+	if search.To != nil {
+		args = append(args, *search.To)
+		conditions = append(conditions, fmt.Sprintf("m.created_at < $%d", len(args)))
+	}
+
+	args = append(args, limit, offset)
+
+	query := fmt.Sprintf(`
+		SELECT m.id, m.conversation_id, c.title, m.role, m.created_at,
+		       ts_rank(m.content_tsv, q.query) AS rank,
+		       ts_headline('english', m.content, q.query, $3)
+		FROM messages m
+		JOIN conversations c ON c.id = m.conversation_id
+		CROSS JOIN websearch_to_tsquery('english', $2) AS q(query)
+		WHERE %s
+		ORDER BY rank DESC, m.created_at DESC
+		LIMIT $%d OFFSET $%d`,
+		strings.Join(conditions, " AND "), len(args)-1, len(args))
+
+	rows, err := db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, errors.Wrap(err, errors.ErrDatabaseError)
+	}
+	defer rows.Close()
+
+	hits := []models.MessageSearchHit{}
+	for rows.Next() {
+		var hit models.MessageSearchHit
+		if err := rows.Scan(
+			&hit.MessageID,
+			&hit.ConversationID,
+			&hit.ConversationTitle,
+			&hit.Role,
+			&hit.CreatedAt,
+			&hit.Rank,
+			&hit.Snippet,
+		); err != nil {
+			return nil, errors.Wrap(err, errors.ErrDatabaseError)
+		}
+		hits = append(hits, hit)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, errors.Wrap(err, errors.ErrDatabaseError)
+	}
+
+	return hits, nil
+}
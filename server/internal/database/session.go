@@ -10,7 +10,7 @@ import (
 )
 
 // CreateSession creates a new user session
-func (db *DB) CreateSession(userID uuid.UUID, tokenHash string, userAgent, ipAddress string) (*models.UserSession, error) {
+func (db *DB) CreateSession(userID uuid.UUID, tokenHash string, userAgent, ipAddress string, viaSSO bool) (*models.UserSession, error) {
 	session := &models.UserSession{
 		ID:        uuid.New(),
 		UserID:    userID,
@@ -19,11 +19,12 @@ func (db *DB) CreateSession(userID uuid.UUID, tokenHash string, userAgent, ipAdd
 		CreatedAt: time.Now(),
 		UserAgent: userAgent,
 		IPAddress: ipAddress,
+		ViaSSO:    viaSSO,
 	}
 
 	query := `
-		INSERT INTO user_sessions (id, user_id, token_hash, expires_at, created_at, user_agent, ip_address)
-		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		INSERT INTO user_sessions (id, user_id, token_hash, expires_at, created_at, user_agent, ip_address, via_sso)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
 		RETURNING id, created_at, expires_at`
 
 	err := db.QueryRow(
@@ -35,6 +36,7 @@ func (db *DB) CreateSession(userID uuid.UUID, tokenHash string, userAgent, ipAdd
 		session.CreatedAt,
 		StringToNullString(session.UserAgent),
 		StringToNullString(session.IPAddress),
+		session.ViaSSO,
 	).Scan(&session.ID, &session.CreatedAt, &session.ExpiresAt)
 
 	if err != nil {
@@ -50,7 +52,7 @@ func (db *DB) GetSessionByToken(tokenHash string) (*models.UserSession, error) {
 	var userAgent, ipAddress sql.NullString
 
 	query := `
-		SELECT id, user_id, token_hash, expires_at, created_at, user_agent, ip_address
+		SELECT id, user_id, token_hash, expires_at, created_at, user_agent, ip_address, via_sso
 		FROM user_sessions
 		WHERE token_hash = $1 AND expires_at > NOW()`
 
@@ -62,6 +64,7 @@ func (db *DB) GetSessionByToken(tokenHash string) (*models.UserSession, error) {
 		&session.CreatedAt,
 		&userAgent,
 		&ipAddress,
+		&session.ViaSSO,
 	)
 
 	if err != nil {
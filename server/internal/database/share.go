@@ -0,0 +1,125 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"article-chat-system/server/internal/errors"
+	"article-chat-system/server/internal/models"
+	"github.com/google/uuid"
+)
+
+// CreateConversationShare stores a new share link for a conversation
+func (db *DB) CreateConversationShare(ctx context.Context, conversationID, userID uuid.UUID, tokenHash string, expiresAt *time.Time) (*models.ConversationShare, error) {
+	query := `
+		INSERT INTO conversation_shares (conversation_id, user_id, token_hash, expires_at)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id, conversation_id, expires_at, created_at, access_count`
+
+	var share models.ConversationShare
+	var expires sql.NullTime
+
+	err := db.QueryRowContext(ctx, query, conversationID, userID, tokenHash, TimeToNullTime(expiresAt)).Scan(
+		&share.ID,
+		&share.ConversationID,
+		&expires,
+		&share.CreatedAt,
+		&share.AccessCount,
+	)
+	if err != nil {
+		return nil, errors.Wrap(err, errors.ErrDatabaseError)
+	}
+
+	share.ExpiresAt = NullTimeToTime(expires)
+	return &share, nil
+}
+
+// GetConversationShares lists the share links of a conversation, newest first
+func (db *DB) GetConversationShares(ctx context.Context, conversationID uuid.UUID) ([]models.ConversationShare, error) {
+	query := `
+		SELECT id, conversation_id, expires_at, created_at, last_accessed_at, access_count
+		FROM conversation_shares
+		WHERE conversation_id = $1
+		ORDER BY created_at DESC`
+
+	rows, err := db.QueryContext(ctx, query, conversationID)
+	if err != nil {
+		return nil, errors.Wrap(err, errors.ErrDatabaseError)
+	}
+	defer rows.Close()
+
+	shares := []models.ConversationShare{}
+	for rows.Next() {
+		var share models.ConversationShare
+		var expires, lastAccessed sql.NullTime
+
+		if err := rows.Scan(
+			&share.ID,
+			&share.ConversationID,
+			&expires,
+			&share.CreatedAt,
+			&lastAccessed,
+			&share.AccessCount,
+		); err != nil {
+			return nil, errors.Wrap(err, errors.ErrDatabaseError)
+		}
+
+		share.ExpiresAt = NullTimeToTime(expires)
+		share.LastAccessedAt = NullTimeToTime(lastAccessed)
+		shares = append(shares, share)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, errors.Wrap(err, errors.ErrDatabaseError)
+	}
+
+	return shares, nil
+}
+
+// DeleteConversationShare revokes a share link of the given conversation
+func (db *DB) DeleteConversationShare(ctx context.Context, shareID, conversationID uuid.UUID) error {
+	query := `DELETE FROM conversation_shares WHERE id = $1 AND conversation_id = $2`
+
+	result, err := db.ExecContext(ctx, query, shareID, conversationID)
+	if err != nil {
+		return errors.Wrap(err, errors.ErrDatabaseError)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return errors.Wrap(err, errors.ErrDatabaseError)
+	}
+
+	if rowsAffected == 0 {
+		return errors.New(errors.ErrResourceNotFound, "Share not found")
+	}
+
+	return nil
+}
+
+// ResolveConversationShare finds the conversation behind an unexpired share token
+// and records the access. Links stop working while the owner's account is deactivated.
+func (db *DB) ResolveConversationShare(ctx context.Context, tokenHash string) (uuid.UUID, *time.Time, error) {
+	query := `
+		UPDATE conversation_shares s
+		SET last_accessed_at = NOW(), access_count = s.access_count + 1
+		FROM conversations c
+		JOIN users u ON u.id = c.user_id
+		WHERE s.token_hash = $1 AND (s.expires_at IS NULL OR s.expires_at > NOW())
+			AND c.id = s.conversation_id AND u.is_active
+		RETURNING s.conversation_id, s.expires_at`
+
+	var conversationID uuid.UUID
+	var expires sql.NullTime
+
+	err := db.QueryRowContext(ctx, query, tokenHash).Scan(&conversationID, &expires)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return uuid.Nil, nil, errors.New(errors.ErrResourceNotFound, "Shared conversation not found or link expired")
+		}
+		return uuid.Nil, nil, errors.Wrap(err, errors.ErrDatabaseError)
+	}
+
+	return conversationID, NullTimeToTime(expires), nil
+}
@@ -0,0 +1,64 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+
+	"article-chat-system/server/internal/errors"
+	"article-chat-system/server/internal/models"
+	"github.com/google/uuid"
+)
+
+// GetConversationSummary returns the rolling summary of a conversation's older turns
+// Summary is empty when none has been written yet
+func (db *DB) GetConversationSummary(ctx context.Context, conversationID uuid.UUID) (*models.ConversationSummary, error) {
+	query := `
+		SELECT COALESCE(summary, ''), summary_message_id, summary_updated_at
+		FROM conversations
+		WHERE id = $1`
+
+	var summary models.ConversationSummary
+	var messageID uuid.NullUUID
+	var updatedAt sql.NullTime
+	err := db.QueryRowContext(ctx, query, conversationID).Scan(&summary.Summary, &messageID, &updatedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errors.New(errors.ErrResourceNotFound, "Conversation not found")
+		}
+		return nil, errors.Wrap(err, errors.ErrDatabaseError)
+	}
+
+	if messageID.Valid {
+		summary.MessageID = &messageID.UUID
+	}
+	if updatedAt.Valid {
+		summary.UpdatedAt = &updatedAt.Time
+	}
+
+	return &summary, nil
+}
+
+// UpdateConversationSummary stores a rolling summary covering the branch up to messageID
+// Summaries are internal bookkeeping and do not change updated_at.
+func (db *DB) UpdateConversationSummary(ctx context.Context, conversationID uuid.UUID, summary string, messageID uuid.UUID) error {
+	query := `
+		UPDATE conversations
+		SET summary = $2, summary_message_id = $3, summary_updated_at = NOW()
+		WHERE id = $1`
+
+	result, err := db.ExecContext(ctx, query, conversationID, summary, messageID)
+	if err != nil {
+		return errors.Wrap(err, errors.ErrDatabaseError)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return errors.Wrap(err, errors.ErrDatabaseError)
+	}
+
+	if rowsAffected == 0 {
+		return errors.New(errors.ErrResourceNotFound, "Conversation not found")
+	}
+
+	return nil
+}
@@ -0,0 +1,259 @@
+package database
+
+import (
+	"database/sql"
+	"time"
+
+	"article-chat-system/server/internal/errors"
+	"article-chat-system/server/internal/models"
+	"github.com/google/uuid"
+)
+
+// SetPendingTOTPSecret stores a TOTP secret that is not active until confirmed
+// Restarting enrollment simply replaces the pending secret
+func (db *DB) SetPendingTOTPSecret(userID uuid.UUID, secret string) error {
+	query := `
+		UPDATE users
+		SET totp_secret = $2, totp_last_step = NULL
+		WHERE id = $1 AND totp_enabled_at IS NULL`
+
+	result, err := db.Exec(query, userID, secret)
+	if err != nil {
+		return errors.Wrap(err, errors.ErrDatabaseError)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return errors.Wrap(err, errors.ErrDatabaseError)
+	}
+
+	if rowsAffected == 0 {
+		return errors.New(errors.ErrValidationFailed, "Two-factor authentication is already enabled")
+	}
+
+	return nil
+}
+
+// GetTOTPState returns the stored TOTP secret and whether it has been confirmed
+func (db *DB) GetTOTPState(userID uuid.UUID) (string, bool, error) {
+	var secret sql.NullString
+	var enabled bool
+
+	query := `SELECT totp_secret, totp_enabled_at IS NOT NULL FROM users WHERE id = $1`
+
+	err := db.QueryRow(query, userID).Scan(&secret, &enabled)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return "", false, errors.New(errors.ErrResourceNotFound, "User not found")
+		}
+		return "", false, errors.Wrap(err, errors.ErrDatabaseError)
+	}
+
+	return NullStringToString(secret), enabled, nil
+}
+
+// ConsumeTOTPStep records the time step of an accepted code
+// Returns false if this step (or a later one) was already used, which blocks code replay
+func (db *DB) ConsumeTOTPStep(userID uuid.UUID, step int64) (bool, error) {
+	query := `
+		UPDATE users
+		SET totp_last_step = $2
+		WHERE id = $1 AND (totp_last_step IS NULL OR totp_last_step < $2)`
+
+	result, err := db.Exec(query, userID, step)
+	if err != nil {
+		return false, errors.Wrap(err, errors.ErrDatabaseError)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, errors.Wrap(err, errors.ErrDatabaseError)
+	}
+
+	return rowsAffected > 0, nil
+}
+
+// EnableTOTP activates the pending secret and replaces the user's recovery codes
+func (db *DB) EnableTOTP(userID uuid.UUID, recoveryCodeHashes []string) error {
+	return db.Transaction(func(tx *sql.Tx) error {
+		_, err := tx.Exec(`UPDATE users SET totp_enabled_at = NOW() WHERE id = $1 AND totp_secret IS NOT NULL`, userID)
+		if err != nil {
+			return errors.Wrap(err, errors.ErrDatabaseError)
+		}
+
+		if _, err := tx.Exec(`DELETE FROM user_recovery_codes WHERE user_id = $1`, userID); err != nil {
+			return errors.Wrap(err, errors.ErrDatabaseError)
+		}
+
+		for _, codeHash := range recoveryCodeHashes {
+			_, err := tx.Exec(`INSERT INTO user_recovery_codes (user_id, code_hash) VALUES ($1, $2)`, userID, codeHash)
+			if err != nil {
+				return errors.Wrap(err, errors.ErrDatabaseError)
+			}
+		}
+
+		return nil
+	})
+}
+
+// DisableTOTP removes the TOTP secret, recovery codes and pending challenges for a user
+func (db *DB) DisableTOTP(userID uuid.UUID) error {
+	return db.Transaction(func(tx *sql.Tx) error {
+		query := `
+			UPDATE users
+			SET totp_secret = NULL, totp_enabled_at = NULL, totp_last_step = NULL
+			WHERE id = $1`
+
+		if _, err := tx.Exec(query, userID); err != nil {
+			return errors.Wrap(err, errors.ErrDatabaseError)
+		}
+
+		if _, err := tx.Exec(`DELETE FROM user_recovery_codes WHERE user_id = $1`, userID); err != nil {
+			return errors.Wrap(err, errors.ErrDatabaseError)
+		}
+
+		if _, err := tx.Exec(`DELETE FROM two_factor_challenges WHERE user_id = $1`, userID); err != nil {
+			return errors.Wrap(err, errors.ErrDatabaseError)
+		}
+
+		return nil
+	})
+}
+
+// UseRecoveryCode marks a matching unused recovery code as used
+// Returns false if no unused code matches
+func (db *DB) UseRecoveryCode(userID uuid.UUID, codeHash string) (bool, error) {
+	query := `
+		UPDATE user_recovery_codes
+		SET used_at = NOW()
+		WHERE id = (
+			SELECT id FROM user_recovery_codes
+			WHERE user_id = $1 AND code_hash = $2 AND used_at IS NULL
+			LIMIT 1
+		)`
+
+	result, err := db.Exec(query, userID, codeHash)
+	if err != nil {
+		return false, errors.Wrap(err, errors.ErrDatabaseError)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, errors.Wrap(err, errors.ErrDatabaseError)
+	}
+
+	return rowsAffected > 0, nil
+}
+
+// CountUnusedRecoveryCodes returns how many recovery codes a user has left
+func (db *DB) CountUnusedRecoveryCodes(userID uuid.UUID) (int, error) {
+	query := `SELECT COUNT(*) FROM user_recovery_codes WHERE user_id = $1 AND used_at IS NULL`
+
+	var count int
+	if err := db.QueryRow(query, userID).Scan(&count); err != nil {
+		return 0, errors.Wrap(err, errors.ErrDatabaseError)
+	}
+
+	return count, nil
+}
+
+// CreateTwoFactorChallenge stores a pending login challenge
+func (db *DB) CreateTwoFactorChallenge(userID uuid.UUID, tokenHash string, ttl time.Duration, userAgent, ipAddress string, viaSSO bool) (*models.TwoFactorChallenge, error) {
+	challenge := &models.TwoFactorChallenge{
+		ID:        uuid.New(),
+		UserID:    userID,
+		TokenHash: tokenHash,
+		ExpiresAt: time.Now().Add(ttl),
+		CreatedAt: time.Now(),
+		UserAgent: userAgent,
+		IPAddress: ipAddress,
+		ViaSSO:    viaSSO,
+	}
+
+	query := `
+		INSERT INTO two_factor_challenges (id, user_id, token_hash, expires_at, created_at, user_agent, ip_address, via_sso)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`
+
+	// A new challenge replaces any outstanding one, so each login can't bring a fresh set of attempts
+	err := db.Transaction(func(tx *sql.Tx) error {
+		if _, err := tx.Exec(`DELETE FROM two_factor_challenges WHERE user_id = $1`, userID); err != nil {
+			return errors.Wrap(err, errors.ErrDatabaseError)
+		}
+
+		_, err := tx.Exec(
+			query,
+			challenge.ID,
+			challenge.UserID,
+			challenge.TokenHash,
+			challenge.ExpiresAt,
+			challenge.CreatedAt,
+			StringToNullString(challenge.UserAgent),
+			StringToNullString(challenge.IPAddress),
+			challenge.ViaSSO,
+		)
+		if err != nil {
+			return errors.Wrap(err, errors.ErrDatabaseError)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return challenge, nil
+}
+
+// GetTwoFactorChallenge retrieves an unexpired challenge by its token hash
+func (db *DB) GetTwoFactorChallenge(tokenHash string) (*models.TwoFactorChallenge, error) {
+	challenge := &models.TwoFactorChallenge{}
+	var userAgent, ipAddress sql.NullString
+
+	query := `
+		SELECT id, user_id, token_hash, attempts, expires_at, created_at, user_agent, ip_address, via_sso
+		FROM two_factor_challenges
+		WHERE token_hash = $1 AND expires_at > NOW()`
+
+	err := db.QueryRow(query, tokenHash).Scan(
+		&challenge.ID,
+		&challenge.UserID,
+		&challenge.TokenHash,
+		&challenge.Attempts,
+		&challenge.ExpiresAt,
+		&challenge.CreatedAt,
+		&userAgent,
+		&ipAddress,
+		&challenge.ViaSSO,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errors.New(errors.ErrUnauthorized, "Invalid or expired two-factor challenge")
+		}
+		return nil, errors.Wrap(err, errors.ErrDatabaseError)
+	}
+
+	challenge.UserAgent = NullStringToString(userAgent)
+	challenge.IPAddress = NullStringToString(ipAddress)
+
+	return challenge, nil
+}
+
+// IncrementChallengeAttempts records a failed code entry and returns the new attempt count
+func (db *DB) IncrementChallengeAttempts(challengeID uuid.UUID) (int, error) {
+	query := `UPDATE two_factor_challenges SET attempts = attempts + 1 WHERE id = $1 RETURNING attempts`
+
+	var attempts int
+	if err := db.QueryRow(query, challengeID).Scan(&attempts); err != nil {
+		return 0, errors.Wrap(err, errors.ErrDatabaseError)
+	}
+
+	return attempts, nil
+}
+
+// DeleteTwoFactorChallenge removes a challenge once it has been completed or exhausted
+func (db *DB) DeleteTwoFactorChallenge(challengeID uuid.UUID) error {
+	_, err := db.Exec(`DELETE FROM two_factor_challenges WHERE id = $1`, challengeID)
+	if err != nil {
+		return errors.Wrap(err, errors.ErrDatabaseError)
+	}
+	return nil
+}
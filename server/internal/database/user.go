@@ -53,7 +53,8 @@ func (db *DB) GetUserByEmail(email string) (*models.User, error) {
 	var lastLogin sql.NullTime
 
 	query := `
-		SELECT id, email, full_name, created_at, updated_at, last_login, is_active
+		SELECT id, email, full_name, created_at, updated_at, last_login, is_active,
+		       totp_enabled_at IS NOT NULL, email_verified_at IS NOT NULL
 		FROM users
 		WHERE email = $1`
 
@@ -65,6 +66,8 @@ func (db *DB) GetUserByEmail(email string) (*models.User, error) {
 		&user.UpdatedAt,
 		&lastLogin,
 		&user.IsActive,
+		&user.TwoFactorEnabled,
+		&user.EmailVerified,
 	)
 
 	if err != nil {
@@ -84,7 +87,8 @@ func (db *DB) GetUserByID(userID uuid.UUID) (*models.User, error) {
 	var lastLogin sql.NullTime
 
 	query := `
-		SELECT id, email, full_name, created_at, updated_at, last_login, is_active
+		SELECT id, email, full_name, created_at, updated_at, last_login, is_active,
+		       totp_enabled_at IS NOT NULL, email_verified_at IS NOT NULL
 		FROM users
 		WHERE id = $1`
 
@@ -96,6 +100,8 @@ func (db *DB) GetUserByID(userID uuid.UUID) (*models.User, error) {
 		&user.UpdatedAt,
 		&lastLogin,
 		&user.IsActive,
+		&user.TwoFactorEnabled,
+		&user.EmailVerified,
 	)
 
 	if err != nil {
@@ -184,6 +190,26 @@ func (db *DB) DeactivateUser(userID uuid.UUID) error {
 	return nil
 }
 
+// MarkEmailVerified records that the user proved ownership of email
+// Returns false when the address has changed since or was already verified
+func (db *DB) MarkEmailVerified(userID uuid.UUID, email string) (bool, error) {
+	query := `
+		UPDATE users SET email_verified_at = NOW(), updated_at = NOW()
+		WHERE id = $1 AND email = $2 AND email_verified_at IS NULL`
+
+	result, err := db.Exec(query, userID, email)
+	if err != nil {
+		return false, errors.Wrap(err, errors.ErrDatabaseError)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, errors.Wrap(err, errors.ErrDatabaseError)
+	}
+
+	return rowsAffected == 1, nil
+}
+
 // CheckEmailExists checks if an email already exists in the database
 func (db *DB) CheckEmailExists(email string) (bool, error) {
 	var exists bool
@@ -53,6 +53,9 @@ const (
 	ErrInvalidDataType       ErrorCode = "INVALID_DATA_TYPE"       // Wrong data type for field
 	ErrInvalidConversationID ErrorCode = "INVALID_CONVERSATION_ID" // Invalid conversation ID format
 	ErrRateLimitExceeded     ErrorCode = "RATE_LIMIT_EXCEEDED"     // Too many requests from client
+	ErrAccountLocked         ErrorCode = "ACCOUNT_LOCKED"          // Too many failed logins, account temporarily locked
+	ErrFileTooLarge          ErrorCode = "FILE_TOO_LARGE"          // Uploaded file exceeds the size limit
+	ErrUnsupportedFileType   ErrorCode = "UNSUPPORTED_FILE_TYPE"   // Uploaded file is not an accepted document type
 
 	// AUTHENTICATION & AUTHORIZATION (401-403) - Security and access control
 	ErrMissingAPIKey ErrorCode = "MISSING_API_KEY" // ANTHROPIC_API_KEY not provided
@@ -75,6 +78,7 @@ const (
 	ErrDatabaseError      ErrorCode = "DATABASE_ERROR"        // PostgreSQL operation failed
 	ErrCacheError         ErrorCode = "CACHE_ERROR"           // Redis cache operation failed
 	ErrRAGServiceError    ErrorCode = "RAG_SERVICE_ERROR"     // Node.js RAG service communication failed
+	ErrArticleFetchFailed ErrorCode = "ARTICLE_FETCH_FAILED"  // Article page could not be fetched or extracted
 
 	// CONFIGURATION ERRORS - Service setup and initialization issues
 	ErrMissingEnvVar         ErrorCode = "MISSING_ENV_VAR"         // Required environment variable missing
@@ -86,12 +90,15 @@ const (
 // This ensures consistent HTTP responses across both Go backend and Node.js RAG services
 var StatusCodes = map[ErrorCode]int{
 	// Client Errors (400s) - Issues with user input or requests
-	ErrBadRequest:            http.StatusBadRequest,      // 400 - Bad Request
-	ErrValidationFailed:      http.StatusBadRequest,      // 400 - Bad Request
-	ErrMissingRequiredField:  http.StatusBadRequest,      // 400 - Bad Request
-	ErrInvalidDataType:       http.StatusBadRequest,      // 400 - Bad Request
-	ErrInvalidConversationID: http.StatusBadRequest,      // 400 - Bad Request
-	ErrRateLimitExceeded:     http.StatusTooManyRequests, // 429 - Too Many Requests
+	ErrBadRequest:            http.StatusBadRequest,            // 400 - Bad Request
+	ErrValidationFailed:      http.StatusBadRequest,            // 400 - Bad Request
+	ErrMissingRequiredField:  http.StatusBadRequest,            // 400 - Bad Request
+	ErrInvalidDataType:       http.StatusBadRequest,            // 400 - Bad Request
+	ErrInvalidConversationID: http.StatusBadRequest,            // 400 - Bad Request
+	ErrRateLimitExceeded:     http.StatusTooManyRequests,       // 429 - Too Many Requests
+	ErrAccountLocked:         http.StatusTooManyRequests,       // 429 - Too Many Requests (sent with Retry-After)
+	ErrFileTooLarge:          http.StatusRequestEntityTooLarge, // 413 - Request Entity Too Large
+	ErrUnsupportedFileType:   http.StatusUnsupportedMediaType,  // 415 - Unsupported Media Type
 
 	// Authentication & Authorization (401-403) - Security issues
 	ErrMissingAPIKey: http.StatusUnauthorized, // 401 - Unauthorized
@@ -114,6 +121,7 @@ var StatusCodes = map[ErrorCode]int{
 	ErrDatabaseError:      http.StatusInternalServerError, // 500 - Internal Server Error
 	ErrCacheError:         http.StatusInternalServerError, // 500 - Internal Server Error
 	ErrRAGServiceError:    http.StatusBadGateway,          // 502 - Bad Gateway (RAG service failure)
+	ErrArticleFetchFailed: http.StatusBadGateway,          // 502 - Bad Gateway (article site failure)
 
 	// Configuration Errors - Service setup issues
 	ErrMissingEnvVar:         http.StatusInternalServerError, // 500 - Internal Server Error
@@ -129,6 +137,9 @@ type AppError struct {
 	Details   interface{} `json:"details,omitempty"`    // Additional error context (validation failures, etc.)
 	RequestID string      `json:"request_id,omitempty"` // Unique request ID for tracing across services
 	Timestamp time.Time   `json:"timestamp"`            // When the error occurred
+
+	// RetryAfter is not serialized; the error handler turns it into a Retry-After header
+	RetryAfter time.Duration `json:"-"`
 }
 
 // Error implements the standard Go error interface
@@ -174,6 +185,13 @@ func (e *AppError) WithRequestID(requestID string) *AppError {
 	return e
 }
 
+// WithRetryAfter tells the client how long to wait before retrying
+// Used by throttling and lockout errors so clients can back off correctly
+func (e *AppError) WithRetryAfter(d time.Duration) *AppError {
+	e.RetryAfter = d
+	return e
+}
+
 // Wrap converts a standard Go error into an AppError with specified error code
 // Preserves existing AppErrors while standardizing other error types
 func Wrap(err error, code ErrorCode) *AppError {
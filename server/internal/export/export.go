@@ -0,0 +1,133 @@
+// Package export renders conversations as portable documents
+//
+// SUPPORTED FORMATS:
+// - json: Lossless document (messages with timestamps and raw metadata), accepted by the importer
+// - md:   Markdown transcript with cited sources as footnotes
+// - html: Self-contained, printable HTML page with cited sources as footnotes
+package export
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+	"time"
+
+	"article-chat-system/server/internal/models"
+)
+
+// Supported export formats
+const (
+	FormatJSON     = "json"
+	FormatMarkdown = "md"
+	FormatHTML     = "html"
+)
+
+// FormatVersion identifies the JSON document layout; bump it on incompatible changes
+const FormatVersion = 1
+
+// Document is the JSON export layout for a single conversation
+type Document struct {
+	FormatVersion int                             `json:"format_version"`
+	ExportedAt    time.Time                       `json:"exported_at"`
+	Conversation  models.ConversationWithMessages `json:"conversation"`
+}
+
+// footnote is a cited source rendered below the transcript
+type footnote struct {
+	Number int
+	Source models.ChunkSource
+}
+
+// IsSupported reports whether format is a known export format
+func IsSupported(format string) bool {
+	switch format {
+	case FormatJSON, FormatMarkdown, FormatHTML:
+		return true
+	}
+	return false
+}
+
+// ContentType returns the MIME type for a format
+func ContentType(format string) string {
+	switch format {
+	case FormatMarkdown:
+		return "text/markdown; charset=utf-8"
+	case FormatHTML:
+		return "text/html; charset=utf-8"
+	default:
+		return "application/json"
+	}
+}
+
+// Write renders conv in the requested format
+func Write(w io.Writer, conv *models.ConversationWithMessages, format string) error {
+	switch format {
+	case FormatJSON:
+		return writeJSON(w, conv)
+	case FormatMarkdown:
+		return writeMarkdown(w, conv)
+	case FormatHTML:
+		return writeHTML(w, conv)
+	default:
+		return fmt.Errorf("unsupported export format %q", format)
+	}
+}
+
+// Filename builds a readable, filesystem-safe file name for a conversation export
+func Filename(conv *models.Conversation, format string) string {
+	slug := strings.Trim(nonSlugChars.ReplaceAllString(strings.ToLower(conv.Title), "-"), "-")
+	if len(slug) > 50 {
+		slug = strings.TrimRight(slug[:50], "-")
+	}
+	if slug == "" {
+		slug = "conversation"
+	}
+
+	return fmt.Sprintf("%s-%s-%s.%s", conv.CreatedAt.Format(time.DateOnly), slug, conv.ID.String()[:8], format)
+}
+
+var nonSlugChars = regexp.MustCompile(`[^a-z0-9]+`)
+
+func writeJSON(w io.Writer, conv *models.ConversationWithMessages) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	encoder.SetEscapeHTML(false)
+	return encoder.Encode(Document{
+		FormatVersion: FormatVersion,
+		ExportedAt:    time.Now().UTC(),
+		Conversation:  *conv,
+	})
+}
+
+// MessageSources extracts the cited chunks stored in an assistant message's metadata
+// Metadata is decoded from JSONB as generic maps, so the sources are re-decoded into ChunkSource
+func MessageSources(msg models.Message) []models.ChunkSource {
+	raw, ok := msg.Metadata["sources"]
+	if !ok {
+		return nil
+	}
+
+	data, err := json.Marshal(raw)
+	if err != nil {
+		return nil
+	}
+
+	var sources []models.ChunkSource
+	if err := json.Unmarshal(data, &sources); err != nil {
+		return nil
+	}
+
+	return sources
+}
+
+// excerpt shortens chunk content for footnotes without splitting UTF-8 characters
+func excerpt(content string, maxRunes int) string {
+	content = strings.Join(strings.Fields(content), " ")
+	runes := []rune(content)
+	if len(runes) <= maxRunes {
+		return content
+	}
+	return strings.TrimSpace(string(runes[:maxRunes])) + "…"
+}
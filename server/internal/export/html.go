@@ -0,0 +1,102 @@
+package export
+
+import (
+	"html/template"
+	"io"
+	"time"
+
+	"article-chat-system/server/internal/models"
+)
+
+// htmlMessage is a message prepared for the HTML template
+type htmlMessage struct {
+	Role      string
+	Label     string
+	Timestamp string
+	Content   string
+	Notes     []int
+}
+
+// htmlFootnote is a cited source prepared for the HTML template
+type htmlFootnote struct {
+	Number    int
+	Title     string
+	Relevance string
+	Excerpt   string
+}
+
+var htmlTemplate = template.Must(template.New("conversation").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>{{.Title}}</title>
+<style>
+body { font-family: system-ui, sans-serif; max-width: 48rem; margin: 2rem auto; padding: 0 1rem; color: #1f2933; }
+header p { color: #616e7c; }
+article { border-top: 1px solid #e4e7eb; padding: 1rem 0; }
+article.user .content { background: #f5f7fa; border-radius: .5rem; padding: .75rem; }
+.meta { font-size: .85rem; color: #616e7c; margin-bottom: .5rem; }
+.content { white-space: pre-wrap; line-height: 1.5; }
+sup a { text-decoration: none; }
+.footnotes { border-top: 2px solid #e4e7eb; margin-top: 2rem; font-size: .9rem; }
+.footnotes li { margin-bottom: .5rem; }
+@media print { body { margin: 0; } }
+</style>
+</head>
+<body>
+<header>
+<h1>{{.Title}}</h1>
+<p>Started {{.Started}} · {{len .Messages}} messages · exported {{.Exported}}</p>
+</header>
+{{range .Messages}}<article class="{{.Role}}">
+<div class="meta"><strong>{{.Label}}</strong> · {{.Timestamp}}</div>
+<div class="content">{{.Content}}{{range .Notes}}<sup id="ref-{{.}}"><a href="#note-{{.}}">[{{.}}]</a></sup>{{end}}</div>
+</article>
+{{end}}{{if .Footnotes}}<section class="footnotes">
+<h2>Sources</h2>
+<ol>
+{{range .Footnotes}}<li id="note-{{.Number}}"><strong>{{.Title}}</strong> (relevance {{.Relevance}}) — “{{.Excerpt}}” <a href="#ref-{{.Number}}">↩</a></li>
+{{end}}</ol>
+</section>
+{{end}}</body>
+</html>
+`))
+
+// writeHTML renders a standalone page; html/template escapes all message content
+func writeHTML(w io.Writer, conv *models.ConversationWithMessages) error {
+	data := struct {
+		Title     string
+		Started   string
+		Exported  string
+		Messages  []htmlMessage
+		Footnotes []htmlFootnote
+	}{
+		Title:    conv.Title,
+		Started:  formatTimestamp(conv.CreatedAt),
+		Exported: formatTimestamp(time.Now()),
+	}
+
+	for _, msg := range conv.Messages {
+		item := htmlMessage{
+			Role:      msg.Role,
+			Label:     roleLabel(msg.Role),
+			Timestamp: formatTimestamp(msg.CreatedAt),
+			Content:   msg.Content,
+		}
+
+		for _, source := range MessageSources(msg) {
+			number := len(data.Footnotes) + 1
+			item.Notes = append(item.Notes, number)
+			data.Footnotes = append(data.Footnotes, htmlFootnote{
+				Number:    number,
+				Title:     sourceTitle(source),
+				Relevance: formatRelevance(source.Relevance),
+				Excerpt:   excerpt(source.Content, 300),
+			})
+		}
+
+		data.Messages = append(data.Messages, item)
+	}
+
+	return htmlTemplate.Execute(w, data)
+}
@@ -0,0 +1,165 @@
+package export
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+	"time"
+
+	"article-chat-system/server/internal/models"
+	"github.com/google/uuid"
+)
+
+// ParseDocuments decodes a single JSON export document or an array of them
+func ParseDocuments(data []byte) ([]Document, error) {
+	data = bytes.TrimSpace(data)
+	if len(data) == 0 {
+		return nil, fmt.Errorf("empty import")
+	}
+
+	if data[0] == '[' {
+		var docs []Document
+		if err := json.Unmarshal(data, &docs); err != nil {
+			return nil, fmt.Errorf("invalid JSON export: %w", err)
+		}
+		return docs, nil
+	}
+
+	var doc Document
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("invalid JSON export: %w", err)
+	}
+	return []Document{doc}, nil
+}
+
+// Archive limits, so a small zip can't inflate into more than the server can hold
+const (
+	maxArchiveEntries = 10000     // Files and directories in one archive
+	maxArchiveBytes   = 128 << 20 // Decompressed bytes across all JSON files
+)
+
+// ReadArchive decodes every .json document in a zip produced by the bulk export
+// Files in other formats are ignored since only JSON exports are lossless.
+// Reading stops as soon as the archive holds more than maxDocs documents.
+func ReadArchive(data []byte, maxDocs int) ([]Document, error) {
+	archive, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, fmt.Errorf("invalid zip archive: %w", err)
+	}
+
+	if len(archive.File) > maxArchiveEntries {
+		return nil, fmt.Errorf("archive has more than %d files", maxArchiveEntries)
+	}
+
+	var docs []Document
+	remaining := int64(maxArchiveBytes)
+	for _, file := range archive.File {
+		if file.FileInfo().IsDir() || !strings.EqualFold(path.Ext(file.Name), ".json") {
+			continue
+		}
+
+		// The declared size is checked up front; the limited read below catches archives that lie about it
+		if file.UncompressedSize64 > uint64(remaining) {
+			return nil, fmt.Errorf("archive is larger than %d MB uncompressed", maxArchiveBytes>>20)
+		}
+
+		content, err := readArchiveFile(file, remaining)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", file.Name, err)
+		}
+		remaining -= int64(len(content))
+
+		parsed, err := ParseDocuments(content)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", file.Name, err)
+		}
+		docs = append(docs, parsed...)
+
+		if len(docs) > maxDocs {
+			return nil, fmt.Errorf("archive has more than %d conversations", maxDocs)
+		}
+	}
+
+	if len(docs) == 0 {
+		return nil, fmt.Errorf("archive contains no JSON exports")
+	}
+
+	return docs, nil
+}
+
+// readArchiveFile inflates one archive entry, failing once it passes limit bytes
+func readArchiveFile(file *zip.File, limit int64) ([]byte, error) {
+	rc, err := file.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+
+	content, err := io.ReadAll(io.LimitReader(rc, limit+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(content)) > limit {
+		return nil, fmt.Errorf("archive is larger than %d MB uncompressed", maxArchiveBytes>>20)
+	}
+
+	return content, nil
+}
+
+// Conversations validates documents and returns their conversations ready for import
+// Missing timestamps are filled in so imported history still sorts sensibly
+func Conversations(docs []Document) ([]models.ConversationWithMessages, error) {
+	conversations := make([]models.ConversationWithMessages, 0, len(docs))
+	now := time.Now()
+
+	for i, doc := range docs {
+		if doc.FormatVersion < 1 || doc.FormatVersion > FormatVersion {
+			return nil, fmt.Errorf("conversation %d: unsupported format_version %d", i+1, doc.FormatVersion)
+		}
+
+		conv := doc.Conversation
+		if conv.ID == uuid.Nil {
+			return nil, fmt.Errorf("conversation %d: missing id", i+1)
+		}
+
+		conv.Title = strings.TrimSpace(conv.Title)
+		if conv.Title == "" {
+			conv.Title = "Imported Conversation"
+		}
+		if len(conv.Title) > 255 {
+			return nil, fmt.Errorf("conversation %d: title must be 255 characters or less", i+1)
+		}
+		if conv.CreatedAt.IsZero() {
+			conv.CreatedAt = now
+		}
+
+		lastActivity := conv.CreatedAt
+		for j := range conv.Messages {
+			msg := &conv.Messages[j]
+			if msg.Role != "user" && msg.Role != "assistant" {
+				return nil, fmt.Errorf("conversation %d, message %d: role must be 'user' or 'assistant'", i+1, j+1)
+			}
+			if strings.TrimSpace(msg.Content) == "" {
+				return nil, fmt.Errorf("conversation %d, message %d: content is empty", i+1, j+1)
+			}
+			if msg.CreatedAt.IsZero() {
+				msg.CreatedAt = conv.CreatedAt
+			}
+			if msg.CreatedAt.After(lastActivity) {
+				lastActivity = msg.CreatedAt
+			}
+		}
+
+		if conv.UpdatedAt.Before(lastActivity) {
+			conv.UpdatedAt = lastActivity
+		}
+
+		conversations = append(conversations, conv)
+	}
+
+	return conversations, nil
+}
@@ -0,0 +1,70 @@
+package export
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"article-chat-system/server/internal/models"
+)
+
+// writeMarkdown renders a transcript with footnote references after each cited answer
+func writeMarkdown(w io.Writer, conv *models.ConversationWithMessages) error {
+	bw := bufio.NewWriter(w)
+
+	fmt.Fprintf(bw, "# %s\n\n", conv.Title)
+	fmt.Fprintf(bw, "_Started %s · %d messages · exported %s_\n\n",
+		formatTimestamp(conv.CreatedAt), len(conv.Messages), formatTimestamp(time.Now()))
+
+	var notes []footnote
+	for _, msg := range conv.Messages {
+		fmt.Fprintf(bw, "---\n\n**%s** · %s\n\n", roleLabel(msg.Role), formatTimestamp(msg.CreatedAt))
+		bw.WriteString(strings.TrimSpace(msg.Content))
+
+		sources := MessageSources(msg)
+		if len(sources) > 0 {
+			bw.WriteString("\n\nSources:")
+			for _, source := range sources {
+				notes = append(notes, footnote{Number: len(notes) + 1, Source: source})
+				fmt.Fprintf(bw, " [^%d]", len(notes))
+			}
+		}
+		bw.WriteString("\n\n")
+	}
+
+	if len(notes) > 0 {
+		bw.WriteString("---\n\n")
+		for _, note := range notes {
+			fmt.Fprintf(bw, "[^%d]: **%s** (relevance %s) — “%s”\n",
+				note.Number, sourceTitle(note.Source), formatRelevance(note.Source.Relevance), excerpt(note.Source.Content, 200))
+		}
+	}
+
+	return bw.Flush()
+}
+
+// roleLabel returns the display name for a message role
+func roleLabel(role string) string {
+	if role == "user" {
+		return "You"
+	}
+	return "Assistant"
+}
+
+// sourceTitle falls back to the article ID for sources without a title
+func sourceTitle(source models.ChunkSource) string {
+	if source.ArticleTitle != "" {
+		return source.ArticleTitle
+	}
+	return source.ArticleID
+}
+
+func formatRelevance(relevance float32) string {
+	return fmt.Sprintf("%.2f", relevance)
+}
+
+func formatTimestamp(t time.Time) string {
+	return t.UTC().Format("2006-01-02 15:04 UTC")
+}
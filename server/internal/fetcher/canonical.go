@@ -0,0 +1,144 @@
+package fetcher
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/url"
+	"path"
+	"strings"
+)
+
+// trackingParams are query parameters that identify the click, not the article
+var trackingParams = map[string]bool{
+	"fbclid": true, "gclid": true, "dclid": true, "gbraid": true, "wbraid": true, "msclkid": true,
+	"yclid": true, "twclid": true, "igshid": true, "mc_cid": true, "mc_eid": true, "mkt_tok": true,
+	"_ga": true, "_gl": true, "_hsenc": true, "_hsmi": true, "ref": true, "ref_src": true, "ref_url": true,
+	"cmpid": true, "ncid": true, "guccounter": true, "guce_referrer": true, "guce_referrer_sig": true,
+	"sr_share": true, "share": true, "spm": true, "s_cid": true, "smid": true, "soc_src": true, "soc_trk": true,
+	"amp": true,
+}
+
+// trackingPrefixes match families of tracking parameters such as utm_source and utm_medium
+var trackingPrefixes = []string{"utm_", "pk_", "mtm_", "oly_", "__twitter"}
+
+// CanonicalURL normalizes an article URL so that variants of one article compare equal
+// The scheme becomes https, the host is lowercased without default port, and fragments,
+// credentials and tracking parameters are dropped. The remaining query is sorted, trailing
+// slashes are removed and AMP variants (including Google AMP cache URLs) map to the regular page.
+func CanonicalURL(rawURL string) (string, error) {
+	u, err := url.Parse(strings.TrimSpace(rawURL))
+	if err != nil {
+		return "", fmt.Errorf("invalid URL format: %w", err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" || u.Hostname() == "" {
+		return "", fmt.Errorf("URL must be absolute and use http or https")
+	}
+
+	u = fromAMPCache(u)
+	host := strings.TrimSuffix(strings.ToLower(u.Hostname()), ".")
+	if port := u.Port(); port != "" && port != "80" && port != "443" {
+		host += ":" + port
+	}
+
+	canonical := &url.URL{
+		Scheme:   "https",
+		Host:     host,
+		Path:     canonicalPath(u.Path),
+		RawQuery: canonicalQuery(u.Query()),
+	}
+	return canonical.String(), nil
+}
+
+// fromAMPCache rewrites a Google AMP cache URL (https://example-com.cdn.ampproject.org/c/s/example.com/a)
+// to the publisher's URL
+func fromAMPCache(u *url.URL) *url.URL {
+	if !strings.HasSuffix(strings.ToLower(u.Hostname()), ".cdn.ampproject.org") {
+		return u
+	}
+
+	rest := u.Path
+	for _, prefix := range []string{"/c/s/", "/v/s/", "/c/", "/v/"} {
+		if strings.HasPrefix(rest, prefix) {
+			scheme := "http"
+			if strings.HasSuffix(prefix, "/s/") {
+				scheme = "https"
+			}
+			publisher, err := url.Parse(scheme + "://" + strings.TrimPrefix(rest, prefix))
+			if err != nil || publisher.Hostname() == "" {
+				return u
+			}
+			publisher.RawQuery = u.RawQuery
+			return publisher
+		}
+	}
+	return u
+}
+
+// canonicalPath cleans a path and strips AMP markers: a leading or trailing /amp segment and .amp extensions
+func canonicalPath(p string) string {
+	if p == "" {
+		return "/"
+	}
+
+	p = path.Clean(p)
+	p = strings.TrimSuffix(p, "/amp")
+	if strings.HasPrefix(p, "/amp/") {
+		p = strings.TrimPrefix(p, "/amp")
+	}
+	p = strings.Replace(p, ".amp.html", ".html", 1)
+	p = strings.TrimSuffix(p, ".amp")
+
+	if p == "" || p == "." {
+		return "/"
+	}
+	return p
+}
+
+// canonicalQuery drops tracking parameters and encodes the rest sorted by key
+func canonicalQuery(query url.Values) string {
+	for key := range query {
+		if isTrackingParam(key) || strings.EqualFold(key, "outputType") && strings.EqualFold(query.Get(key), "amp") {
+			query.Del(key)
+		}
+	}
+	return query.Encode()
+}
+
+func isTrackingParam(key string) bool {
+	key = strings.ToLower(key)
+	if trackingParams[key] {
+		return true
+	}
+	for _, prefix := range trackingPrefixes {
+		if strings.HasPrefix(key, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// sameSite reports whether two canonical URLs belong to the same site, ignoring www., m. and amp. hosts
+// A page may only declare a canonical URL on its own site.
+func sameSite(a, b string) bool {
+	hostA, errA := url.Parse(a)
+	hostB, errB := url.Parse(b)
+	if errA != nil || errB != nil {
+		return false
+	}
+	return siteHost(hostA.Host) == siteHost(hostB.Host)
+}
+
+func siteHost(host string) string {
+	for _, prefix := range []string{"www.", "m.", "amp."} {
+		host = strings.TrimPrefix(host, prefix)
+	}
+	return host
+}
+
+// ContentHash returns the SHA-256 of extracted article text with whitespace normalized
+// Identical articles served under unrelated URLs share the same hash.
+func ContentHash(content string) string {
+	sum := sha256.Sum256([]byte(collapseSpace(content)))
+	return hex.EncodeToString(sum[:])
+}
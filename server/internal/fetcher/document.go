@@ -0,0 +1,236 @@
+package fetcher
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"net/url"
+	"path/filepath"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"article-chat-system/server/internal/models"
+	"github.com/google/uuid"
+	"github.com/ledongthuc/pdf"
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/charset"
+)
+
+// Document types accepted for upload
+const (
+	DocumentText     = "text"
+	DocumentMarkdown = "markdown"
+	DocumentHTML     = "html"
+	DocumentPDF      = "pdf"
+)
+
+// documentExtensions maps file extensions to document types
+var documentExtensions = map[string]string{
+	".txt":      DocumentText,
+	".text":     DocumentText,
+	".md":       DocumentMarkdown,
+	".markdown": DocumentMarkdown,
+	".html":     DocumentHTML,
+	".htm":      DocumentHTML,
+	".xhtml":    DocumentHTML,
+	".pdf":      DocumentPDF,
+}
+
+var (
+	// ErrUnsupportedDocument is returned for files that are not text, Markdown, HTML or PDF
+	ErrUnsupportedDocument = errors.New("unsupported document type")
+	// ErrNoDocumentText is returned for documents without extractable text, such as scanned PDFs
+	ErrNoDocumentText = errors.New("document has no extractable text")
+)
+
+// DocumentType returns the type of an uploaded file from its extension
+// The content must match: PDFs start with the PDF header and the other types must sniff as text.
+func DocumentType(filename string, data []byte) (string, error) {
+	docType, ok := documentExtensions[strings.ToLower(filepath.Ext(filename))]
+	if !ok {
+		return "", ErrUnsupportedDocument
+	}
+
+	if docType == DocumentPDF {
+		if !bytes.HasPrefix(data, []byte("%PDF-")) {
+			return "", fmt.Errorf("%w: not a PDF file", ErrUnsupportedDocument)
+		}
+		return docType, nil
+	}
+
+	if !strings.HasPrefix(http.DetectContentType(data), "text/") {
+		return "", fmt.Errorf("%w: not a text file", ErrUnsupportedDocument)
+	}
+	return docType, nil
+}
+
+// ExtractDocument turns an uploaded file into an article ready for indexing; the caller sets its source
+// The article's URL is upload://<hash>/<filename> and its canonical URL is derived from the
+// content hash, so the same text uploaded twice is a duplicate whatever the file name.
+func (f *ArticleFetcher) ExtractDocument(filename string, data []byte) (*models.Article, error) {
+	docType, err := DocumentType(filename, data)
+	if err != nil {
+		return nil, err
+	}
+
+	var article *models.Article
+	switch docType {
+	case DocumentPDF:
+		article, err = extractPDF(data)
+	case DocumentHTML:
+		article, err = extractHTMLDocument(data)
+	default:
+		article, err = extractTextDocument(data, docType)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if strings.TrimSpace(article.Content) == "" {
+		return nil, ErrNoDocumentText
+	}
+	if article.Title == "" {
+		article.Title = strings.TrimSuffix(filepath.Base(filename), filepath.Ext(filename))
+	}
+
+	hash := ContentHash(article.Content)
+	article.ID = uuid.New().String()
+	article.URL = "upload://" + hash[:16] + "/" + url.PathEscape(filepath.Base(filename))
+	article.CanonicalURL = "upload://" + hash
+	article.ContentHash = hash
+	article.FetchedAt = time.Now()
+	article.ProcessedAt = time.Now()
+	article.Status = models.ArticleStatusFetched
+	article.Metadata.WordCount = len(strings.Fields(article.Content))
+	article.Metadata.ReadingTime = f.calculateReadingTime(article.Content)
+
+	return article, nil
+}
+
+// extractTextDocument decodes plain text or Markdown; Markdown documents are titled by their
+// first heading
+func extractTextDocument(data []byte, docType string) (*models.Article, error) {
+	text, err := decodeText(data)
+	if err != nil {
+		return nil, err
+	}
+	text = strings.TrimSpace(strings.ReplaceAll(text, "\r\n", "\n"))
+
+	article := &models.Article{Content: text}
+	if docType == DocumentMarkdown {
+		for _, line := range strings.Split(text, "\n") {
+			if heading, ok := strings.CutPrefix(line, "# "); ok {
+				article.Title = strings.TrimSpace(heading)
+				break
+			}
+		}
+	}
+
+	return article, nil
+}
+
+// extractHTMLDocument extracts an HTML file like a fetched page
+func extractHTMLDocument(data []byte) (*models.Article, error) {
+	text, err := decodeText(data)
+	if err != nil {
+		return nil, err
+	}
+
+	doc, err := html.Parse(strings.NewReader(text))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse document: %w", err)
+	}
+
+	// Metadata first: content extraction prunes the tree
+	meta := readPageMetadata(doc)
+	return &models.Article{
+		Title:       meta.title(),
+		Content:     extractContent(doc),
+		Author:      meta.author,
+		PublishedAt: meta.published,
+		Metadata: models.Metadata{
+			Language: meta.language,
+			Summary:  meta.description,
+		},
+	}, nil
+}
+
+// extractPDF reads the text layer of a PDF, line by line
+// Scanned PDFs without a text layer yield ErrNoDocumentText; encrypted PDFs are refused.
+func extractPDF(data []byte) (article *models.Article, err error) {
+	// The PDF reader panics on some malformed files
+	defer func() {
+		if r := recover(); r != nil {
+			article, err = nil, fmt.Errorf("failed to read PDF: %v", r)
+		}
+	}()
+
+	reader, err := pdf.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read PDF: %w", err)
+	}
+
+	pages := make([]string, 0, reader.NumPage())
+	for i := 1; i <= reader.NumPage(); i++ {
+		if text := pdfPageText(reader.Page(i).Content().Text); text != "" {
+			pages = append(pages, text)
+		}
+	}
+
+	article = &models.Article{Content: strings.Join(pages, "\n\n")}
+	if info := reader.Trailer().Key("Info"); !info.IsNull() {
+		article.Title = collapseSpace(info.Key("Title").Text())
+		article.Author = collapseSpace(info.Key("Author").Text())
+	}
+
+	return article, nil
+}
+
+// pdfPageText rebuilds a page's lines from its positioned glyphs
+// A change of baseline starts a new line and a gap wider than a third of the font size is a space.
+func pdfPageText(glyphs []pdf.Text) string {
+	var b strings.Builder
+	for i, glyph := range glyphs {
+		if i > 0 {
+			prev := glyphs[i-1]
+			size := math.Max(glyph.FontSize, 1)
+			switch {
+			case math.Abs(glyph.Y-prev.Y) > size/2:
+				b.WriteString("\n")
+			case glyph.X-(prev.X+prev.W) > size/3 && prev.S != " " && glyph.S != " ":
+				b.WriteString(" ")
+			}
+		}
+		b.WriteString(glyph.S)
+	}
+
+	lines := strings.Split(b.String(), "\n")
+	kept := lines[:0]
+	for _, line := range lines {
+		if line = strings.TrimSpace(line); line != "" {
+			kept = append(kept, line)
+		}
+	}
+	return strings.Join(kept, "\n")
+}
+
+// decodeText converts a text file to UTF-8, detecting the encoding from a BOM or, for HTML,
+// a <meta charset>; invalid UTF-8 without either is read as Windows-1252
+func decodeText(data []byte) (string, error) {
+	if utf8.Valid(data) {
+		return strings.TrimPrefix(string(data), "\uFEFF"), nil
+	}
+
+	// Byte order marks are kept by the decoders
+
+	encoding, _, _ := charset.DetermineEncoding(data, "")
+	decoded, err := io.ReadAll(encoding.NewDecoder().Reader(bytes.NewReader(data)))
+	if err != nil {
+		return "", fmt.Errorf("failed to decode document: %w", err)
+	}
+	return strings.TrimPrefix(string(decoded), "\uFEFF"), nil
+}
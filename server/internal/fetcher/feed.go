@@ -0,0 +1,291 @@
+package fetcher
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+	"time"
+
+	"golang.org/x/net/html/charset"
+)
+
+// ErrNotAFeed is returned when a document is neither RSS nor Atom
+var ErrNotAFeed = errors.New("not an RSS or Atom feed")
+
+// ParsedFeed is the channel title and entries of an RSS or Atom feed
+type ParsedFeed struct {
+	Title   string
+	Entries []FeedEntry
+}
+
+// FeedEntry is one item of a feed; URL is absolute
+type FeedEntry struct {
+	Title      string
+	URL        string
+	Published  time.Time
+	Categories []string
+}
+
+// FetchFeed downloads and parses an RSS or Atom feed, returning the feed's cache validators
+// Non-empty validators make the request conditional; an unchanged feed yields ErrNotModified.
+func (f *ArticleFetcher) FetchFeed(ctx context.Context, feedURL string, validators Validators) (*ParsedFeed, Validators, error) {
+	if err := f.guard.Check(ctx, feedURL); err != nil {
+		return nil, Validators{}, fmt.Errorf("invalid URL: %w", err)
+	}
+
+	request := f.feedClient.R().
+		SetContext(ctx).
+		SetHeader("User-Agent", f.userAgent).
+		SetHeader("Accept", "application/rss+xml, application/atom+xml, application/xml;q=0.9, text/xml;q=0.9, */*;q=0.5").
+		SetDoNotParseResponse(true)
+	if validators.ETag != "" {
+		request.SetHeader("If-None-Match", validators.ETag)
+	}
+	if validators.LastModified != "" {
+		request.SetHeader("If-Modified-Since", validators.LastModified)
+	}
+
+	resp, err := request.Get(feedURL)
+	if err != nil {
+		return nil, Validators{}, fmt.Errorf("failed to fetch feed: %w", err)
+	}
+	body := resp.RawBody()
+	defer body.Close()
+
+	if resp.StatusCode() == 304 {
+		return nil, validators, ErrNotModified
+	}
+	if resp.StatusCode() != 200 {
+		return nil, Validators{}, fmt.Errorf("failed to fetch feed: status %d", resp.StatusCode())
+	}
+
+	raw, err := io.ReadAll(io.LimitReader(body, f.maxBytes+1))
+	if err != nil {
+		return nil, Validators{}, fmt.Errorf("failed to read feed: %w", err)
+	}
+	if int64(len(raw)) > f.maxBytes {
+		return nil, Validators{}, fmt.Errorf("feed exceeds %d bytes", f.maxBytes)
+	}
+
+	feed, err := ParseFeed(bytes.NewReader(raw), feedURL)
+	if err != nil {
+		return nil, Validators{}, err
+	}
+
+	slog.Debug("Feed fetched", "url", feedURL, "title", feed.Title, "entries", len(feed.Entries))
+
+	current := Validators{
+		ETag:         resp.Header().Get("ETag"),
+		LastModified: resp.Header().Get("Last-Modified"),
+	}
+	return feed, current, nil
+}
+
+// ParseFeed reads an RSS 2.0, RSS 1.0 (RDF) or Atom document
+// Entry links are resolved against feedURL; entries without a link are skipped.
+func ParseFeed(r io.Reader, feedURL string) (*ParsedFeed, error) {
+	decoder := xml.NewDecoder(r)
+	decoder.CharsetReader = charset.NewReaderLabel
+	// Feeds in the wild use HTML entities and bare ampersands. HTML auto-closing is not enabled:
+	// it would treat RSS <link> elements as empty.
+	decoder.Strict = false
+	decoder.Entity = xml.HTMLEntity
+
+	for {
+		token, err := decoder.Token()
+		if err != nil {
+			if err == io.EOF {
+				return nil, ErrNotAFeed
+			}
+			return nil, fmt.Errorf("failed to parse feed: %w", err)
+		}
+
+		start, ok := token.(xml.StartElement)
+		if !ok {
+			continue
+		}
+
+		switch start.Name.Local {
+		case "rss", "RDF":
+			var doc rssDocument
+			if err := decoder.DecodeElement(&doc, &start); err != nil {
+				return nil, fmt.Errorf("failed to parse feed: %w", err)
+			}
+			return doc.parsed(feedURL), nil
+		case "feed":
+			var doc atomFeed
+			if err := decoder.DecodeElement(&doc, &start); err != nil {
+				return nil, fmt.Errorf("failed to parse feed: %w", err)
+			}
+			return doc.parsed(feedURL), nil
+		default:
+			return nil, ErrNotAFeed
+		}
+	}
+}
+
+// rssDocument is an RSS 2.0 <rss> or RSS 1.0 <rdf:RDF> root
+type rssDocument struct {
+	Channel struct {
+		Title string    `xml:"title"`
+		Items []rssItem `xml:"item"`
+	} `xml:"channel"`
+	Items []rssItem `xml:"item"` // RSS 1.0 items are siblings of the channel
+}
+
+type rssItem struct {
+	Title      string    `xml:"title"`
+	Links      []rssLink `xml:"link"`
+	GUID       rssGUID   `xml:"guid"`
+	PubDate    string    `xml:"pubDate"`
+	Date       string    `xml:"http://purl.org/dc/elements/1.1/ date"`
+	Categories []string  `xml:"category"`
+}
+
+// rssLink holds <link>url</link> and, when a feed mixes in Atom, <atom:link href="url"/>
+type rssLink struct {
+	Value string `xml:",chardata"`
+	Href  string `xml:"href,attr"`
+	Rel   string `xml:"rel,attr"`
+}
+
+type rssGUID struct {
+	Value       string `xml:",chardata"`
+	IsPermaLink string `xml:"isPermaLink,attr"`
+}
+
+func (d *rssDocument) parsed(feedURL string) *ParsedFeed {
+	feed := &ParsedFeed{Title: collapseSpace(d.Channel.Title)}
+
+	for _, item := range append(d.Channel.Items, d.Items...) {
+		link := ""
+		for _, l := range item.Links {
+			if value := strings.TrimSpace(l.Value); value != "" {
+				link = value
+				break
+			}
+			if l.Href != "" && (l.Rel == "" || l.Rel == "alternate") {
+				link = l.Href
+				break
+			}
+		}
+		// A GUID is a permalink unless marked otherwise
+		if guid := strings.TrimSpace(item.GUID.Value); link == "" && item.GUID.IsPermaLink != "false" &&
+			(strings.HasPrefix(guid, "http://") || strings.HasPrefix(guid, "https://")) {
+			link = guid
+		}
+
+		categories := make([]string, 0, len(item.Categories))
+		for _, category := range item.Categories {
+			if category = collapseSpace(category); category != "" {
+				categories = append(categories, category)
+			}
+		}
+
+		feed.addEntry(feedURL, FeedEntry{
+			Title:      collapseSpace(item.Title),
+			URL:        link,
+			Published:  parseFeedDate(firstNonEmpty(item.PubDate, item.Date)),
+			Categories: categories,
+		})
+	}
+
+	return feed
+}
+
+// atomFeed is an Atom <feed> root
+type atomFeed struct {
+	Title   string      `xml:"title"`
+	Entries []atomEntry `xml:"entry"`
+}
+
+type atomEntry struct {
+	Title      string         `xml:"title"`
+	Links      []atomLink     `xml:"link"`
+	Published  string         `xml:"published"`
+	Updated    string         `xml:"updated"`
+	Categories []atomCategory `xml:"category"`
+}
+
+type atomLink struct {
+	Href string `xml:"href,attr"`
+	Rel  string `xml:"rel,attr"`
+}
+
+type atomCategory struct {
+	Term  string `xml:"term,attr"`
+	Label string `xml:"label,attr"`
+}
+
+func (d *atomFeed) parsed(feedURL string) *ParsedFeed {
+	feed := &ParsedFeed{Title: collapseSpace(d.Title)}
+
+	for _, entry := range d.Entries {
+		// rel defaults to alternate, the entry's own page
+		link := ""
+		for _, l := range entry.Links {
+			if l.Rel == "" || l.Rel == "alternate" {
+				link = l.Href
+				break
+			}
+		}
+
+		categories := make([]string, 0, len(entry.Categories))
+		for _, category := range entry.Categories {
+			if name := collapseSpace(firstNonEmpty(category.Label, category.Term)); name != "" {
+				categories = append(categories, name)
+			}
+		}
+
+		feed.addEntry(feedURL, FeedEntry{
+			Title:      collapseSpace(entry.Title),
+			URL:        link,
+			Published:  parseFeedDate(firstNonEmpty(entry.Published, entry.Updated)),
+			Categories: categories,
+		})
+	}
+
+	return feed
+}
+
+// addEntry resolves the entry's link and keeps entries with an http(s) URL
+func (f *ParsedFeed) addEntry(feedURL string, entry FeedEntry) {
+	entry.URL = resolveReference(feedURL, strings.TrimSpace(entry.URL))
+	if !strings.HasPrefix(entry.URL, "http://") && !strings.HasPrefix(entry.URL, "https://") {
+		return
+	}
+	f.Entries = append(f.Entries, entry)
+}
+
+// feedDateLayouts are the date formats seen in RSS (RFC 822 and variants) and Atom (RFC 3339)
+var feedDateLayouts = []string{
+	time.RFC1123Z,
+	time.RFC1123,
+	"Mon, 2 Jan 2006 15:04:05 -0700",
+	"Mon, 2 Jan 2006 15:04:05 MST",
+	"Mon, 2 Jan 2006 15:04 -0700",
+	"2 Jan 2006 15:04:05 -0700",
+	time.RFC3339,
+	"2006-01-02T15:04:05",
+	"2006-01-02",
+}
+
+// parseFeedDate parses an entry date, returning the zero time when the format is unknown
+func parseFeedDate(value string) time.Time {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return time.Time{}
+	}
+
+	for _, layout := range feedDateLayouts {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t
+		}
+	}
+	return time.Time{}
+}
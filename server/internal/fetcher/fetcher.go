@@ -1,12 +1,13 @@
 package fetcher
 
 import (
+	"article-chat-system/server/internal/config"
 	"article-chat-system/server/internal/models"
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
 	"net/url"
-	"os"
 	"strings"
 	"time"
 
@@ -14,25 +15,68 @@ import (
 	"github.com/google/uuid"
 )
 
+// Extractor turns an article URL into its title, content and metadata
+// Implementations fill what they can find; ArticleFetcher sets IDs, timestamps and statistics.
+type Extractor interface {
+	Name() string
+	Extract(ctx context.Context, articleURL string) (*models.Article, error)
+}
+
+// ErrNotModified is returned by conditional fetches when the page did not change
+var ErrNotModified = errors.New("article not modified")
+
+// Validators are the HTTP cache validators of a fetched page
+type Validators struct {
+	ETag         string
+	LastModified string
+}
+
+// ConditionalExtractor is implemented by extractors that can skip unchanged pages
+// ExtractIfModified sends the validators with the request and returns ErrNotModified
+// when the server confirms the page did not change.
+type ConditionalExtractor interface {
+	ExtractIfModified(ctx context.Context, articleURL string, validators Validators) (*models.Article, error)
+}
+
 type ArticleFetcher struct {
-	client *resty.Client
+	extractor  Extractor
+	guard      *URLGuard
+	feedClient *resty.Client
+	userAgent  string
+	maxBytes   int64
 }
 
-type JinaResponse struct {
-	Code int    `json:"code"`
-	Msg  string `json:"msg"`
-	Data struct {
-		Title       string `json:"title"`
-		Description string `json:"description"`
-		URL         string `json:"url"`
-		Content     string `json:"content"`
-		Usage       struct {
-			Tokens int `json:"tokens"`
-		} `json:"usage"`
-	} `json:"data"`
+// NewArticleFetcher creates a fetcher using the extractor selected in cfg
+func NewArticleFetcher(cfg config.FetcherConfig) *ArticleFetcher {
+	guard := NewURLGuard(cfg)
+
+	var extractor Extractor
+	switch cfg.Extractor {
+	case config.ExtractorNative:
+		extractor = NewNativeExtractor(cfg, guard)
+	case config.ExtractorNativeWithFallback:
+		extractor = NewFallbackExtractor(NewNativeExtractor(cfg, guard), NewJinaExtractor(cfg.JinaAPIKey))
+	default:
+		extractor = NewJinaExtractor(cfg.JinaAPIKey)
+	}
+
+	// Feeds are always downloaded directly, whichever extractor reads the articles
+	feedClient := newRetryingClient()
+	feedClient.SetTransport(guard.Transport())
+	feedClient.SetRedirectPolicy(resty.RedirectPolicyFunc(guard.CheckRedirect))
+
+	return &ArticleFetcher{
+		extractor:  extractor,
+		guard:      guard,
+		feedClient: feedClient,
+		userAgent:  cfg.UserAgent,
+		maxBytes:   cfg.MaxPageBytes,
+	}
 }
 
-func NewArticleFetcher() *ArticleFetcher {
+// newRetryingClient creates an HTTP client that retries server errors and timeouts
+// Blocked URLs are never retried.
+func newRetryingClient() *resty.Client {
 	client := resty.New()
 	client.SetTimeout(30 * time.Second)
 	client.SetRetryCount(3)
@@ -42,103 +86,99 @@ func NewArticleFetcher() *ArticleFetcher {
 	// Retry on server errors and timeouts
 	client.AddRetryCondition(func(r *resty.Response, err error) bool {
 		if err != nil {
-			return true
+			return !errors.Is(err, ErrBlockedURL)
 		}
 		return r.StatusCode() >= 500
 	})
 
-	return &ArticleFetcher{
-		client: client,
-	}
+	return client
+}
+
+// CheckURL reports whether the backend may fetch articleURL
+// The error wraps ErrBlockedURL when the URL or an address it resolves to is not allowed.
+func (f *ArticleFetcher) CheckURL(ctx context.Context, articleURL string) error {
+	return f.guard.Check(ctx, articleURL)
 }
 
 func (f *ArticleFetcher) FetchArticle(ctx context.Context, articleURL string) (*models.Article, error) {
-	if err := f.validateURL(articleURL); err != nil {
+	if err := f.guard.Check(ctx, articleURL); err != nil {
 		return nil, fmt.Errorf("invalid URL: %w", err)
 	}
 
-	slog.Info("Fetching article", "url", articleURL)
-
-	// Use Jina Reader API for clean article extraction
-	jinaURL := "https://r.jina.ai/" + articleURL
-
-	// Get JINA API key from environment
-	jinaAPIKey := os.Getenv("JINA_API_KEY")
-
-	request := f.client.R().
-		SetContext(ctx).
-		SetHeader("Accept", "application/json").
-		SetResult(&JinaResponse{})
-
-	// Add Authorization header if API key is available
-	if jinaAPIKey != "" {
-		request = request.SetHeader("Authorization", "Bearer "+jinaAPIKey)
-	}
-
-	resp, err := request.Get(jinaURL)
+	slog.Info("Fetching article", "url", articleURL, "extractor", f.extractor.Name())
 
+	article, err := f.extractor.Extract(ctx, articleURL)
 	if err != nil {
-		slog.Error("Failed to fetch article", "url", articleURL, "error", err)
-		return nil, fmt.Errorf("failed to fetch article: %w", err)
-	}
-
-	if resp.StatusCode() != 200 {
-		slog.Error("Jina Reader API error", "status", resp.StatusCode(), "url", articleURL)
-		return nil, fmt.Errorf("jina reader api error: status %d", resp.StatusCode())
-	}
-
-	jinaResp := resp.Result().(*JinaResponse)
-	if jinaResp.Code != 200 {
-		return nil, fmt.Errorf("jina reader failed: %s", jinaResp.Msg)
-	}
-
-	// Create article model
-	article := &models.Article{
-		ID:          uuid.New().String(),
-		URL:         articleURL,
-		Title:       jinaResp.Data.Title,
-		Content:     jinaResp.Data.Content,
-		Source:      f.extractDomain(articleURL),
-		FetchedAt:   time.Now(),
-		ProcessedAt: time.Now(),
-		Status:      "fetched",
-		Metadata: models.Metadata{
-			Domain:      f.extractDomain(articleURL),
-			WordCount:   len(strings.Fields(jinaResp.Data.Content)),
-			ReadingTime: f.calculateReadingTime(jinaResp.Data.Content),
-		},
-	}
-
-	// Calculate reading time (average 200 words per minute)
-	article.Metadata.ReadingTime = article.Metadata.WordCount / 200
-	if article.Metadata.ReadingTime < 1 {
-		article.Metadata.ReadingTime = 1
+		slog.Error("Failed to fetch article", "url", articleURL, "extractor", f.extractor.Name(), "error", err)
+		return nil, err
 	}
+	f.complete(article, articleURL)
 
 	slog.Info("Article fetched successfully",
 		"url", articleURL,
 		"title", article.Title,
+		"author", article.Author,
 		"word_count", article.Metadata.WordCount,
 		"reading_time", article.Metadata.ReadingTime)
 
 	return article, nil
 }
 
-func (f *ArticleFetcher) validateURL(rawURL string) error {
-	parsedURL, err := url.Parse(rawURL)
-	if err != nil {
-		return fmt.Errorf("invalid URL format: %w", err)
+// RefreshArticle fetches a stored article again
+// When the extractor supports conditional requests the article's validators are sent, and
+// ErrNotModified is returned if the page did not change. The result has a new ID; callers
+// keep the stored one.
+func (f *ArticleFetcher) RefreshArticle(ctx context.Context, current *models.Article) (*models.Article, error) {
+	if err := f.guard.Check(ctx, current.URL); err != nil {
+		return nil, fmt.Errorf("invalid URL: %w", err)
 	}
 
-	if parsedURL.Scheme != "http" && parsedURL.Scheme != "https" {
-		return fmt.Errorf("URL must use http or https scheme")
+	var article *models.Article
+	var err error
+	if conditional, ok := f.extractor.(ConditionalExtractor); ok {
+		validators := Validators{ETag: current.ETag, LastModified: current.LastModified}
+		article, err = conditional.ExtractIfModified(ctx, current.URL, validators)
+	} else {
+		article, err = f.extractor.Extract(ctx, current.URL)
+	}
+	if err != nil {
+		return nil, err
 	}
+	f.complete(article, current.URL)
+
+	return article, nil
+}
+
+// complete fills the fields every extractor leaves to the fetcher: IDs, timestamps and statistics
+func (f *ArticleFetcher) complete(article *models.Article, articleURL string) {
+	article.ID = uuid.New().String()
+	article.URL = articleURL
+	article.CanonicalURL = f.canonicalURL(articleURL, article.CanonicalURL)
+	article.ContentHash = ContentHash(article.Content)
+	article.Source = f.extractDomain(articleURL)
+	article.FetchedAt = time.Now()
+	article.ProcessedAt = time.Now()
+	article.Status = models.ArticleStatusFetched
+	article.Metadata.Domain = f.extractDomain(articleURL)
+	article.Metadata.WordCount = len(strings.Fields(article.Content))
+	article.Metadata.ReadingTime = f.calculateReadingTime(article.Content)
+}
 
-	if parsedURL.Host == "" {
-		return fmt.Errorf("URL must have a host")
+// canonicalURL normalizes the submitted URL, preferring the canonical URL the page declares
+// when it is on the same site
+func (f *ArticleFetcher) canonicalURL(articleURL, declared string) string {
+	canonical, err := CanonicalURL(articleURL)
+	if err != nil {
+		return articleURL
+	}
+	if declared == "" {
+		return canonical
 	}
 
-	return nil
+	if declaredCanonical, err := CanonicalURL(declared); err == nil && sameSite(declaredCanonical, canonical) {
+		return declaredCanonical
+	}
+	return canonical
 }
 
 func (f *ArticleFetcher) extractDomain(rawURL string) string {
@@ -157,3 +197,55 @@ func (f *ArticleFetcher) calculateReadingTime(content string) int {
 	}
 	return readingTime
 }
+
+// FallbackExtractor uses a second extractor when the first one fails
+type FallbackExtractor struct {
+	primary  Extractor
+	fallback Extractor
+}
+
+// NewFallbackExtractor creates an extractor trying primary, then fallback
+func NewFallbackExtractor(primary, fallback Extractor) *FallbackExtractor {
+	return &FallbackExtractor{
+		primary:  primary,
+		fallback: fallback,
+	}
+}
+
+func (e *FallbackExtractor) Name() string {
+	return e.primary.Name() + "+" + e.fallback.Name()
+}
+
+func (e *FallbackExtractor) Extract(ctx context.Context, articleURL string) (*models.Article, error) {
+	article, err := e.primary.Extract(ctx, articleURL)
+	if err == nil {
+		return article, nil
+	}
+	return e.useFallback(ctx, articleURL, err)
+}
+
+// ExtractIfModified makes a conditional request when the primary extractor supports it
+// The fallback is only used when the primary fails, not when the page is unchanged.
+func (e *FallbackExtractor) ExtractIfModified(ctx context.Context, articleURL string, validators Validators) (*models.Article, error) {
+	conditional, ok := e.primary.(ConditionalExtractor)
+	if !ok {
+		return e.Extract(ctx, articleURL)
+	}
+
+	article, err := conditional.ExtractIfModified(ctx, articleURL, validators)
+	if err == nil || errors.Is(err, ErrNotModified) {
+		return article, err
+	}
+	return e.useFallback(ctx, articleURL, err)
+}
+
+func (e *FallbackExtractor) useFallback(ctx context.Context, articleURL string, primaryErr error) (*models.Article, error) {
+	slog.Warn("Article extraction failed, trying fallback",
+		"url", articleURL, "extractor", e.primary.Name(), "fallback", e.fallback.Name(), "error", primaryErr)
+
+	article, fallbackErr := e.fallback.Extract(ctx, articleURL)
+	if fallbackErr != nil {
+		return nil, fmt.Errorf("%s: %v; %s: %w", e.primary.Name(), primaryErr, e.fallback.Name(), fallbackErr)
+	}
+	return article, nil
+}
@@ -0,0 +1,231 @@
+package fetcher
+
+import (
+	"article-chat-system/server/internal/config"
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"regexp"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// maxRedirects is the number of redirects followed before a fetch is abandoned
+const maxRedirects = 5
+
+// ErrBlockedURL is returned for URLs the backend must not fetch
+var ErrBlockedURL = errors.New("URL is not allowed")
+
+// blockedPrefixes are the special-purpose ranges, besides loopback, private, link-local
+// and multicast addresses, that never host public articles
+var blockedPrefixes = []netip.Prefix{
+	netip.MustParsePrefix("0.0.0.0/8"),       // "This" network
+	netip.MustParsePrefix("100.64.0.0/10"),   // Carrier-grade NAT
+	netip.MustParsePrefix("192.0.0.0/24"),    // IETF protocol assignments
+	netip.MustParsePrefix("192.0.2.0/24"),    // TEST-NET-1
+	netip.MustParsePrefix("198.18.0.0/15"),   // Benchmarking
+	netip.MustParsePrefix("198.51.100.0/24"), // TEST-NET-2
+	netip.MustParsePrefix("203.0.113.0/24"),  // TEST-NET-3
+	netip.MustParsePrefix("240.0.0.0/4"),     // Reserved and broadcast
+	netip.MustParsePrefix("64:ff9b::/96"),    // NAT64, embeds IPv4 addresses
+	netip.MustParsePrefix("64:ff9b:1::/48"),  // Local-use NAT64
+	netip.MustParsePrefix("2001:db8::/32"),   // Documentation
+}
+
+// numericHost matches hosts written only with decimal, octal or hex numbers, such as
+// 2130706433, 0177.0.0.1 or 0x7f.1. Some resolvers read these as IPv4 addresses, and no
+// real domain looks like one, so anything that isn't a plain dotted quad is refused.
+var numericHost = regexp.MustCompile(`^(0x[0-9a-f]*|[0-9]+)(\.(0x[0-9a-f]*|[0-9]+))*$`)
+
+// resolver looks up the addresses of a host; *net.Resolver implements it
+type resolver interface {
+	LookupNetIP(ctx context.Context, network, host string) ([]netip.Addr, error)
+}
+
+// URLGuard protects article fetching against server-side request forgery
+// URLs must use http or https on an allowed port and pass the domain lists. Every address a
+// host resolves to must be public, and the guarded transport checks the address actually
+// dialed again, so redirects and DNS rebinding cannot reach internal services either.
+type URLGuard struct {
+	allowedPorts   map[string]bool
+	allowedDomains []string
+	deniedDomains  []string
+	allowPrivate   bool
+	resolver       resolver
+}
+
+// NewURLGuard creates a guard from the fetcher's port, domain and network settings
+func NewURLGuard(cfg config.FetcherConfig) *URLGuard {
+	ports := map[string]bool{}
+	for _, port := range splitList(cfg.AllowedPorts) {
+		ports[port] = true
+	}
+
+	return &URLGuard{
+		allowedPorts:   ports,
+		allowedDomains: splitList(cfg.AllowedDomains),
+		deniedDomains:  splitList(cfg.DeniedDomains),
+		allowPrivate:   cfg.AllowPrivateNetworks,
+		resolver:       net.DefaultResolver,
+	}
+}
+
+// Check validates a URL and every address its host resolves to
+func (g *URLGuard) Check(ctx context.Context, rawURL string) error {
+	parsedURL, err := url.Parse(rawURL)
+	if err != nil {
+		return fmt.Errorf("invalid URL format: %w", err)
+	}
+	if err := g.checkURL(parsedURL); err != nil {
+		return err
+	}
+
+	host := parsedURL.Hostname()
+	if addr, err := netip.ParseAddr(host); err == nil {
+		return g.checkAddr(addr)
+	}
+
+	addrs, err := g.resolver.LookupNetIP(ctx, "ip", host)
+	if err != nil {
+		return fmt.Errorf("failed to resolve %s: %w", host, err)
+	}
+	for _, addr := range addrs {
+		if err := g.checkAddr(addr); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// checkURL validates scheme, credentials, port and domain without resolving the host
+func (g *URLGuard) checkURL(u *url.URL) error {
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return fmt.Errorf("%w: scheme must be http or https", ErrBlockedURL)
+	}
+	if u.Hostname() == "" {
+		return fmt.Errorf("%w: URL must have a host", ErrBlockedURL)
+	}
+	if u.User != nil {
+		return fmt.Errorf("%w: credentials in URLs are not supported", ErrBlockedURL)
+	}
+
+	port := u.Port()
+	if port == "" {
+		port = "80"
+		if u.Scheme == "https" {
+			port = "443"
+		}
+	}
+	if !g.allowedPorts[port] {
+		return fmt.Errorf("%w: port %s is not allowed", ErrBlockedURL, port)
+	}
+
+	host := strings.TrimSuffix(strings.ToLower(u.Hostname()), ".")
+	if _, err := netip.ParseAddr(host); err != nil && numericHost.MatchString(host) {
+		return fmt.Errorf("%w: numeric host %s must be a dotted IPv4 address", ErrBlockedURL, host)
+	}
+	if matchesDomain(host, g.deniedDomains) {
+		return fmt.Errorf("%w: domain %s is denied", ErrBlockedURL, host)
+	}
+	if len(g.allowedDomains) > 0 && !matchesDomain(host, g.allowedDomains) {
+		return fmt.Errorf("%w: domain %s is not in the allow list", ErrBlockedURL, host)
+	}
+
+	return nil
+}
+
+// checkAddr rejects addresses outside the public internet
+func (g *URLGuard) checkAddr(addr netip.Addr) error {
+	if g.allowPrivate {
+		return nil
+	}
+
+	// IPv4-mapped IPv6 addresses reach the IPv4 host
+	addr = addr.Unmap()
+	if addr.IsLoopback() || addr.IsPrivate() || addr.IsLinkLocalUnicast() || addr.IsLinkLocalMulticast() ||
+		addr.IsInterfaceLocalMulticast() || addr.IsMulticast() || addr.IsUnspecified() {
+		return fmt.Errorf("%w: %s is not a public address", ErrBlockedURL, addr)
+	}
+	for _, prefix := range blockedPrefixes {
+		if prefix.Contains(addr) {
+			return fmt.Errorf("%w: %s is not a public address", ErrBlockedURL, addr)
+		}
+	}
+
+	return nil
+}
+
+// Transport returns an HTTP transport that checks every address it connects to
+// Proxies are disabled: the check must see the article host, not the proxy.
+func (g *URLGuard) Transport() *http.Transport {
+	dialer := &net.Dialer{
+		Timeout:   10 * time.Second,
+		KeepAlive: 30 * time.Second,
+		Control: func(network, address string, _ syscall.RawConn) error {
+			host, port, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			if !g.allowedPorts[port] {
+				return fmt.Errorf("%w: port %s is not allowed", ErrBlockedURL, port)
+			}
+			addr, err := netip.ParseAddr(host)
+			if err != nil {
+				return err
+			}
+			return g.checkAddr(addr)
+		},
+	}
+
+	return &http.Transport{
+		Proxy:                 nil,
+		DialContext:           dialer.DialContext,
+		ForceAttemptHTTP2:     true,
+		MaxIdleConns:          20,
+		IdleConnTimeout:       90 * time.Second,
+		TLSHandshakeTimeout:   10 * time.Second,
+		ResponseHeaderTimeout: 20 * time.Second,
+	}
+}
+
+// CheckRedirect validates each redirect target before it is followed
+// Hostnames are resolved when the transport dials them; address literals are checked here.
+func (g *URLGuard) CheckRedirect(req *http.Request, via []*http.Request) error {
+	if len(via) >= maxRedirects {
+		return fmt.Errorf("stopped after %d redirects", maxRedirects)
+	}
+	if err := g.checkURL(req.URL); err != nil {
+		return err
+	}
+	if addr, err := netip.ParseAddr(req.URL.Hostname()); err == nil {
+		return g.checkAddr(addr)
+	}
+	return nil
+}
+
+// matchesDomain reports whether host is one of domains or a subdomain of one
+func matchesDomain(host string, domains []string) bool {
+	for _, domain := range domains {
+		if host == domain || strings.HasSuffix(host, "."+domain) {
+			return true
+		}
+	}
+	return false
+}
+
+// splitList splits a comma separated setting into lowercase, trimmed entries
+func splitList(value string) []string {
+	items := []string{}
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSuffix(strings.ToLower(strings.TrimSpace(item)), "."); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
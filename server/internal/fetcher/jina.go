@@ -0,0 +1,87 @@
+package fetcher
+
+import (
+	"article-chat-system/server/internal/models"
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/go-resty/resty/v2"
+)
+
+type JinaResponse struct {
+	Code int    `json:"code"`
+	Msg  string `json:"msg"`
+	Data struct {
+		Title         string `json:"title"`
+		Description   string `json:"description"`
+		URL           string `json:"url"`
+		Content       string `json:"content"`
+		PublishedTime string `json:"publishedTime"`
+		Usage         struct {
+			Tokens int `json:"tokens"`
+		} `json:"usage"`
+	} `json:"data"`
+}
+
+// JinaExtractor extracts articles with the Jina Reader API
+type JinaExtractor struct {
+	client *resty.Client
+	apiKey string
+}
+
+// NewJinaExtractor creates a Jina Reader extractor; apiKey is optional
+func NewJinaExtractor(apiKey string) *JinaExtractor {
+	return &JinaExtractor{
+		client: newRetryingClient(),
+		apiKey: apiKey,
+	}
+}
+
+func (e *JinaExtractor) Name() string {
+	return "jina"
+}
+
+func (e *JinaExtractor) Extract(ctx context.Context, articleURL string) (*models.Article, error) {
+	// Use Jina Reader API for clean article extraction
+	jinaURL := "https://r.jina.ai/" + articleURL
+
+	request := e.client.R().
+		SetContext(ctx).
+		SetHeader("Accept", "application/json").
+		SetResult(&JinaResponse{})
+
+	// Add Authorization header if API key is available
+	if e.apiKey != "" {
+		request = request.SetHeader("Authorization", "Bearer "+e.apiKey)
+	}
+
+	resp, err := request.Get(jinaURL)
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch article: %w", err)
+	}
+
+	if resp.StatusCode() != 200 {
+		slog.Error("Jina Reader API error", "status", resp.StatusCode(), "url", articleURL)
+		return nil, fmt.Errorf("jina reader api error: status %d", resp.StatusCode())
+	}
+
+	jinaResp := resp.Result().(*JinaResponse)
+	if jinaResp.Code != 200 {
+		return nil, fmt.Errorf("jina reader failed: %s", jinaResp.Msg)
+	}
+
+	article := &models.Article{
+		Title:   jinaResp.Data.Title,
+		Content: jinaResp.Data.Content,
+		Metadata: models.Metadata{
+			Summary: jinaResp.Data.Description,
+		},
+	}
+	if published, ok := parsePublishedDate(jinaResp.Data.PublishedTime); ok {
+		article.PublishedAt = published
+	}
+
+	return article, nil
+}
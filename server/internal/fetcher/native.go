@@ -0,0 +1,410 @@
+package fetcher
+
+import (
+	"article-chat-system/server/internal/config"
+	"article-chat-system/server/internal/models"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/go-resty/resty/v2"
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+	"golang.org/x/net/html/charset"
+)
+
+// minArticleLength is the shortest extracted text, in characters, accepted as an article
+// Shorter results usually mean a page rendered by JavaScript or a paywall.
+const minArticleLength = 200
+
+// ErrNoArticleContent is returned when a page has no recognizable article text
+var ErrNoArticleContent = errors.New("no article content found")
+
+// NativeExtractor fetches pages directly and extracts the main content without external services
+// Boilerplate is removed readability-style; title, author and published date come from
+// JSON-LD, OpenGraph and meta tags.
+type NativeExtractor struct {
+	client    *resty.Client
+	userAgent string
+	maxBytes  int64
+}
+
+// NewNativeExtractor creates a native extractor
+// Pages are downloaded through the guard's transport, which re-checks redirects and dialed addresses.
+func NewNativeExtractor(cfg config.FetcherConfig, guard *URLGuard) *NativeExtractor {
+	client := newRetryingClient()
+	client.SetTransport(guard.Transport())
+	client.SetRedirectPolicy(resty.RedirectPolicyFunc(guard.CheckRedirect))
+
+	return &NativeExtractor{
+		client:    client,
+		userAgent: cfg.UserAgent,
+		maxBytes:  cfg.MaxPageBytes,
+	}
+}
+
+func (e *NativeExtractor) Name() string {
+	return "native"
+}
+
+func (e *NativeExtractor) Extract(ctx context.Context, articleURL string) (*models.Article, error) {
+	return e.ExtractIfModified(ctx, articleURL, Validators{})
+}
+
+func (e *NativeExtractor) ExtractIfModified(ctx context.Context, articleURL string, validators Validators) (*models.Article, error) {
+	page, validators, err := e.fetchPage(ctx, articleURL, validators)
+	if err != nil {
+		return nil, err
+	}
+
+	doc, err := html.Parse(strings.NewReader(page))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse page: %w", err)
+	}
+
+	// Metadata first: content extraction prunes the tree
+	meta := readPageMetadata(doc)
+	content := extractContent(doc)
+	if utf8.RuneCountInString(content) < minArticleLength {
+		return nil, ErrNoArticleContent
+	}
+
+	article := &models.Article{
+		Title:        meta.title(),
+		Content:      content,
+		RawHTML:      page,
+		Author:       meta.author,
+		PublishedAt:  meta.published,
+		CanonicalURL: resolveReference(articleURL, meta.canonical),
+		ETag:         validators.ETag,
+		LastModified: validators.LastModified,
+		Metadata: models.Metadata{
+			Language: meta.language,
+			Summary:  meta.description,
+		},
+	}
+
+	return article, nil
+}
+
+// fetchPage downloads an HTML page and decodes it to UTF-8, returning the page's cache validators
+// Non-empty validators make the request conditional; an unchanged page yields ErrNotModified.
+func (e *NativeExtractor) fetchPage(ctx context.Context, pageURL string, validators Validators) (string, Validators, error) {
+	request := e.client.R().
+		SetContext(ctx).
+		SetHeader("User-Agent", e.userAgent).
+		SetHeader("Accept", "text/html,application/xhtml+xml;q=0.9,*/*;q=0.5").
+		SetDoNotParseResponse(true)
+	if validators.ETag != "" {
+		request.SetHeader("If-None-Match", validators.ETag)
+	}
+	if validators.LastModified != "" {
+		request.SetHeader("If-Modified-Since", validators.LastModified)
+	}
+
+	resp, err := request.Get(pageURL)
+	if err != nil {
+		return "", Validators{}, fmt.Errorf("failed to fetch article: %w", err)
+	}
+	body := resp.RawBody()
+	defer body.Close()
+
+	if resp.StatusCode() == 304 {
+		return "", validators, ErrNotModified
+	}
+	if resp.StatusCode() != 200 {
+		return "", Validators{}, fmt.Errorf("failed to fetch article: status %d", resp.StatusCode())
+	}
+
+	contentType := resp.Header().Get("Content-Type")
+	if contentType != "" && !strings.Contains(contentType, "html") {
+		return "", Validators{}, fmt.Errorf("unsupported content type %q", contentType)
+	}
+
+	raw, err := io.ReadAll(io.LimitReader(body, e.maxBytes+1))
+	if err != nil {
+		return "", Validators{}, fmt.Errorf("failed to read page: %w", err)
+	}
+	if int64(len(raw)) > e.maxBytes {
+		return "", Validators{}, fmt.Errorf("page exceeds %d bytes", e.maxBytes)
+	}
+
+	// Honour the charset from the header or <meta charset>, defaulting to UTF-8
+	reader, err := charset.NewReader(bytes.NewReader(raw), contentType)
+	if err != nil {
+		return "", Validators{}, fmt.Errorf("failed to decode page: %w", err)
+	}
+	decoded, err := io.ReadAll(reader)
+	if err != nil {
+		return "", Validators{}, fmt.Errorf("failed to decode page: %w", err)
+	}
+
+	current := Validators{
+		ETag:         resp.Header().Get("ETag"),
+		LastModified: resp.Header().Get("Last-Modified"),
+	}
+	return string(decoded), current, nil
+}
+
+// pageMetadata holds the article details found in a page's head
+type pageMetadata struct {
+	jsonLDTitle string
+	ogTitle     string
+	docTitle    string
+	siteName    string
+	author      string
+	description string
+	language    string
+	canonical   string // <link rel="canonical"> href, possibly relative
+	published   time.Time
+}
+
+// title picks the most specific title, stripping a " | Site" suffix from the document title
+func (m *pageMetadata) title() string {
+	switch {
+	case m.jsonLDTitle != "":
+		return m.jsonLDTitle
+	case m.ogTitle != "":
+		return m.ogTitle
+	}
+
+	title := m.docTitle
+	if m.siteName != "" {
+		for _, separator := range []string{" | ", " - ", " – ", " — ", " :: "} {
+			title = strings.TrimSuffix(title, separator+m.siteName)
+		}
+	}
+	return title
+}
+
+// Meta tag names carrying the author and published date, most specific first
+var (
+	authorMetaNames    = []string{"author", "article:author", "parsely-author", "sailthru.author", "dc.creator"}
+	publishedMetaNames = []string{"article:published_time", "og:published_time", "datepublished", "parsely-pub-date",
+		"sailthru.date", "pubdate", "publishdate", "dc.date.issued", "dc.date", "date"}
+)
+
+// readPageMetadata collects title, author, date, description and language from JSON-LD and meta tags
+func readPageMetadata(doc *html.Node) *pageMetadata {
+	meta := &pageMetadata{}
+	tags := map[string]string{}
+
+	var walk func(*html.Node)
+	walk = func(n *html.Node) {
+		if n.Type == html.ElementNode {
+			switch n.DataAtom {
+			case atom.Html:
+				meta.language = attr(n, "lang")
+			case atom.Title:
+				if meta.docTitle == "" {
+					meta.docTitle = collapseSpace(textContent(n))
+				}
+			case atom.Meta:
+				key := strings.ToLower(attr(n, "property"))
+				if key == "" {
+					key = strings.ToLower(attr(n, "name"))
+				}
+				if key == "" {
+					key = strings.ToLower(attr(n, "itemprop"))
+				}
+				if content := strings.TrimSpace(attr(n, "content")); key != "" && content != "" {
+					if _, seen := tags[key]; !seen {
+						tags[key] = content
+					}
+				}
+			case atom.Link:
+				if meta.canonical == "" && hasToken(attr(n, "rel"), "canonical") {
+					meta.canonical = strings.TrimSpace(attr(n, "href"))
+				}
+			case atom.Script:
+				if strings.EqualFold(attr(n, "type"), "application/ld+json") {
+					readJSONLD(textContent(n), meta)
+				}
+			}
+		}
+		for child := n.FirstChild; child != nil; child = child.NextSibling {
+			walk(child)
+		}
+	}
+	walk(doc)
+
+	meta.ogTitle = firstNonEmpty(tags["og:title"], tags["twitter:title"])
+	meta.siteName = tags["og:site_name"]
+	meta.description = firstNonEmpty(tags["og:description"], tags["description"], tags["twitter:description"])
+
+	// Profile URLs in article:author are not names
+	if meta.author == "" {
+		for _, name := range authorMetaNames {
+			if value := tags[name]; value != "" && !strings.HasPrefix(value, "http") {
+				meta.author = value
+				break
+			}
+		}
+	}
+	if meta.published.IsZero() {
+		for _, name := range publishedMetaNames {
+			if published, ok := parsePublishedDate(tags[name]); ok {
+				meta.published = published
+				break
+			}
+		}
+	}
+
+	return meta
+}
+
+// jsonLDArticleTypes are the schema.org types describing the article itself
+var jsonLDArticleTypes = map[string]bool{
+	"Article": true, "NewsArticle": true, "BlogPosting": true, "Report": true, "ScholarlyArticle": true,
+	"TechArticle": true, "AnalysisNewsArticle": true, "OpinionNewsArticle": true, "ReportageNewsArticle": true,
+}
+
+// readJSONLD fills title, author and published date from the first article object in a JSON-LD block
+func readJSONLD(data string, meta *pageMetadata) {
+	var value interface{}
+	if err := json.Unmarshal([]byte(data), &value); err != nil {
+		return
+	}
+
+	var visit func(interface{}) bool
+	visit = func(v interface{}) bool {
+		switch node := v.(type) {
+		case []interface{}:
+			for _, item := range node {
+				if visit(item) {
+					return true
+				}
+			}
+		case map[string]interface{}:
+			if graph, ok := node["@graph"]; ok && visit(graph) {
+				return true
+			}
+			if !hasJSONLDType(node["@type"]) {
+				return false
+			}
+
+			if meta.jsonLDTitle == "" {
+				meta.jsonLDTitle = firstNonEmpty(jsonString(node["headline"]), jsonString(node["name"]))
+			}
+			if meta.author == "" {
+				meta.author = jsonLDAuthor(node["author"])
+			}
+			if published, ok := parsePublishedDate(jsonString(node["datePublished"])); ok && meta.published.IsZero() {
+				meta.published = published
+			}
+			return true
+		}
+		return false
+	}
+	visit(value)
+}
+
+// hasJSONLDType reports whether @type (a string or list) names an article type
+func hasJSONLDType(value interface{}) bool {
+	switch t := value.(type) {
+	case string:
+		return jsonLDArticleTypes[t]
+	case []interface{}:
+		for _, item := range t {
+			if name, ok := item.(string); ok && jsonLDArticleTypes[name] {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// jsonLDAuthor reads an author given as a name, a Person object or a list of either
+func jsonLDAuthor(value interface{}) string {
+	switch author := value.(type) {
+	case string:
+		return strings.TrimSpace(author)
+	case map[string]interface{}:
+		return jsonString(author["name"])
+	case []interface{}:
+		names := []string{}
+		for _, item := range author {
+			if name := jsonLDAuthor(item); name != "" {
+				names = append(names, name)
+			}
+		}
+		return strings.Join(names, ", ")
+	}
+	return ""
+}
+
+func jsonString(value interface{}) string {
+	s, _ := value.(string)
+	return strings.TrimSpace(s)
+}
+
+// publishedDateLayouts are the date formats seen in article metadata
+var publishedDateLayouts = []string{
+	time.RFC3339Nano,
+	time.RFC3339,
+	"2006-01-02T15:04:05Z0700",
+	"2006-01-02T15:04Z07:00",
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05",
+	"2006-01-02",
+	time.RFC1123Z,
+	time.RFC1123,
+	"January 2, 2006",
+}
+
+// parsePublishedDate parses a metadata date; ok is false when the value is empty or unrecognized
+func parsePublishedDate(value string) (time.Time, bool) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return time.Time{}, false
+	}
+
+	for _, layout := range publishedDateLayouts {
+		if published, err := time.Parse(layout, value); err == nil {
+			return published, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// hasToken reports whether a space separated attribute such as rel contains token
+func hasToken(value, token string) bool {
+	for _, field := range strings.Fields(value) {
+		if strings.EqualFold(field, token) {
+			return true
+		}
+	}
+	return false
+}
+
+// resolveReference resolves href against the page URL; "" when href is empty or invalid
+func resolveReference(pageURL, href string) string {
+	if href == "" {
+		return ""
+	}
+	base, err := url.Parse(pageURL)
+	if err != nil {
+		return ""
+	}
+	ref, err := base.Parse(href)
+	if err != nil {
+		return ""
+	}
+	return ref.String()
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, value := range values {
+		if value != "" {
+			return value
+		}
+	}
+	return ""
+}
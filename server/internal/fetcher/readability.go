@@ -0,0 +1,363 @@
+package fetcher
+
+import (
+	"math"
+	"regexp"
+	"strings"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// Main content extraction, following the approach of Mozilla's Readability:
+// boilerplate elements are pruned, paragraphs award points to their ancestors, and the
+// best-scoring container (discounted by link density) is rendered together with related siblings.
+
+// skippedTags never contain article text
+var skippedTags = map[atom.Atom]bool{
+	atom.Script: true, atom.Style: true, atom.Noscript: true, atom.Iframe: true, atom.Form: true,
+	atom.Nav: true, atom.Footer: true, atom.Header: true, atom.Aside: true, atom.Svg: true,
+	atom.Button: true, atom.Input: true, atom.Select: true, atom.Textarea: true, atom.Template: true,
+	atom.Object: true, atom.Embed: true, atom.Canvas: true, atom.Dialog: true, atom.Menu: true,
+}
+
+// blockTags start a new paragraph in extracted text; list items start a new line
+var blockTags = map[atom.Atom]bool{
+	atom.P: true, atom.Div: true, atom.Section: true, atom.Article: true, atom.Main: true,
+	atom.Blockquote: true, atom.Pre: true, atom.Ul: true, atom.Ol: true,
+	atom.Dl: true, atom.Dt: true, atom.Dd: true, atom.Table: true, atom.Tr: true,
+	atom.Figure: true, atom.Figcaption: true, atom.Hr: true, atom.Address: true,
+	atom.H1: true, atom.H2: true, atom.H3: true, atom.H4: true, atom.H5: true, atom.H6: true,
+}
+
+var headingLevels = map[atom.Atom]int{
+	atom.H1: 1, atom.H2: 2, atom.H3: 3, atom.H4: 4, atom.H5: 5, atom.H6: 6,
+}
+
+var (
+	unlikelyCandidates = regexp.MustCompile(`(?i)-ad-|ad-break|adbox|advert|agegate|banner|breadcrumb|combx|comment|community|consent|cookie|disqus|extra|footer|gdpr|header|legends|menu|modal|newsletter|pager|pagination|popup|promo|related|remark|replies|rss|share|shoutbox|sidebar|skyscraper|social|sponsor|subscribe|supplemental|toolbar|widget`)
+	maybeCandidate     = regexp.MustCompile(`(?i)and|article|body|column|content|main|shadow`)
+	positiveClass      = regexp.MustCompile(`(?i)article|body|content|entry|hentry|h-entry|main|page|post|text|blog|story`)
+	negativeClass      = regexp.MustCompile(`(?i)-ad-|hidden|^hid$| hid$| hid |^hid |banner|combx|comment|com-|contact|foot|footer|footnote|gdpr|masthead|media|meta|outbrain|promo|related|scroll|share|shoutbox|sidebar|skyscraper|sponsor|shopping|tags|tool|widget`)
+	whitespace         = regexp.MustCompile(`\s+`)
+	blankLines         = regexp.MustCompile(`\n{3,}`)
+)
+
+// minParagraphLength is the shortest paragraph, in characters, that scores
+const minParagraphLength = 25
+
+// extractContent returns the main text of a page as lightly formatted Markdown
+// The tree is modified: boilerplate nodes are removed.
+func extractContent(doc *html.Node) string {
+	body := findElement(doc, atom.Body)
+	if body == nil {
+		body = doc
+	}
+	prune(body)
+
+	scores := scoreParagraphs(body)
+
+	var top *html.Node
+	topScore := 0.0
+	for node, score := range scores {
+		score *= 1 - linkDensity(node)
+		scores[node] = score
+		if top == nil || score > topScore {
+			top, topScore = node, score
+		}
+	}
+	if top == nil {
+		return renderText([]*html.Node{body})
+	}
+
+	return renderText(withRelatedSiblings(top, topScore, scores))
+}
+
+// prune removes elements that are hidden or look like navigation, ads and other boilerplate
+func prune(n *html.Node) {
+	for child := n.FirstChild; child != nil; {
+		next := child.NextSibling
+		if child.Type == html.CommentNode || child.Type == html.ElementNode && isBoilerplate(child) {
+			n.RemoveChild(child)
+		} else {
+			prune(child)
+		}
+		child = next
+	}
+}
+
+func isBoilerplate(n *html.Node) bool {
+	if skippedTags[n.DataAtom] {
+		return true
+	}
+
+	if _, hidden := attrValue(n, "hidden"); hidden || attr(n, "aria-hidden") == "true" {
+		return true
+	}
+	style := strings.ReplaceAll(strings.ToLower(attr(n, "style")), " ", "")
+	if strings.Contains(style, "display:none") || strings.Contains(style, "visibility:hidden") {
+		return true
+	}
+
+	switch n.DataAtom {
+	case atom.Body, atom.Article, atom.Main, atom.A:
+		return false
+	}
+	match := attr(n, "class") + " " + attr(n, "id")
+	return unlikelyCandidates.MatchString(match) && !maybeCandidate.MatchString(match)
+}
+
+// scoreParagraphs awards each paragraph's points to its ancestors
+// Paragraph points grow with length and commas; the parent gets them in full,
+// the grandparent half and further ancestors progressively less.
+func scoreParagraphs(body *html.Node) map[*html.Node]float64 {
+	scores := map[*html.Node]float64{}
+
+	var walk func(*html.Node)
+	walk = func(n *html.Node) {
+		for child := n.FirstChild; child != nil; child = child.NextSibling {
+			if child.Type == html.ElementNode {
+				walk(child)
+			}
+		}
+		if !isParagraph(n) {
+			return
+		}
+
+		text := collapseSpace(textContent(n))
+		if len(text) < minParagraphLength {
+			return
+		}
+		points := 1 + float64(strings.Count(text, ",")) + math.Min(float64(len(text)/100), 3)
+
+		level := 0
+		for ancestor := n.Parent; ancestor != nil && ancestor.Type == html.ElementNode && level < 5; ancestor = ancestor.Parent {
+			if _, ok := scores[ancestor]; !ok {
+				scores[ancestor] = initialScore(ancestor)
+			}
+			switch level {
+			case 0:
+				scores[ancestor] += points
+			case 1:
+				scores[ancestor] += points / 2
+			default:
+				scores[ancestor] += points / float64(level*3)
+			}
+			level++
+		}
+	}
+	walk(body)
+
+	return scores
+}
+
+// isParagraph reports whether n holds paragraph text: a text block, or a div without block children
+func isParagraph(n *html.Node) bool {
+	switch n.DataAtom {
+	case atom.P, atom.Pre, atom.Td, atom.Blockquote:
+		return true
+	case atom.Div:
+		for child := n.FirstChild; child != nil; child = child.NextSibling {
+			if child.Type == html.ElementNode && blockTags[child.DataAtom] {
+				return false
+			}
+		}
+		return true
+	}
+	return false
+}
+
+// initialScore rates a container by its tag and class names
+func initialScore(n *html.Node) float64 {
+	score := classWeight(n)
+	switch n.DataAtom {
+	case atom.Div, atom.Article, atom.Main:
+		score += 5
+	case atom.Pre, atom.Td, atom.Blockquote:
+		score += 3
+	case atom.Address, atom.Ol, atom.Ul, atom.Dl, atom.Dd, atom.Dt, atom.Li, atom.Form:
+		score -= 3
+	case atom.H1, atom.H2, atom.H3, atom.H4, atom.H5, atom.H6, atom.Th:
+		score -= 5
+	}
+	return score
+}
+
+// classWeight favours content-like and penalizes boilerplate-like class and id names
+func classWeight(n *html.Node) float64 {
+	weight := 0.0
+	for _, name := range []string{attr(n, "class"), attr(n, "id")} {
+		if name == "" {
+			continue
+		}
+		if negativeClass.MatchString(name) {
+			weight -= 25
+		}
+		if positiveClass.MatchString(name) {
+			weight += 25
+		}
+	}
+	return weight
+}
+
+// linkDensity is the share of a node's text inside links
+func linkDensity(n *html.Node) float64 {
+	textLength := len(collapseSpace(textContent(n)))
+	if textLength == 0 {
+		return 0
+	}
+
+	linkLength := 0
+	var walk func(*html.Node)
+	walk = func(node *html.Node) {
+		if node.Type == html.ElementNode && node.DataAtom == atom.A {
+			linkLength += len(collapseSpace(textContent(node)))
+			return
+		}
+		for child := node.FirstChild; child != nil; child = child.NextSibling {
+			walk(child)
+		}
+	}
+	walk(n)
+
+	return float64(linkLength) / float64(textLength)
+}
+
+// withRelatedSiblings returns the top candidate with siblings that likely belong to the article,
+// such as an introduction paragraph kept outside the main container
+func withRelatedSiblings(top *html.Node, topScore float64, scores map[*html.Node]float64) []*html.Node {
+	if top.Parent == nil {
+		return []*html.Node{top}
+	}
+
+	threshold := math.Max(10, topScore*0.2)
+	nodes := []*html.Node{}
+	for sibling := top.Parent.FirstChild; sibling != nil; sibling = sibling.NextSibling {
+		if sibling == top {
+			nodes = append(nodes, sibling)
+			continue
+		}
+		if sibling.Type != html.ElementNode {
+			continue
+		}
+
+		if score, ok := scores[sibling]; ok && score >= threshold {
+			nodes = append(nodes, sibling)
+			continue
+		}
+		if sibling.DataAtom == atom.P {
+			text := collapseSpace(textContent(sibling))
+			density := linkDensity(sibling)
+			if len(text) > 80 && density < 0.25 || len(text) > 0 && density == 0 && strings.HasSuffix(text, ".") {
+				nodes = append(nodes, sibling)
+			}
+		}
+	}
+
+	return nodes
+}
+
+// renderText converts nodes to plain text with Markdown headings and list items
+func renderText(nodes []*html.Node) string {
+	var b strings.Builder
+
+	var render func(*html.Node, bool)
+	render = func(n *html.Node, preformatted bool) {
+		switch n.Type {
+		case html.TextNode:
+			if preformatted {
+				b.WriteString(n.Data)
+			} else {
+				b.WriteString(whitespace.ReplaceAllString(n.Data, " "))
+			}
+			return
+		case html.ElementNode, html.DocumentNode:
+		default:
+			return
+		}
+
+		block := blockTags[n.DataAtom]
+		switch {
+		case n.DataAtom == atom.Br:
+			b.WriteString("\n")
+		case headingLevels[n.DataAtom] > 0:
+			b.WriteString("\n\n" + strings.Repeat("#", headingLevels[n.DataAtom]) + " ")
+		case n.DataAtom == atom.Li:
+			b.WriteString("\n- ")
+		case block:
+			b.WriteString("\n\n")
+		}
+
+		for child := n.FirstChild; child != nil; child = child.NextSibling {
+			render(child, preformatted || n.DataAtom == atom.Pre)
+		}
+
+		switch {
+		case block:
+			b.WriteString("\n\n")
+		case n.DataAtom == atom.Li:
+			b.WriteString("\n")
+		}
+	}
+	for _, n := range nodes {
+		render(n, false)
+	}
+
+	// Trim every line and drop headings and list markers left without text
+	lines := strings.Split(b.String(), "\n")
+	kept := make([]string, 0, len(lines))
+	for _, line := range lines {
+		line = strings.TrimSpace(line)
+		if strings.Trim(line, "#- ") == "" {
+			line = ""
+		}
+		kept = append(kept, line)
+	}
+
+	return strings.TrimSpace(blankLines.ReplaceAllString(strings.Join(kept, "\n"), "\n\n"))
+}
+
+// findElement returns the first element with the given tag in document order
+func findElement(n *html.Node, tag atom.Atom) *html.Node {
+	if n.Type == html.ElementNode && n.DataAtom == tag {
+		return n
+	}
+	for child := n.FirstChild; child != nil; child = child.NextSibling {
+		if found := findElement(child, tag); found != nil {
+			return found
+		}
+	}
+	return nil
+}
+
+// textContent concatenates the text below n
+func textContent(n *html.Node) string {
+	if n.Type == html.TextNode {
+		return n.Data
+	}
+
+	var b strings.Builder
+	for child := n.FirstChild; child != nil; child = child.NextSibling {
+		b.WriteString(textContent(child))
+	}
+	return b.String()
+}
+
+func collapseSpace(s string) string {
+	return strings.TrimSpace(whitespace.ReplaceAllString(s, " "))
+}
+
+// attrValue returns an attribute's value and whether it is present
+func attrValue(n *html.Node, key string) (string, bool) {
+	for _, a := range n.Attr {
+		if a.Key == key {
+			return a.Val, true
+		}
+	}
+	return "", false
+}
+
+func attr(n *html.Node, key string) string {
+	value, _ := attrValue(n, key)
+	return value
+}
@@ -0,0 +1,155 @@
+package handlers
+
+import (
+	"context"
+	"log/slog"
+	"regexp"
+
+	"article-chat-system/server/internal/auth"
+	"article-chat-system/server/internal/errors"
+	"article-chat-system/server/internal/models"
+	"article-chat-system/server/internal/services"
+	"github.com/google/uuid"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// AccountHandler handles account self-service: email changes, deactivation and deletion
+type AccountHandler struct {
+	authService *auth.AuthService
+	cache       services.CacheService
+}
+
+// NewAccountHandler creates a new account handler
+func NewAccountHandler(authService *auth.AuthService, cache services.CacheService) *AccountHandler {
+	return &AccountHandler{
+		authService: authService,
+		cache:       cache,
+	}
+}
+
+// HandleDeleteAccount deletes (or, with "deactivate": true, only disables) the authenticated account
+func (h *AccountHandler) HandleDeleteAccount(c *fiber.Ctx) error {
+	// Get authenticated user
+	user, err := auth.GetUserFromContext(c)
+	if err != nil {
+		return err
+	}
+
+	var req models.AccountDeletion
+
+	// Parse request body
+	if err := c.BodyParser(&req); err != nil {
+		slog.Debug("Failed to parse account deletion request", "error", err)
+		return errors.New(errors.ErrBadRequest, "Invalid request body")
+	}
+
+	if err := h.authService.ConfirmIdentity(user, req.Password); err != nil {
+		return err
+	}
+
+	if req.Deactivate {
+		if err := h.authService.DeactivateAccount(user.ID); err != nil {
+			return err
+		}
+
+		slog.Info("Account deactivated", "user_id", user.ID)
+
+		return c.JSON(fiber.Map{
+			"message": "Account deactivated",
+		})
+	}
+
+	ctx := c.Context()
+
+	// Cached chat answers are keyed by message and conversation, so they must be
+	// purged while the messages needed to rebuild the keys still exist
+	if err := h.purgeChatCache(ctx, user.ID); err != nil {
+		return err
+	}
+
+	if err := h.authService.DeleteAccount(ctx, user.ID); err != nil {
+		return err
+	}
+
+	slog.Info("Account deleted", "user_id", user.ID)
+
+	return c.JSON(fiber.Map{
+		"message": "Account and all associated data deleted",
+	})
+}
+
+// HandleRequestEmailChange sends a confirmation link to the requested new address
+func (h *AccountHandler) HandleRequestEmailChange(c *fiber.Ctx) error {
+	// Get authenticated user
+	user, err := auth.GetUserFromContext(c)
+	if err != nil {
+		return err
+	}
+
+	var req models.EmailChange
+
+	// Parse request body
+	if err := c.BodyParser(&req); err != nil {
+		slog.Debug("Failed to parse email change request", "error", err)
+		return errors.New(errors.ErrBadRequest, "Invalid request body")
+	}
+
+	if req.NewEmail == "" {
+		return errors.New(errors.ErrMissingRequiredField, "New email is required")
+	}
+
+	// Validate email format
+	emailRegex := regexp.MustCompile(`^[a-zA-Z0-9._%+-]+@[a-zA-Z0-9.-]+\.[a-zA-Z]{2,}$`)
+	if !emailRegex.MatchString(req.NewEmail) {
+		return errors.New(errors.ErrValidationFailed, "Invalid email format")
+	}
+
+	if err := h.authService.RequestEmailChange(c.Context(), user, &req); err != nil {
+		return err
+	}
+
+	slog.Info("Email change requested", "user_id", user.ID)
+
+	return c.JSON(fiber.Map{
+		"message": "Confirmation link sent to the new email address",
+	})
+}
+
+// HandleConfirmEmailChange applies an email change from its emailed token
+func (h *AccountHandler) HandleConfirmEmailChange(c *fiber.Ctx) error {
+	var req models.EmailChangeConfirmation
+
+	// Parse request body
+	if err := c.BodyParser(&req); err != nil {
+		slog.Debug("Failed to parse email change confirmation", "error", err)
+		return errors.New(errors.ErrBadRequest, "Invalid request body")
+	}
+
+	if err := h.authService.ConfirmEmailChange(req.Token); err != nil {
+		return err
+	}
+
+	return c.JSON(fiber.Map{
+		"message": "Email address updated",
+	})
+}
+
+// purgeChatCache removes cached chat answers produced for the user's conversations
+func (h *AccountHandler) purgeChatCache(ctx context.Context, userID uuid.UUID) error {
+	prompts, err := h.authService.GetDB().GetUserChatPrompts(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	for _, prompt := range prompts {
+		cacheKey := services.ChatCacheKey(prompt.Content, prompt.ConversationID)
+		if err := h.cache.Delete(ctx, cacheKey); err != nil {
+			slog.Warn("Failed to purge cached chat response", "error", err, "cache_key", cacheKey[:8]+"...")
+		}
+	}
+
+	slog.Debug("Purged cached chat responses", "user_id", userID, "count", len(prompts))
+
+	return nil
+}
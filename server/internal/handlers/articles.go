@@ -1,6 +1,9 @@
 package handlers
 
 import (
+	"article-chat-system/server/internal/auth"
+	"article-chat-system/server/internal/config"
+	"article-chat-system/server/internal/database"
 	"article-chat-system/server/internal/errors"
 	"article-chat-system/server/internal/fetcher"
 	"article-chat-system/server/internal/models"
@@ -8,39 +11,62 @@ import (
 	"article-chat-system/server/internal/validation"
 	"article-chat-system/server/internal/workers"
 	"context"
-	"fmt"
+	"io"
 	"log/slog"
-	"sync"
+	"mime/multipart"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+const (
+	// uploadTimeout bounds indexing the documents of one upload request
+	uploadTimeout = 2 * time.Minute
+	// retryTimeout bounds a manual retry of a failed article
+	retryTimeout = 2 * time.Minute
+	// retryLease is how many seconds a manual retry holds its article, outlasting retryTimeout
+	retryLease = 5 * 60
 )
 
 type ArticleHandler struct {
 	fetcher     *fetcher.ArticleFetcher
-	ragClient   *services.RAGClient
+	ingester    *services.ArticleIngester
+	db          *database.DB
 	poolManager *workers.PoolManager
-	cache       services.CacheService
-	articles    map[string]*models.Article // In-memory storage for demo
-	articlesMux sync.RWMutex
+	reindexer   *services.ArticleReindexer
+	authService *auth.AuthService
+	uploads     config.UploadConfig
 }
 
 func NewArticleHandler(
 	fetcher *fetcher.ArticleFetcher,
-	ragClient *services.RAGClient,
+	ingester *services.ArticleIngester,
+	db *database.DB,
 	poolManager *workers.PoolManager,
-	cache services.CacheService,
+	reindexer *services.ArticleReindexer,
+	authService *auth.AuthService,
+	uploads config.UploadConfig,
 ) *ArticleHandler {
 	return &ArticleHandler{
 		fetcher:     fetcher,
-		ragClient:   ragClient,
+		ingester:    ingester,
+		db:          db,
 		poolManager: poolManager,
-		cache:       cache,
-		articles:    make(map[string]*models.Article),
+		reindexer:   reindexer,
+		authService: authService,
+		uploads:     uploads,
 	}
 }
 
 func (h *ArticleHandler) HandleAddArticle(c *fiber.Ctx) error {
+	user, err := auth.GetUserFromContext(c)
+	if err != nil {
+		return err
+	}
+
 	var req models.AddArticleRequest
 	if err := c.BodyParser(&req); err != nil {
 		slog.Error("Failed to parse add article request", "error", err)
@@ -56,93 +82,404 @@ func (h *ArticleHandler) HandleAddArticle(c *fiber.Ctx) error {
 		return err
 	}
 
+	if err := validatePriority(req.Priority); err != nil {
+		return err.WithRequestID(c.Get("X-Request-ID"))
+	}
+
 	// Create context with timeout
 	ctx, cancel := context.WithTimeout(c.Context(), 2*time.Minute)
 	defer cancel()
 
-	// Generate cache key for article URL
-	cacheKey := services.GenerateArticleCacheKey(req.URL)
+	// Refuse internal addresses, disallowed ports and denied domains before anything is fetched
+	if err := h.fetcher.CheckURL(ctx, req.URL); err != nil {
+		slog.Warn("Article URL rejected", "url", req.URL, "error", err)
+		return errors.New(
+			errors.ErrValidationFailed,
+			err.Error(),
+		).WithRequestID(c.Get("X-Request-ID"))
+	}
 
-	// Check if article is already cached
-	var cachedResponse models.AddArticleResponse
-	if err := h.cache.Get(ctx, cacheKey, &cachedResponse); err == nil {
-		slog.Info("Article cache hit",
+	// Answer known articles without waiting for a worker
+	known, err := h.ingester.Known(ctx, req.URL)
+	if err != nil {
+		return err
+	}
+	if known != nil {
+		return c.JSON(known)
+	}
+
+	// Process article asynchronously
+	responseChan := make(chan *models.AddArticleResponse, 1)
+	errorChan := make(chan error, 1)
+
+	h.poolManager.SubmitArticleTask(workers.ArticleTask{
+		Owner:    user.ID.String(),
+		Priority: req.Priority,
+		Label:    req.URL,
+		Ctx:      ctx,
+		Run: func() {
+			response, err := h.ingester.Ingest(ctx, services.IngestRequest{
+				URL:    req.URL,
+				Source: services.ArticleSourceUser,
+				Tags:   req.Tags,
+				Custom: req.Custom,
+
+				SubmittedBy: user.ID.String(),
+			})
+			if err != nil {
+				errorChan <- err
+				return
+			}
+			responseChan <- response
+		},
+	})
+
+	// Wait for completion or timeout
+	select {
+	case response := <-responseChan:
+		slog.Info("Article processing completed",
 			"url", req.URL,
-			"cache_key", cacheKey[:12]+"...",
-			"cached_article_id", cachedResponse.ID)
+			"article_id", response.ID)
+		return c.JSON(response)
+
+	case err := <-errorChan:
+		slog.Error("Article processing failed", "url", req.URL, "error", err)
+		return err
 
-		// Mark as cached and return
-		cachedResponse.Cached = true
-		cachedResponse.Message = "Article already processed (from cache)"
-		return c.JSON(cachedResponse)
+	case <-ctx.Done():
+		return errors.New(
+			errors.ErrServiceUnavailable,
+			"Article processing timed out",
+		).WithRequestID(c.Get("X-Request-ID"))
 	}
+}
 
-	slog.Debug("Article cache miss",
-		"url", req.URL,
-		"cache_key", cacheKey[:12]+"...")
+// HandleUploadArticles adds uploaded documents to the knowledge base
+// POST /api/articles/upload (multipart: "files", optional comma separated "tags")
+// Every file is checked and its text extracted before anything is indexed, so one bad file
+// rejects the whole request. Indexing results are then reported per file.
+func (h *ArticleHandler) HandleUploadArticles(c *fiber.Ctx) error {
+	user, err := auth.GetUserFromContext(c)
+	if err != nil {
+		return err
+	}
 
-	// Process article asynchronously
-	responseChan := make(chan models.AddArticleResponse, 1)
-	errorChan := make(chan error, 1)
+	form, err := c.MultipartForm()
+	if err != nil {
+		return errors.New(
+			errors.ErrBadRequest,
+			"Expected a multipart form with files",
+		).WithRequestID(c.Get("X-Request-ID"))
+	}
 
-	h.poolManager.SubmitArticleTask(func() {
-		// Create a simple article record
-		slog.Info("Forwarding article to RAG service", "url", req.URL)
-
-		// Generate a simple ID from URL
-		articleID := fmt.Sprintf("article_%d", time.Now().Unix())
-		article := &models.Article{
-			ID:        articleID,
-			URL:       req.URL,
-			Status:    "processing",
-			FetchedAt: time.Now(),
-			Source:    "user_submitted",
+	files := append(form.File["files"], form.File["file"]...)
+	if len(files) == 0 {
+		return errors.New(
+			errors.ErrMissingRequiredField,
+			"At least one file is required",
+		).WithRequestID(c.Get("X-Request-ID"))
+	}
+	if len(files) > h.uploads.MaxFiles {
+		return errors.NewWithDetails(
+			errors.ErrValidationFailed,
+			"Too many files",
+			map[string]interface{}{"max_files": h.uploads.MaxFiles},
+		).WithRequestID(c.Get("X-Request-ID"))
+	}
+
+	priority := workers.MinArticlePriority
+	if values := form.Value["priority"]; len(values) > 0 && values[0] != "" {
+		if priority, err = strconv.Atoi(values[0]); err != nil {
+			return errors.New(
+				errors.ErrValidationFailed,
+				"Priority must be a number",
+			).WithRequestID(c.Get("X-Request-ID"))
+		}
+		if err := validatePriority(priority); err != nil {
+			return err.WithRequestID(c.Get("X-Request-ID"))
 		}
+	}
 
-		// Send article to RAG service for processing
-		metadata := map[string]interface{}{
-			"article_id":   articleID,
-			"source":       "user_submitted",
-			"submitted_at": time.Now(),
+	var tags []string
+	for _, value := range form.Value["tags"] {
+		for _, tag := range strings.Split(value, ",") {
+			if tag = validation.SanitizeString(tag); tag != "" {
+				tags = append(tags, tag)
+			}
 		}
+	}
 
-		if err := h.ragClient.ProcessArticle(ctx, req.URL, metadata); err != nil {
-			slog.Error("Failed to send article to RAG service", "error", err, "url", req.URL)
-			errorChan <- fmt.Errorf("failed to process article: %w", err)
-			return
+	articles := make([]*models.Article, len(files))
+	for i, file := range files {
+		article, err := h.extractUpload(file)
+		if err != nil {
+			return err.WithRequestID(c.Get("X-Request-ID"))
 		}
+		article.SubmittedBy = user.ID.String()
+		articles[i] = article
+	}
 
-		// Store article (in production, save to database)
-		h.articlesMux.Lock()
-		h.articles[article.ID] = article
-		h.articlesMux.Unlock()
-		article.Status = "indexed"
+	ctx, cancel := context.WithTimeout(c.Context(), uploadTimeout)
+	defer cancel()
 
-		response := models.AddArticleResponse{
-			ID:      article.ID,
-			Status:  "success",
-			Message: "Article processed and indexed successfully",
-		}
+	results := make([]models.UploadArticleResult, len(files))
+	done := make(chan struct{}, len(files))
+	for i, article := range articles {
+		h.poolManager.SubmitArticleTask(workers.ArticleTask{
+			Owner:    user.ID.String(),
+			Priority: priority,
+			Label:    article.URL,
+			Ctx:      ctx,
+			Run: func() {
+				defer func() { done <- struct{}{} }()
+
+				result := models.UploadArticleResult{Filename: files[i].Filename}
+				response, err := h.ingester.IngestDocument(ctx, article, tags, map[string]string{"filename": files[i].Filename})
+				if err != nil {
+					slog.Error("Document upload failed", "filename", files[i].Filename, "error", err)
+					result.Status = "error"
+					result.Message = "Failed to index document"
+					result.Error = err.Error()
+				} else {
+					result.AddArticleResponse = *response
+				}
+				results[i] = result
+			},
+		})
+	}
 
-		// Cache the successful response with 24 hour TTL
-		if cacheErr := h.cache.Set(ctx, cacheKey, response, 24*time.Hour); cacheErr != nil {
-			slog.Warn("Failed to cache article response", "error", cacheErr, "cache_key", cacheKey[:12]+"...")
-			// Don't fail the request if caching fails
+	for range articles {
+		select {
+		case <-done:
+		case <-ctx.Done():
+			return errors.New(
+				errors.ErrServiceUnavailable,
+				"Document processing timed out",
+			).WithRequestID(c.Get("X-Request-ID"))
 		}
+	}
 
-		responseChan <- response
+	slog.Info("Documents uploaded", "files", len(files))
+	return c.JSON(fiber.Map{
+		"results": results,
+		"total":   len(results),
+	})
+}
+
+// extractUpload checks an uploaded file's size and type and extracts its text
+func (h *ArticleHandler) extractUpload(file *multipart.FileHeader) (*models.Article, *errors.AppError) {
+	if file.Size > h.uploads.MaxFileBytes {
+		return nil, errors.NewWithDetails(errors.ErrFileTooLarge, "File is too large", map[string]interface{}{
+			"filename":  file.Filename,
+			"max_bytes": h.uploads.MaxFileBytes,
+		})
+	}
+
+	f, err := file.Open()
+	if err != nil {
+		return nil, errors.Wrap(err, errors.ErrBadRequest)
+	}
+	defer f.Close()
+
+	data, err := io.ReadAll(io.LimitReader(f, h.uploads.MaxFileBytes))
+	if err != nil {
+		return nil, errors.Wrap(err, errors.ErrBadRequest)
+	}
+
+	if _, err := fetcher.DocumentType(file.Filename, data); err != nil {
+		return nil, errors.NewWithDetails(
+			errors.ErrUnsupportedFileType,
+			"Only .txt, .md, .html and .pdf files are accepted",
+			map[string]string{"filename": file.Filename, "reason": err.Error()},
+		)
+	}
+
+	article, err := h.fetcher.ExtractDocument(file.Filename, data)
+	if err != nil {
+		return nil, errors.NewWithDetails(
+			errors.ErrValidationFailed,
+			"Failed to extract text from document",
+			map[string]string{"filename": file.Filename, "reason": err.Error()},
+		)
+	}
+
+	return article, nil
+}
+
+// validatePriority checks a submitted article priority against the queue's range
+func validatePriority(priority int) *errors.AppError {
+	if priority < workers.MinArticlePriority || priority > workers.MaxArticlePriority {
+		return errors.NewWithDetails(
+			errors.ErrValidationFailed,
+			"Priority is out of range",
+			map[string]int{"min": workers.MinArticlePriority, "max": workers.MaxArticlePriority},
+		)
+	}
+	return nil
+}
+
+// HandleListArticles lists articles in the knowledge base
+// Query parameters: status (comma separated), domain, tag, source, submitted_by (user ID or "me"),
+// from and to (RFC 3339 or YYYY-MM-DD) on date (fetched_at|published_at), q (keywords in title and
+// content), sort (fetched_at|published_at|title), order (asc|desc), include_content, limit and cursor
+func (h *ArticleHandler) HandleListArticles(c *fiber.Ctx) error {
+	filter, err := parseArticleFilter(c)
+	if err != nil {
+		return err
+	}
+
+	articles, cursors, err := h.db.ListArticles(c.Context(), filter)
+	if err != nil {
+		return err
+	}
+
+	totalCount, err := h.db.CountArticles(c.Context(), filter)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(fiber.Map{
+		"articles": articles,
+		"pagination": fiber.Map{
+			"limit":       filter.Limit,
+			"total_count": totalCount,
+			"has_more":    cursors.Next != "",
+			"next_cursor": cursors.Next,
+			"prev_cursor": cursors.Prev,
+		},
+	})
+}
+
+func (h *ArticleHandler) HandleGetArticle(c *fiber.Ctx) error {
+	articleID, err := h.articleID(c)
+	if err != nil {
+		return err
+	}
+
+	article, err := h.db.GetArticle(c.Context(), articleID)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(article)
+}
+
+func (h *ArticleHandler) HandleDeleteArticle(c *fiber.Ctx) error {
+	articleID, err := h.articleID(c)
+	if err != nil {
+		return err
+	}
+
+	article, err := h.db.GetArticle(c.Context(), articleID)
+	if err != nil {
+		return err
+	}
+
+	if err := h.db.DeleteArticle(c.Context(), articleID); err != nil {
+		return err
+	}
+	h.ingester.Forget(c.Context(), article)
+
+	// Note: Deletion from RAG service should be implemented if needed
+
+	slog.Info("Article deleted successfully", "article_id", article.ID, "title", article.Title)
+
+	return c.JSON(fiber.Map{
+		"message": "Article deleted successfully",
+		"id":      article.ID,
+	})
+}
+
+// HandleGetArticleVersions lists an article's content versions, the current one first
+func (h *ArticleHandler) HandleGetArticleVersions(c *fiber.Ctx) error {
+	articleID, err := h.articleID(c)
+	if err != nil {
+		return err
+	}
+
+	versions, err := h.db.GetArticleVersions(c.Context(), articleID)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(fiber.Map{
+		"article_id": articleID,
+		"versions":   versions,
+	})
+}
+
+// HandleRetryArticle runs ingestion again for a failed article
+// POST /api/articles/:id/retry
+// An article whose page could not be fetched is fetched again; otherwise its stored content is
+// indexed again. The response matches POST /api/articles.
+func (h *ArticleHandler) HandleRetryArticle(c *fiber.Ctx) error {
+	user, err := auth.GetUserFromContext(c)
+	if err != nil {
+		return err
+	}
+
+	articleID, err := h.articleID(c)
+	if err != nil {
+		return err
+	}
+
+	article, err := h.db.GetArticle(c.Context(), articleID)
+	if err != nil {
+		return err
+	}
+	if article.SubmittedBy != user.ID.String() && !h.authService.IsAdmin(user) {
+		return errors.New(
+			errors.ErrForbidden,
+			"Only the submitter or an admin can retry this article",
+		).WithRequestID(c.Get("X-Request-ID"))
+	}
+	if article.Status != models.ArticleStatusFailed {
+		return errors.NewWithDetails(
+			errors.ErrValidationFailed,
+			"Only failed articles can be retried",
+			map[string]string{"status": article.Status},
+		).WithRequestID(c.Get("X-Request-ID"))
+	}
+
+	// Lease the article like the retry scheduler does, so the two never run at once
+	article, err = h.db.ClaimArticleForRetry(c.Context(), articleID, retryLease)
+	if err != nil {
+		return err
+	}
+	if article == nil {
+		return errors.New(
+			errors.ErrValidationFailed,
+			"Article is already being retried",
+		).WithRequestID(c.Get("X-Request-ID"))
+	}
+
+	ctx, cancel := context.WithTimeout(c.Context(), retryTimeout)
+	defer cancel()
+
+	responseChan := make(chan *models.AddArticleResponse, 1)
+	errorChan := make(chan error, 1)
+
+	h.poolManager.SubmitArticleTask(workers.ArticleTask{
+		Owner: user.ID.String(),
+		Label: article.URL,
+		Ctx:   ctx,
+		Run: func() {
+			response, err := h.ingester.Reingest(ctx, article)
+			if err != nil {
+				errorChan <- err
+				return
+			}
+			responseChan <- response
+		},
 	})
 
-	// Wait for completion or timeout
 	select {
 	case response := <-responseChan:
-		slog.Info("Article processing completed",
-			"url", req.URL,
-			"article_id", response.ID)
+		slog.Info("Article retry completed", "article_id", article.ID, "result_id", response.ID)
 		return c.JSON(response)
 
 	case err := <-errorChan:
-		slog.Error("Article processing failed", "url", req.URL, "error", err)
+		slog.Error("Article retry failed", "article_id", article.ID, "error", err)
 		return err
 
 	case <-ctx.Done():
@@ -153,73 +490,177 @@ func (h *ArticleHandler) HandleAddArticle(c *fiber.Ctx) error {
 	}
 }
 
-func (h *ArticleHandler) HandleListArticles(c *fiber.Ctx) error {
-	h.articlesMux.RLock()
-	articles := make([]*models.Article, 0, len(h.articles))
-	for _, article := range h.articles {
-		articles = append(articles, article)
+// HandleReindexArticles starts a background job re-running ingestion for all articles or those
+// matching the list filters of HandleListArticles (admin only)
+// POST /api/articles/reindex
+// Stored content is re-indexed, replacing its chunks; failed fetches are fetched again.
+func (h *ArticleHandler) HandleReindexArticles(c *fiber.Ctx) error {
+	filter, err := parseArticleFilter(c)
+	if err != nil {
+		return err
 	}
-	h.articlesMux.RUnlock()
 
-	slog.Info("Listed articles", "count", len(articles))
-	return c.JSON(fiber.Map{
-		"articles": articles,
-		"total":    len(articles),
-	})
+	job, err := h.reindexer.Start(c.Context(), filter)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusAccepted).JSON(job)
 }
 
-func (h *ArticleHandler) HandleGetArticle(c *fiber.Ctx) error {
-	articleID := c.Params("id")
-	if articleID == "" {
+// HandleGetReindexJob reports the progress of a reindex job (admin only)
+// GET /api/articles/reindex/:id
+func (h *ArticleHandler) HandleGetReindexJob(c *fiber.Ctx) error {
+	job, ok := h.reindexer.Job(c.Params("id"))
+	if !ok {
 		return errors.New(
-			errors.ErrMissingRequiredField,
-			"Article ID is required",
+			errors.ErrResourceNotFound,
+			"Reindex job not found",
 		).WithRequestID(c.Get("X-Request-ID"))
 	}
 
-	h.articlesMux.RLock()
-	article, exists := h.articles[articleID]
-	h.articlesMux.RUnlock()
-	if !exists {
+	return c.JSON(job)
+}
+
+// HandleSetRefreshInterval sets how often an article is re-fetched
+func (h *ArticleHandler) HandleSetRefreshInterval(c *fiber.Ctx) error {
+	articleID, err := h.articleID(c)
+	if err != nil {
+		return err
+	}
+
+	var req models.ArticleRefreshIntervalRequest
+	if err := c.BodyParser(&req); err != nil {
+		return errors.NewWithDetails(
+			errors.ErrBadRequest,
+			"Failed to parse request body",
+			map[string]string{"parse_error": err.Error()},
+		).WithRequestID(c.Get("X-Request-ID"))
+	}
+	if req.Interval != nil && *req.Interval < 0 {
 		return errors.New(
-			errors.ErrArticleNotFound,
-			"Article not found",
+			errors.ErrValidationFailed,
+			"Refresh interval must not be negative",
 		).WithRequestID(c.Get("X-Request-ID"))
 	}
 
+	if err := h.db.SetArticleRefreshInterval(c.Context(), articleID, req.Interval); err != nil {
+		return err
+	}
+
+	article, err := h.db.GetArticle(c.Context(), articleID)
+	if err != nil {
+		return err
+	}
+
 	return c.JSON(article)
 }
 
-func (h *ArticleHandler) HandleDeleteArticle(c *fiber.Ctx) error {
-	articleID := c.Params("id")
-	if articleID == "" {
-		return errors.New(
+// articleID parses the :id route parameter; unknown formats are reported as not found
+func (h *ArticleHandler) articleID(c *fiber.Ctx) (uuid.UUID, error) {
+	if c.Params("id") == "" {
+		return uuid.Nil, errors.New(
 			errors.ErrMissingRequiredField,
 			"Article ID is required",
 		).WithRequestID(c.Get("X-Request-ID"))
 	}
 
-	h.articlesMux.RLock()
-	article, exists := h.articles[articleID]
-	h.articlesMux.RUnlock()
-	if !exists {
-		return errors.New(
+	articleID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return uuid.Nil, errors.New(
 			errors.ErrArticleNotFound,
 			"Article not found",
 		).WithRequestID(c.Get("X-Request-ID"))
 	}
 
-	// Remove from memory (in production, delete from database)
-	h.articlesMux.Lock()
-	delete(h.articles, articleID)
-	h.articlesMux.Unlock()
+	return articleID, nil
+}
+
+// parseArticleFilter parses the list filters, sort and pagination of HandleListArticles
+func parseArticleFilter(c *fiber.Ctx) (*models.ArticleFilter, error) {
+	limit, _, err := parsePaginationParams(c)
+	if err != nil {
+		return nil, err
+	}
+
+	filter := &models.ArticleFilter{
+		Domain:         strings.ToLower(strings.TrimSpace(c.Query("domain"))),
+		Tag:            strings.TrimSpace(c.Query("tag")),
+		Source:         c.Query("source"),
+		DateField:      c.Query("date", "fetched_at"),
+		Query:          strings.TrimSpace(c.Query("q")),
+		Sort:           c.Query("sort", "fetched_at"),
+		IncludeContent: c.QueryBool("include_content"),
+		Limit:          limit,
+		Cursor:         c.Query("cursor"),
+	}
 
-	// Note: Deletion from RAG service should be implemented if needed
+	if statuses := c.Query("status"); statuses != "" {
+		for _, status := range strings.Split(statuses, ",") {
+			switch status = strings.TrimSpace(status); status {
+			case models.ArticleStatusFetched, models.ArticleStatusIndexing, models.ArticleStatusIndexed, models.ArticleStatusFailed:
+				filter.Statuses = append(filter.Statuses, status)
+			default:
+				return nil, errors.New(errors.ErrValidationFailed, "status must be 'fetched', 'indexing', 'indexed' or 'failed'")
+			}
+		}
+	}
 
-	slog.Info("Article deleted successfully", "article_id", articleID, "title", article.Title)
+	if filter.Domain != "" && strings.Trim(filter.Domain, "abcdefghijklmnopqrstuvwxyz0123456789.-") != "" {
+		return nil, errors.New(errors.ErrValidationFailed, "Invalid domain")
+	}
 
-	return c.JSON(fiber.Map{
-		"message": "Article deleted successfully",
-		"id":      articleID,
-	})
+	switch filter.Source {
+	case "", services.ArticleSourceUser, services.ArticleSourceFeed, services.ArticleSourceUpload:
+	default:
+		return nil, errors.New(errors.ErrValidationFailed, "source must be 'user_submitted', 'feed' or 'upload'")
+	}
+
+	if submitter := c.Query("submitted_by"); submitter == "me" {
+		user, err := auth.GetUserFromContext(c)
+		if err != nil {
+			return nil, err
+		}
+		filter.SubmittedBy = &user.ID
+	} else if submitter != "" {
+		userID, err := uuid.Parse(submitter)
+		if err != nil {
+			return nil, errors.New(errors.ErrInvalidDataType, "Invalid submitted_by format")
+		}
+		filter.SubmittedBy = &userID
+	}
+
+	if filter.DateField != "fetched_at" && filter.DateField != "published_at" {
+		return nil, errors.New(errors.ErrValidationFailed, "date must be 'fetched_at' or 'published_at'")
+	}
+	if filter.From, err = parseDateParam(c, "from", false); err != nil {
+		return nil, err
+	}
+	if filter.To, err = parseDateParam(c, "to", true); err != nil {
+		return nil, err
+	}
+
+	if len(filter.Query) > 200 {
+		return nil, errors.New(errors.ErrValidationFailed, "Search query must be 200 characters or less")
+	}
+
+	if !database.IsArticleSort(filter.Sort) {
+		return nil, errors.New(errors.ErrValidationFailed, "sort must be 'fetched_at', 'published_at' or 'title'")
+	}
+
+	// Titles read naturally A-Z; dates newest first
+	order := "desc"
+	if filter.Sort == "title" {
+		order = "asc"
+	}
+	switch c.Query("order", order) {
+	case "asc":
+		filter.Desc = false
+	case "desc":
+		filter.Desc = true
+	default:
+		return nil, errors.New(errors.ErrValidationFailed, "order must be 'asc' or 'desc'")
+	}
+
+	return filter, nil
 }
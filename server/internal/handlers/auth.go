@@ -48,6 +48,15 @@ func (h *AuthHandler) HandleSignup(c *fiber.Ctx) error {
 	// Log user creation
 	slog.Info("New user registered", "user_id", user.ID, "email", user.Email)
 
+	message := "Registration successful. Please login to continue."
+	if h.authService.EmailVerificationEnabled() {
+		// Signup still succeeds if the email cannot be sent; the user can request a new link
+		if err := h.authService.SendVerificationEmail(c.Context(), user); err != nil {
+			slog.Warn("Failed to send verification email", "user_id", user.ID, "error", err)
+		}
+		message = "Registration successful. Check your email to verify your address."
+	}
+
 	// Return user profile
 	profile := models.UserProfile{
 		ID:        user.ID,
@@ -59,7 +68,7 @@ func (h *AuthHandler) HandleSignup(c *fiber.Ctx) error {
 
 	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
 		"user":    profile,
-		"message": "Registration successful. Please login to continue.",
+		"message": message,
 	})
 }
 
@@ -83,29 +92,20 @@ func (h *AuthHandler) HandleLogin(c *fiber.Ctx) error {
 	ipAddress := c.IP()
 
 	// Authenticate user
-	user, token, err := h.authService.LoginUser(&credentials, userAgent, ipAddress)
+	result, err := h.authService.LoginUser(&credentials, userAgent, ipAddress)
 	if err != nil {
 		return err
 	}
 
-	// Create auth response
-	profile := models.UserProfile{
-		ID:        user.ID,
-		Email:     user.Email,
-		FullName:  user.FullName,
-		CreatedAt: user.CreatedAt,
-		UpdatedAt: user.UpdatedAt,
-		LastLogin: user.LastLogin,
-	}
-
-	response := models.AuthResponse{
-		User:  profile,
-		Token: token,
+	// Two-factor users must complete the challenge at /api/auth/2fa/verify
+	if result.Challenge != nil {
+		slog.Info("Login awaiting second factor", "user_id", result.User.ID)
+		return c.JSON(result.Challenge)
 	}
 
-	slog.Info("User logged in", "user_id", user.ID, "email", user.Email)
+	slog.Info("User logged in", "user_id", result.User.ID, "email", result.User.Email)
 
-	return c.JSON(response)
+	return c.JSON(newAuthResponse(result.User, result.Token))
 }
 
 // HandleLogout handles user logout
@@ -216,6 +216,11 @@ func (h *AuthHandler) HandleCheckEmail(c *fiber.Ctx) error {
 		return errors.New(errors.ErrValidationFailed, "Invalid email format")
 	}
 
+	// With verification on, signup must not double as an account enumeration oracle
+	if h.authService.EmailVerificationEnabled() && !h.isAuthenticated(c) {
+		return errors.New(errors.ErrUnauthorized, "Authentication required to check email availability")
+	}
+
 	exists, err := h.authService.GetDB().CheckEmailExists(email)
 	if err != nil {
 		return err
@@ -226,6 +231,71 @@ func (h *AuthHandler) HandleCheckEmail(c *fiber.Ctx) error {
 	})
 }
 
+// HandleVerifyEmail confirms an email address from the signed token sent at signup
+func (h *AuthHandler) HandleVerifyEmail(c *fiber.Ctx) error {
+	var req models.EmailVerification
+
+	// Parse request body
+	if err := c.BodyParser(&req); err != nil {
+		slog.Debug("Failed to parse email verification request", "error", err)
+		return errors.New(errors.ErrBadRequest, "Invalid request body")
+	}
+
+	if err := h.authService.VerifyEmail(req.Token); err != nil {
+		return err
+	}
+
+	return c.JSON(fiber.Map{
+		"message": "Email address verified",
+	})
+}
+
+// HandleResendVerification sends a fresh verification link to the authenticated user
+func (h *AuthHandler) HandleResendVerification(c *fiber.Ctx) error {
+	// Get authenticated user
+	user, err := auth.GetUserFromContext(c)
+	if err != nil {
+		return err
+	}
+
+	if err := h.authService.SendVerificationEmail(c.Context(), user); err != nil {
+		return err
+	}
+
+	return c.JSON(fiber.Map{
+		"message": "Verification email sent",
+	})
+}
+
+// isAuthenticated reports whether the request carries a valid session token
+// Used by public endpoints whose answer depends on the caller
+func (h *AuthHandler) isAuthenticated(c *fiber.Ctx) bool {
+	token, err := auth.ExtractBearerToken(c.Get("Authorization"))
+	if err != nil {
+		return false
+	}
+
+	_, err = h.authService.ValidateSession(token)
+	return err == nil
+}
+
+// newAuthResponse builds the response returned once a session has been issued
+func newAuthResponse(user *models.User, token string) models.AuthResponse {
+	return models.AuthResponse{
+		User: models.UserProfile{
+			ID:               user.ID,
+			Email:            user.Email,
+			FullName:         user.FullName,
+			CreatedAt:        user.CreatedAt,
+			UpdatedAt:        user.UpdatedAt,
+			LastLogin:        user.LastLogin,
+			TwoFactorEnabled: user.TwoFactorEnabled,
+			EmailVerified:    user.EmailVerified,
+		},
+		Token: token,
+	}
+}
+
 // Validation functions
 
 func validateUserSignup(signup *models.UserSignup) error {
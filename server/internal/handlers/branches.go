@@ -0,0 +1,257 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+
+	"article-chat-system/server/internal/auth"
+	"article-chat-system/server/internal/errors"
+	"article-chat-system/server/internal/models"
+	"article-chat-system/server/internal/services"
+	"article-chat-system/server/internal/validation"
+)
+
+// Conversation branching
+//
+// Editing a user message or regenerating an answer never overwrites history: the new
+// message is stored as a sibling and becomes the conversation's active branch. Chat history,
+// exports and share links follow the active branch; the endpoints below list and switch branches.
+
+// HandleEditMessage re-asks an earlier user message with new text on a new branch
+// POST /api/conversations/:id/messages/:messageId/edit
+func (h *ChatHandler) HandleEditMessage(c *fiber.Ctx) error {
+	user, err := auth.GetUserFromContext(c)
+	if err != nil {
+		return h.errorResponse(c, err)
+	}
+
+	var edit models.MessageEdit
+	if err := c.BodyParser(&edit); err != nil {
+		slog.Debug("Failed to parse message edit request", "error", err)
+		return h.errorResponse(c, errors.New(errors.ErrBadRequest, "Invalid request body"))
+	}
+
+	edit.Message = validation.SanitizeString(edit.Message)
+	if err := validation.ValidateChatRequest(edit.Message, ""); err != nil {
+		return h.errorResponse(c, err)
+	}
+
+	conversationID, original, err := h.loadBranchMessage(c, user, "user")
+	if err != nil {
+		return h.errorResponse(c, err)
+	}
+
+	ctx, cancel := context.WithTimeout(c.Context(), 2*time.Minute)
+	defer cancel()
+
+	// History is everything before the edited message, followed by the new text
+	history := []models.ChatMessage{}
+	summary := ""
+	if original.ParentID != nil {
+		previous, err := h.db.GetMessagePath(ctx, *original.ParentID)
+		if err != nil {
+			return h.errorResponse(c, err)
+		}
+		var recent []models.Message
+		recent, summary = h.history.Build(ctx, conversationID, previous)
+		history = convertDBMessagesToChatMessages(recent)
+	}
+	history = append(history, models.ChatMessage{
+		ID:        uuid.New().String(),
+		Role:      "user",
+		Content:   edit.Message,
+		Timestamp: time.Now(),
+	})
+
+	response, err := h.ragClient.ProcessChat(ctx, edit.Message, conversationID.String(), history, summary)
+	if err != nil {
+		slog.Error("RAG service failed", "error", err, "query", edit.Message)
+		return h.errorResponse(c, mapRAGError(err))
+	}
+
+	userMessage, assistantMessage, err := h.db.CreateBranchPair(ctx, conversationID, original.ParentID, edit.Message, response.Message, assistantMetadata(response))
+	if err != nil {
+		return h.errorResponse(c, err)
+	}
+	response.ConversationID = conversationID.String()
+	h.history.ScheduleSummary(conversationID)
+
+	slog.Info("Message edited on new branch",
+		"conversation_id", conversationID,
+		"user_id", user.ID,
+		"edited_message_id", original.ID,
+		"user_message_id", userMessage.ID)
+
+	return c.JSON(models.BranchResponse{
+		ChatResponse:       *response,
+		UserMessageID:      userMessage.ID,
+		AssistantMessageID: assistantMessage.ID,
+	})
+}
+
+// HandleRegenerateMessage asks for a new answer to the same question on a new branch
+// POST /api/conversations/:id/messages/:messageId/regenerate
+func (h *ChatHandler) HandleRegenerateMessage(c *fiber.Ctx) error {
+	user, err := auth.GetUserFromContext(c)
+	if err != nil {
+		return h.errorResponse(c, err)
+	}
+
+	conversationID, answer, err := h.loadBranchMessage(c, user, "assistant")
+	if err != nil {
+		return h.errorResponse(c, err)
+	}
+	if answer.ParentID == nil {
+		return h.errorResponse(c, errors.New(errors.ErrValidationFailed, "Message has no question to regenerate from"))
+	}
+
+	ctx, cancel := context.WithTimeout(c.Context(), 2*time.Minute)
+	defer cancel()
+
+	// History ends with the question being answered again
+	path, err := h.db.GetMessagePath(ctx, *answer.ParentID)
+	if err != nil {
+		return h.errorResponse(c, err)
+	}
+	question := path[len(path)-1]
+
+	// A cached answer would just repeat the one being replaced
+	cacheKey := services.ChatCacheKey(question.Content, conversationID)
+	if err := h.cache.Delete(ctx, cacheKey); err != nil {
+		slog.Warn("Failed to evict cached response", "error", err, "cache_key", cacheKey[:8]+"...")
+	}
+
+	recent, summary := h.history.Build(ctx, conversationID, path[:len(path)-1])
+	history := convertDBMessagesToChatMessages(append(recent, question))
+
+	response, err := h.ragClient.ProcessChat(ctx, question.Content, conversationID.String(), history, summary)
+	if err != nil {
+		slog.Error("RAG service failed", "error", err, "query", question.Content)
+		return h.errorResponse(c, mapRAGError(err))
+	}
+
+	reply, err := h.db.CreateRegeneratedReply(ctx, conversationID, question.ID, response.Message, assistantMetadata(response))
+	if err != nil {
+		return h.errorResponse(c, err)
+	}
+	response.ConversationID = conversationID.String()
+	h.history.ScheduleSummary(conversationID)
+
+	slog.Info("Answer regenerated on new branch",
+		"conversation_id", conversationID,
+		"user_id", user.ID,
+		"replaced_message_id", answer.ID,
+		"assistant_message_id", reply.ID)
+
+	return c.JSON(models.BranchResponse{
+		ChatResponse:       *response,
+		UserMessageID:      question.ID,
+		AssistantMessageID: reply.ID,
+	})
+}
+
+// HandleListMessageBranches lists the alternatives of a message and marks the active one
+// GET /api/conversations/:id/messages/:messageId/branches
+func (h *ChatHandler) HandleListMessageBranches(c *fiber.Ctx) error {
+	user, err := auth.GetUserFromContext(c)
+	if err != nil {
+		return h.errorResponse(c, err)
+	}
+
+	conversationID, message, err := h.loadBranchMessage(c, user, "")
+	if err != nil {
+		return h.errorResponse(c, err)
+	}
+
+	siblings, err := h.db.GetMessageSiblings(c.Context(), message.ID)
+	if err != nil {
+		return h.errorResponse(c, err)
+	}
+
+	active, err := h.db.GetConversationMessages(c.Context(), conversationID)
+	if err != nil {
+		return h.errorResponse(c, err)
+	}
+
+	// At most one sibling lies on the active branch
+	var activeID *uuid.UUID
+	onActivePath := make(map[uuid.UUID]bool, len(active))
+	for _, msg := range active {
+		onActivePath[msg.ID] = true
+	}
+	for i := range siblings {
+		if onActivePath[siblings[i].ID] {
+			activeID = &siblings[i].ID
+			break
+		}
+	}
+
+	return c.JSON(fiber.Map{
+		"branches":          siblings,
+		"active_message_id": activeID,
+	})
+}
+
+// HandleSwitchBranch makes the branch through a message active and returns its messages
+// POST /api/conversations/:id/messages/:messageId/activate
+func (h *ChatHandler) HandleSwitchBranch(c *fiber.Ctx) error {
+	user, err := auth.GetUserFromContext(c)
+	if err != nil {
+		return h.errorResponse(c, err)
+	}
+
+	conversationID, message, err := h.loadBranchMessage(c, user, "")
+	if err != nil {
+		return h.errorResponse(c, err)
+	}
+
+	leafID, err := h.db.SetActiveBranch(c.Context(), conversationID, message.ID)
+	if err != nil {
+		return h.errorResponse(c, err)
+	}
+
+	messages, err := h.db.GetConversationMessages(c.Context(), conversationID)
+	if err != nil {
+		return h.errorResponse(c, err)
+	}
+
+	return c.JSON(fiber.Map{
+		"active_leaf_id": leafID,
+		"messages":       messages,
+	})
+}
+
+// loadBranchMessage resolves the :id and :messageId params for a conversation the user owns
+// When role is set the message must have that role
+func (h *ChatHandler) loadBranchMessage(c *fiber.Ctx, user *models.User, role string) (uuid.UUID, *models.Message, error) {
+	conversationID, err := parseUUIDParam(c, "id")
+	if err != nil {
+		return uuid.Nil, nil, err
+	}
+	messageID, err := parseUUIDParam(c, "messageId")
+	if err != nil {
+		return uuid.Nil, nil, err
+	}
+
+	if err := h.db.CheckConversationOwnership(c.Context(), conversationID, user.ID); err != nil {
+		return uuid.Nil, nil, err
+	}
+
+	message, err := h.db.GetMessage(c.Context(), messageID)
+	if err != nil {
+		return uuid.Nil, nil, err
+	}
+	if message.ConversationID != conversationID {
+		return uuid.Nil, nil, errors.New(errors.ErrResourceNotFound, "Message not found")
+	}
+	if role != "" && message.Role != role {
+		return uuid.Nil, nil, errors.New(errors.ErrValidationFailed, fmt.Sprintf("Only %s messages can be used here", role))
+	}
+
+	return conversationID, message, nil
+}
@@ -60,20 +60,26 @@ import (
 // ChatHandler handles all chat-related HTTP endpoints
 // Dependencies injected for clean architecture and testability
 type ChatHandler struct {
-	ragClient *services.RAGClient   // HTTP client for Node.js RAG service communication
-	cache     services.CacheService // Redis cache with memory fallback for performance
-	db        *database.DB          // Database for conversation persistence
+	ragClient *services.RAGClient      // HTTP client for Node.js RAG service communication
+	cache     services.CacheService    // Redis cache with memory fallback for performance
+	db        *database.DB             // Database for conversation persistence
+	history   *services.HistoryWindow  // Bounds the history sent with each question
+	titles    *services.TitleGenerator // Titles new conversations in the background; nil when disabled
 }
 
 // NewChatHandler creates a new chat handler with required dependencies
 // ragClient: HTTP client for communicating with Node.js RAG service
 // cache: Caching service (Redis primary, memory fallback) for performance optimization
 // db: Database for conversation persistence
-func NewChatHandler(ragClient *services.RAGClient, cache services.CacheService, db *database.DB) *ChatHandler {
+// history: Selects the recent messages and summary sent to the RAG service
+// titles: Generates titles after a conversation's first answer (nil keeps the titles cut from the first message)
+func NewChatHandler(ragClient *services.RAGClient, cache services.CacheService, db *database.DB, history *services.HistoryWindow, titles *services.TitleGenerator) *ChatHandler {
 	return &ChatHandler{
 		ragClient: ragClient,
 		cache:     cache,
 		db:        db,
+		history:   history,
+		titles:    titles,
 	}
 }
 
@@ -148,6 +154,7 @@ func (h *ChatHandler) HandleChat(c *fiber.Ctx) error {
 	// STEP 7: CONVERSATION PERSISTENCE SETUP
 	var persistentConversationID uuid.UUID
 	var conversationHistory []models.ChatMessage
+	var conversationSummary string
 
 	if isAuthenticated {
 		// Handle conversation persistence for authenticated users
@@ -157,9 +164,11 @@ func (h *ChatHandler) HandleChat(c *fiber.Ctx) error {
 				// Verify user owns this conversation
 				if err := h.db.CheckConversationOwnership(ctx, parsedID, user.ID); err == nil {
 					persistentConversationID = parsedID
-					// Load existing conversation history
+					// Load the recent conversation history and the summary of older turns
 					if messages, err := h.db.GetConversationMessages(ctx, parsedID); err == nil {
-						conversationHistory = convertDBMessagesToChatMessages(messages)
+						var recent []models.Message
+						recent, conversationSummary = h.history.Build(ctx, parsedID, messages)
+						conversationHistory = convertDBMessagesToChatMessages(recent)
 					}
 				} else {
 					slog.Warn("User attempted to access conversation they don't own",
@@ -195,13 +204,12 @@ func (h *ChatHandler) HandleChat(c *fiber.Ctx) error {
 
 	// STEP 8: STREAMING VS REGULAR RESPONSE HANDLING
 	if req.Stream {
-		return h.handleStreamingChat(c, ctx, req, conversationHistory, isAuthenticated, user, persistentConversationID)
+		return h.handleStreamingChat(c, ctx, req, conversationHistory, conversationSummary, isAuthenticated, user, persistentConversationID)
 	}
 
 	// STEP 9: INTELLIGENT CACHING LOGIC
 	// Generate cache key from normalized message + conversation context
-	conversationContext := fmt.Sprintf("conv_%s", req.ConversationID)
-	cacheKey := services.GenerateCacheKey(req.Message, conversationContext)
+	cacheKey := services.ChatCacheKey(req.Message, persistentConversationID)
 
 	// Check cache for existing response (includes text normalization)
 	var cachedResponse models.ChatResponse
@@ -215,7 +223,7 @@ func (h *ChatHandler) HandleChat(c *fiber.Ctx) error {
 
 		// Even for cached responses, persist to database if authenticated
 		if isAuthenticated {
-			go h.persistCachedConversation(ctx, persistentConversationID, user.ID, req.Message, cachedResponse.Message)
+			go h.persistCachedConversation(persistentConversationID, user.ID, req.Message, cachedResponse)
 		}
 
 		return c.JSON(cachedResponse)
@@ -227,28 +235,10 @@ func (h *ChatHandler) HandleChat(c *fiber.Ctx) error {
 
 	// STEP 10: RAG SERVICE PROCESSING
 	// Forward to Node.js service for LangChain + Claude + vector search processing
-	response, err := h.ragClient.ProcessChat(ctx, req.Message, req.ConversationID, conversationHistory)
+	response, err := h.ragClient.ProcessChat(ctx, req.Message, req.ConversationID, conversationHistory, conversationSummary)
 	if err != nil {
 		slog.Error("RAG service failed", "error", err, "query", req.Message)
-
-		// Map service errors to user-friendly messages
-		if strings.Contains(err.Error(), "rag service error") {
-			return h.errorResponse(c, errors.New(
-				errors.ErrRAGServiceError,
-				"RAG service is temporarily unavailable",
-			))
-		}
-		if strings.Contains(err.Error(), "timeout") {
-			return h.errorResponse(c, errors.New(
-				errors.ErrServiceUnavailable,
-				"Request timed out, please try again",
-			))
-		}
-
-		return h.errorResponse(c, errors.New(
-			errors.ErrProcessingError,
-			"Failed to process your question",
-		))
+		return h.errorResponse(c, mapRAGError(err))
 	}
 
 	// STEP 11: CONVERSATION PERSISTENCE
@@ -282,6 +272,27 @@ func (h *ChatHandler) HandleChat(c *fiber.Ctx) error {
 	return c.JSON(response)
 }
 
+// mapRAGError maps RAG service failures to user-friendly errors
+func mapRAGError(err error) error {
+	if strings.Contains(err.Error(), "rag service error") {
+		return errors.New(
+			errors.ErrRAGServiceError,
+			"RAG service is temporarily unavailable",
+		)
+	}
+	if strings.Contains(err.Error(), "timeout") {
+		return errors.New(
+			errors.ErrServiceUnavailable,
+			"Request timed out, please try again",
+		)
+	}
+
+	return errors.New(
+		errors.ErrProcessingError,
+		"Failed to process your question",
+	)
+}
+
 // errorResponse sends a standardized error response
 func (h *ChatHandler) errorResponse(c *fiber.Ctx, err error) error {
 	requestID := c.Get("X-Request-ID")
@@ -307,14 +318,14 @@ func (h *ChatHandler) errorResponse(c *fiber.Ctx, err error) error {
 	})
 }
 
-func (h *ChatHandler) handleStreamingChat(c *fiber.Ctx, ctx context.Context, req models.ChatRequest, history []models.ChatMessage, isAuthenticated bool, user *models.User, persistentConversationID uuid.UUID) error {
+func (h *ChatHandler) handleStreamingChat(c *fiber.Ctx, ctx context.Context, req models.ChatRequest, history []models.ChatMessage, summary string, isAuthenticated bool, user *models.User, persistentConversationID uuid.UUID) error {
 	c.Set("Content-Type", "text/event-stream")
 	c.Set("Cache-Control", "no-cache")
 	c.Set("Connection", "keep-alive")
 	c.Set("Access-Control-Allow-Origin", "*")
 
 	// Start streaming
-	responseChan, err := h.ragClient.ProcessChatStream(ctx, req.Message, req.ConversationID, history)
+	responseChan, err := h.ragClient.ProcessChatStream(ctx, req.Message, req.ConversationID, history, summary)
 	if err != nil {
 		slog.Error("Failed to start streaming", "error", err)
 		return h.errorResponse(c, errors.New(
@@ -362,7 +373,7 @@ func (h *ChatHandler) handleStreamingChat(c *fiber.Ctx, ctx context.Context, req
 // persistConversation saves a new conversation and message pair to the database
 func (h *ChatHandler) persistConversation(ctx context.Context, conversationID uuid.UUID, userID uuid.UUID, userMessage string, response *models.ChatResponse) error {
 	// Create conversation if it doesn't exist
-	_, err := h.db.GetConversation(ctx, conversationID)
+	conv, err := h.db.GetConversation(ctx, conversationID)
 	if err != nil {
 		// Conversation doesn't exist, create it with the specific ID
 		title := database.GenerateConversationTitle(userMessage)
@@ -371,7 +382,31 @@ func (h *ChatHandler) persistConversation(ctx context.Context, conversationID uu
 		}
 	}
 
-	// Prepare metadata for assistant message
+	// Save both messages in a transaction
+	if _, _, err := h.db.CreateMessagePair(ctx, conversationID, userMessage, response.Message, assistantMetadata(response)); err != nil {
+		return err
+	}
+
+	h.scheduleTitle(conversationID, conv, userMessage, response.Message)
+	h.history.ScheduleSummary(conversationID)
+	return nil
+}
+
+// scheduleTitle queues title generation after the first exchange of a conversation
+// conv is the conversation as loaded before the exchange was stored, nil if it was just created
+func (h *ChatHandler) scheduleTitle(conversationID uuid.UUID, conv *models.Conversation, userMessage, answer string) {
+	if h.titles == nil {
+		return
+	}
+	if conv != nil && (conv.MessageCount > 0 || conv.TitleManual) {
+		return
+	}
+
+	h.titles.Schedule(conversationID, userMessage, answer)
+}
+
+// assistantMetadata prepares the stored metadata for an assistant message
+func assistantMetadata(response *models.ChatResponse) map[string]interface{} {
 	metadata := map[string]interface{}{
 		"processing_time_ms": response.ProcessingTime,
 		"tokens_used":        response.TokensUsed,
@@ -383,33 +418,41 @@ func (h *ChatHandler) persistConversation(ctx context.Context, conversationID uu
 		metadata["sources"] = response.Sources
 	}
 
-	// Save both messages in a transaction
-	_, _, err = h.db.CreateMessagePair(ctx, conversationID, userMessage, response.Message, metadata)
-	return err
+	return metadata
 }
 
 // persistCachedConversation handles persistence for cached responses (runs in goroutine)
-func (h *ChatHandler) persistCachedConversation(ctx context.Context, conversationID uuid.UUID, userID uuid.UUID, userMessage string, assistantMessage string) {
+// The cached response's sources are stored too, so feedback on cached answers can be traced to articles
+func (h *ChatHandler) persistCachedConversation(conversationID uuid.UUID, userID uuid.UUID, userMessage string, response models.ChatResponse) {
 	// Create a new context with timeout for the background operation
 	bgCtx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 	defer cancel()
 
 	// Create conversation if it doesn't exist
-	_, err := h.db.GetConversation(bgCtx, conversationID)
+	conv, err := h.db.GetConversation(bgCtx, conversationID)
 	if err != nil {
 		title := database.GenerateConversationTitle(userMessage)
-		_, err = h.db.CreateConversation(bgCtx, userID, title)
+		_, err = h.db.CreateConversation(bgCtx, userID, title, false)
 		if err != nil {
 			h.createConversationWithID(bgCtx, conversationID, userID, title)
 		}
 	}
 
-	// Save messages with cached flag
+	// Save messages with cached flag; tokens and timings belong to the original answer
 	metadata := map[string]interface{}{
-		"cached": true,
+		"cached":        true,
+		"sources_count": len(response.Sources),
+	}
+	if len(response.Sources) > 0 {
+		metadata["sources"] = response.Sources
+	}
+
+	if _, _, err := h.db.CreateMessagePair(bgCtx, conversationID, userMessage, response.Message, metadata); err != nil {
+		return
 	}
 
-	h.db.CreateMessagePair(bgCtx, conversationID, userMessage, assistantMessage, metadata)
+	h.scheduleTitle(conversationID, conv, userMessage, response.Message)
+	h.history.ScheduleSummary(conversationID)
 }
 
 // createConversationWithID creates a conversation with a specific ID (fallback method)
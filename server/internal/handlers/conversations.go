@@ -1,9 +1,17 @@
 package handlers
 
 import (
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"html"
+	"io"
 	"log/slog"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/google/uuid"
@@ -11,6 +19,7 @@ import (
 	"article-chat-system/server/internal/auth"
 	"article-chat-system/server/internal/database"
 	"article-chat-system/server/internal/errors"
+	"article-chat-system/server/internal/export"
 	"article-chat-system/server/internal/models"
 )
 
@@ -26,7 +35,10 @@ func NewConversationHandler(db *database.DB) *ConversationHandler {
 	}
 }
 
-// HandleListConversations returns all conversations for the authenticated user
+// HandleListConversations returns the authenticated user's conversations, pinned first
+// Query parameters: archived (exclude|only|include, default exclude), pinned (true|false),
+// folder (ID or "none"), tags (comma separated IDs, all required), sort (updated_at|created_at|title|message_count),
+// order (asc|desc), limit, and cursor (from next_cursor or prev_cursor) or offset
 func (h *ConversationHandler) HandleListConversations(c *fiber.Ctx) error {
 	// Get authenticated user
 	user, err := auth.GetUserFromContext(c)
@@ -34,31 +46,83 @@ func (h *ConversationHandler) HandleListConversations(c *fiber.Ctx) error {
 		return err
 	}
 
-	// Parse pagination parameters
-	limit, offset, err := parsePaginationParams(c)
+	// Parse filter, sort and pagination parameters
+	filter, err := parseConversationFilter(c)
 	if err != nil {
 		return err
 	}
 
 	// Get conversations
-	conversations, err := h.db.GetUserConversations(c.Context(), user.ID, limit, offset)
+	conversations, cursors, err := h.db.GetUserConversations(c.Context(), user.ID, filter)
 	if err != nil {
 		return err
 	}
 
 	// Get total count for pagination
-	totalCount, err := h.db.GetConversationCount(c.Context(), user.ID)
+	totalCount, err := h.db.GetConversationCount(c.Context(), user.ID, filter)
 	if err != nil {
 		return err
 	}
 
+	pagination := fiber.Map{
+		"limit":       filter.Limit,
+		"total_count": totalCount,
+		"has_more":    cursors.Next != "",
+		"next_cursor": cursors.Next,
+		"prev_cursor": cursors.Prev,
+	}
+	if filter.Cursor == "" {
+		pagination["offset"] = filter.Offset
+	}
+
 	return c.JSON(fiber.Map{
 		"conversations": conversations,
+		"pagination":    pagination,
+	})
+}
+
+// HandleSearchConversations runs a full-text search over the user's message history
+// Query parameters: q (required), role (user|assistant), from and to (RFC 3339 or YYYY-MM-DD)
+func (h *ConversationHandler) HandleSearchConversations(c *fiber.Ctx) error {
+	// Get authenticated user
+	user, err := auth.GetUserFromContext(c)
+	if err != nil {
+		return err
+	}
+
+	search, err := parseMessageSearch(c)
+	if err != nil {
+		return err
+	}
+
+	// Parse pagination parameters
+	limit, offset, err := parsePaginationParams(c)
+	if err != nil {
+		return err
+	}
+
+	// Fetch one extra hit to know whether another page exists
+	hits, err := h.db.SearchMessages(c.Context(), user.ID, search, limit+1, offset)
+	if err != nil {
+		return err
+	}
+
+	hasMore := len(hits) > limit
+	if hasMore {
+		hits = hits[:limit]
+	}
+
+	for i := range hits {
+		hits[i].Snippet = highlightSnippet(hits[i].Snippet)
+	}
+
+	return c.JSON(fiber.Map{
+		"query":   search.Query,
+		"results": hits,
 		"pagination": fiber.Map{
-			"limit":       limit,
-			"offset":      offset,
-			"total_count": totalCount,
-			"has_more":    offset+len(conversations) < totalCount,
+			"limit":    limit,
+			"offset":   offset,
+			"has_more": hasMore,
 		},
 	})
 }
@@ -80,13 +144,15 @@ func (h *ConversationHandler) HandleCreateConversation(c *fiber.Ctx) error {
 	}
 
 	// Validate and set title
+	// A title given here is the user's choice and is never replaced automatically
 	title := strings.TrimSpace(create.Title)
-	if title == "" {
+	manual := title != ""
+	if !manual {
 		title = "New Conversation"
 	}
 
 	// Create conversation
-	conversation, err := h.db.CreateConversation(c.Context(), user.ID, title)
+	conversation, err := h.db.CreateConversation(c.Context(), user.ID, title, manual)
 	if err != nil {
 		return err
 	}
@@ -124,7 +190,169 @@ func (h *ConversationHandler) HandleGetConversation(c *fiber.Ctx) error {
 	return c.JSON(conversation)
 }
 
+// HandleExportConversation downloads a single conversation as ?format=md|json|html (default md)
+func (h *ConversationHandler) HandleExportConversation(c *fiber.Ctx) error {
+	// Get authenticated user
+	user, err := auth.GetUserFromContext(c)
+	if err != nil {
+		return err
+	}
+
+	// Parse conversation ID
+	conversationID, err := parseUUIDParam(c, "id")
+	if err != nil {
+		return err
+	}
+
+	format, err := parseExportFormat(c, export.FormatMarkdown)
+	if err != nil {
+		return err
+	}
+
+	// Check if user owns the conversation
+	if err := h.db.CheckConversationOwnership(c.Context(), conversationID, user.ID); err != nil {
+		return err
+	}
+
+	conversation, err := h.db.GetConversationWithMessages(c.Context(), conversationID)
+	if err != nil {
+		return err
+	}
+
+	c.Set(fiber.HeaderContentType, export.ContentType(format))
+	c.Attachment(export.Filename(&conversation.Conversation, format))
+
+	slog.Info("Conversation exported", "conversation_id", conversationID, "user_id", user.ID, "format", format)
+
+	return export.Write(c.Response().BodyWriter(), conversation, format)
+}
+
+// HandleExportAllConversations streams a zip archive of every conversation the user owns
+// Each conversation becomes one file in ?format=md|json|html (default json, which can be re-imported)
+func (h *ConversationHandler) HandleExportAllConversations(c *fiber.Ctx) error {
+	// Get authenticated user
+	user, err := auth.GetUserFromContext(c)
+	if err != nil {
+		return err
+	}
+
+	format, err := parseExportFormat(c, export.FormatJSON)
+	if err != nil {
+		return err
+	}
+
+	// Collect IDs up front so errors can still be reported as a normal JSON response
+	// Archived conversations are part of a full export
+	var conversations []models.Conversation
+	filter := &models.ConversationFilter{Archived: models.ArchivedInclude, Sort: "created_at", Limit: 100}
+	for {
+		page, cursors, err := h.db.GetUserConversations(c.Context(), user.ID, filter)
+		if err != nil {
+			return err
+		}
+		conversations = append(conversations, page...)
+		if cursors.Next == "" {
+			break
+		}
+		filter.Cursor = cursors.Next
+	}
+
+	c.Set(fiber.HeaderContentType, "application/zip")
+	c.Attachment(fmt.Sprintf("conversations-%s.zip", time.Now().Format(time.DateOnly)))
+
+	slog.Info("Exporting all conversations", "user_id", user.ID, "format", format, "count", len(conversations))
+
+	// The body is produced after the handler returns, so the request context cannot be used
+	c.Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+		defer cancel()
+
+		archive := zip.NewWriter(w)
+		for i := range conversations {
+			conversation, err := h.db.GetConversationWithMessages(ctx, conversations[i].ID)
+			if err != nil {
+				// Deleted mid-export or database failure: skip it rather than truncate the archive
+				slog.Warn("Skipping conversation in export", "conversation_id", conversations[i].ID, "error", err)
+				continue
+			}
+
+			file, err := archive.CreateHeader(&zip.FileHeader{
+				Name:     export.Filename(&conversation.Conversation, format),
+				Method:   zip.Deflate,
+				Modified: conversation.UpdatedAt,
+			})
+			if err != nil {
+				slog.Error("Failed to write export archive", "user_id", user.ID, "error", err)
+				return
+			}
+			if err := export.Write(file, conversation, format); err != nil {
+				slog.Error("Failed to write export archive", "user_id", user.ID, "error", err)
+				return
+			}
+		}
+
+		if err := archive.Close(); err != nil {
+			slog.Error("Failed to finish export archive", "user_id", user.ID, "error", err)
+			return
+		}
+		w.Flush()
+	})
+
+	return nil
+}
+
+// maxImportConversations bounds a single import request
+const maxImportConversations = 1000
+
+// HandleImportConversations recreates conversations from JSON exports under the authenticated user
+// Accepts a JSON document, an array of documents, or the zip from the bulk export, either as the
+// raw request body or as a multipart "file" upload
+func (h *ConversationHandler) HandleImportConversations(c *fiber.Ctx) error {
+	// Get authenticated user
+	user, err := auth.GetUserFromContext(c)
+	if err != nil {
+		return err
+	}
+
+	data, err := readImportPayload(c)
+	if err != nil {
+		return err
+	}
+
+	var docs []export.Document
+	if bytes.HasPrefix(data, []byte("PK\x03\x04")) {
+		docs, err = export.ReadArchive(data, maxImportConversations)
+	} else {
+		docs, err = export.ParseDocuments(data)
+	}
+	if err != nil {
+		return errors.New(errors.ErrValidationFailed, err.Error())
+	}
+
+	if len(docs) > maxImportConversations {
+		return errors.New(errors.ErrValidationFailed, fmt.Sprintf("At most %d conversations can be imported at once", maxImportConversations))
+	}
+
+	conversations, err := export.Conversations(docs)
+	if err != nil {
+		return errors.New(errors.ErrValidationFailed, err.Error())
+	}
+
+	result, err := h.db.ImportConversations(c.Context(), user.ID, conversations)
+	if err != nil {
+		return err
+	}
+
+	slog.Info("Conversations imported",
+		"user_id", user.ID,
+		"imported", len(result.Imported),
+		"duplicates", len(result.Duplicates))
+
+	return c.Status(fiber.StatusCreated).JSON(result)
+}
+
 // HandleGetConversationMessages returns messages for a conversation with pagination
+// Query parameters: limit, and cursor (from next_cursor or prev_cursor) or offset
 func (h *ConversationHandler) HandleGetConversationMessages(c *fiber.Ctx) error {
 	// Get authenticated user
 	user, err := auth.GetUserFromContext(c)
@@ -150,7 +378,8 @@ func (h *ConversationHandler) HandleGetConversationMessages(c *fiber.Ctx) error
 	}
 
 	// Get messages
-	messages, err := h.db.GetConversationMessagesPaginated(c.Context(), conversationID, limit, offset)
+	cursor := c.Query("cursor")
+	messages, cursors, err := h.db.GetConversationMessagesPaginated(c.Context(), conversationID, limit, offset, cursor)
 	if err != nil {
 		return err
 	}
@@ -161,14 +390,20 @@ func (h *ConversationHandler) HandleGetConversationMessages(c *fiber.Ctx) error
 		return err
 	}
 
+	pagination := fiber.Map{
+		"limit":       limit,
+		"total_count": totalCount,
+		"has_more":    cursors.Next != "",
+		"next_cursor": cursors.Next,
+		"prev_cursor": cursors.Prev,
+	}
+	if cursor == "" {
+		pagination["offset"] = offset
+	}
+
 	return c.JSON(fiber.Map{
-		"messages": messages,
-		"pagination": fiber.Map{
-			"limit":       limit,
-			"offset":      offset,
-			"total_count": totalCount,
-			"has_more":    offset+len(messages) < totalCount,
-		},
+		"messages":   messages,
+		"pagination": pagination,
 	})
 }
 
@@ -205,7 +440,7 @@ func (h *ConversationHandler) HandleUpdateConversation(c *fiber.Ctx) error {
 	}
 
 	// Update conversation
-	conversation, err := h.db.UpdateConversation(c.Context(), conversationID, update.Title)
+	conversation, err := h.db.UpdateConversation(c.Context(), conversationID, update.Title, true)
 	if err != nil {
 		return err
 	}
@@ -270,6 +505,76 @@ func parsePaginationParams(c *fiber.Ctx) (limit, offset int, err error) {
 	return limit, offset, nil
 }
 
+// parseConversationFilter parses the list filters, sort and pagination of HandleListConversations
+func parseConversationFilter(c *fiber.Ctx) (*models.ConversationFilter, error) {
+	limit, offset, err := parsePaginationParams(c)
+	if err != nil {
+		return nil, err
+	}
+
+	filter := &models.ConversationFilter{
+		Archived: c.Query("archived", models.ArchivedExclude),
+		Sort:     c.Query("sort", "updated_at"),
+		Limit:    limit,
+		Offset:   offset,
+		Cursor:   c.Query("cursor"),
+	}
+
+	switch filter.Archived {
+	case models.ArchivedExclude, models.ArchivedOnly, models.ArchivedInclude:
+	default:
+		return nil, errors.New(errors.ErrValidationFailed, "archived must be 'exclude', 'only' or 'include'")
+	}
+
+	if !database.IsConversationSort(filter.Sort) {
+		return nil, errors.New(errors.ErrValidationFailed, "sort must be 'updated_at', 'created_at', 'title' or 'message_count'")
+	}
+
+	// Titles read naturally A-Z; everything else newest or largest first
+	order := "desc"
+	if filter.Sort == "title" {
+		order = "asc"
+	}
+	switch c.Query("order", order) {
+	case "asc":
+		filter.Desc = false
+	case "desc":
+		filter.Desc = true
+	default:
+		return nil, errors.New(errors.ErrValidationFailed, "order must be 'asc' or 'desc'")
+	}
+
+	if pinned := c.Query("pinned"); pinned != "" {
+		value, err := strconv.ParseBool(pinned)
+		if err != nil {
+			return nil, errors.New(errors.ErrInvalidDataType, "pinned must be true or false")
+		}
+		filter.Pinned = &value
+	}
+
+	if folder := c.Query("folder"); folder == "none" {
+		filter.Unfiled = true
+	} else if folder != "" {
+		folderID, err := uuid.Parse(folder)
+		if err != nil {
+			return nil, errors.New(errors.ErrInvalidDataType, "Invalid folder format")
+		}
+		filter.FolderID = &folderID
+	}
+
+	if tags := c.Query("tags"); tags != "" {
+		for _, tag := range strings.Split(tags, ",") {
+			tagID, err := uuid.Parse(strings.TrimSpace(tag))
+			if err != nil {
+				return nil, errors.New(errors.ErrInvalidDataType, "Invalid tags format")
+			}
+			filter.TagIDs = append(filter.TagIDs, tagID)
+		}
+	}
+
+	return filter, nil
+}
+
 // parseUUIDParam parses a UUID parameter from the URL
 func parseUUIDParam(c *fiber.Ctx, paramName string) (uuid.UUID, error) {
 	idStr := c.Params(paramName)
@@ -285,6 +590,98 @@ func parseUUIDParam(c *fiber.Ctx, paramName string) (uuid.UUID, error) {
 	return id, nil
 }
 
+// parseMessageSearch reads and validates history search query parameters
+func parseMessageSearch(c *fiber.Ctx) (*models.MessageSearch, error) {
+	search := &models.MessageSearch{
+		Query: strings.TrimSpace(c.Query("q")),
+		Role:  c.Query("role"),
+	}
+
+	if search.Query == "" {
+		return nil, errors.New(errors.ErrMissingRequiredField, "Search query (q) is required")
+	}
+	if len(search.Query) > 200 {
+		return nil, errors.New(errors.ErrValidationFailed, "Search query must be 200 characters or less")
+	}
+	if search.Role != "" && search.Role != "user" && search.Role != "assistant" {
+		return nil, errors.New(errors.ErrValidationFailed, "Role must be 'user' or 'assistant'")
+	}
+
+	var err error
+	if search.From, err = parseDateParam(c, "from", false); err != nil {
+		return nil, err
+	}
+	if search.To, err = parseDateParam(c, "to", true); err != nil {
+		return nil, err
+	}
+
+	return search, nil
+}
+
+// parseDateParam parses an optional RFC 3339 timestamp or YYYY-MM-DD date
+// With endOfDay set, a plain date covers that whole day (bound becomes the next midnight)
+func parseDateParam(c *fiber.Ctx, name string, endOfDay bool) (*time.Time, error) {
+	value := c.Query(name)
+	if value == "" {
+		return nil, nil
+	}
+
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return &t, nil
+	}
+
+	t, err := time.Parse(time.DateOnly, value)
+	if err != nil {
+		return nil, errors.New(errors.ErrInvalidDataType, "Invalid "+name+" date, expected RFC 3339 or YYYY-MM-DD")
+	}
+	if endOfDay {
+		t = t.AddDate(0, 0, 1)
+	}
+
+	return &t, nil
+}
+
+// highlightSnippet escapes a search snippet and converts the database highlight markers to <mark> tags
+func highlightSnippet(snippet string) string {
+	escaped := html.EscapeString(snippet)
+	escaped = strings.ReplaceAll(escaped, database.SearchHighlightStart, "<mark>")
+	return strings.ReplaceAll(escaped, database.SearchHighlightStop, "</mark>")
+}
+
+// readImportPayload returns the uploaded "file" for multipart requests, otherwise the raw body
+func readImportPayload(c *fiber.Ctx) ([]byte, error) {
+	if !strings.HasPrefix(c.Get(fiber.HeaderContentType), fiber.MIMEMultipartForm) {
+		return c.Body(), nil
+	}
+
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		return nil, errors.New(errors.ErrMissingRequiredField, "Import file is required")
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		return nil, errors.New(errors.ErrBadRequest, "Unable to read import file")
+	}
+	defer file.Close()
+
+	data, err := io.ReadAll(file)
+	if err != nil {
+		return nil, errors.New(errors.ErrBadRequest, "Unable to read import file")
+	}
+
+	return data, nil
+}
+
+// parseExportFormat reads the ?format= query parameter
+func parseExportFormat(c *fiber.Ctx, defaultFormat string) (string, error) {
+	format := strings.ToLower(c.Query("format", defaultFormat))
+	if !export.IsSupported(format) {
+		return "", errors.New(errors.ErrValidationFailed, "Format must be 'md', 'json' or 'html'")
+	}
+	return format, nil
+}
+
 // validateConversationUpdate validates conversation update data
 func validateConversationUpdate(update *models.ConversationUpdate) error {
 	if strings.TrimSpace(update.Title) == "" {
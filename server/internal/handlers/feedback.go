@@ -0,0 +1,194 @@
+package handlers
+
+import (
+	"fmt"
+	"log/slog"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+
+	"article-chat-system/server/internal/auth"
+	"article-chat-system/server/internal/database"
+	"article-chat-system/server/internal/errors"
+	"article-chat-system/server/internal/models"
+	"article-chat-system/server/internal/services"
+	"article-chat-system/server/internal/validation"
+)
+
+const (
+	maxFeedbackComment = 2000 // Characters
+	maxReportArticles  = 200
+)
+
+// FeedbackHandler handles ratings of assistant answers and the admin quality report
+type FeedbackHandler struct {
+	db    *database.DB
+	cache services.CacheService
+}
+
+// NewFeedbackHandler creates a new feedback handler
+// cache is used to evict answers that were rated down
+func NewFeedbackHandler(db *database.DB, cache services.CacheService) *FeedbackHandler {
+	return &FeedbackHandler{
+		db:    db,
+		cache: cache,
+	}
+}
+
+// HandleMessageFeedback stores the user's rating of an assistant message
+// POST /api/conversations/:id/messages/:messageId/feedback
+// Rating again replaces the previous rating. A thumbs down also evicts the cached answer
+// so the question is answered afresh next time.
+func (h *FeedbackHandler) HandleMessageFeedback(c *fiber.Ctx) error {
+	// Get authenticated user
+	user, err := auth.GetUserFromContext(c)
+	if err != nil {
+		return err
+	}
+
+	// Parse conversation and message IDs
+	conversationID, err := parseUUIDParam(c, "id")
+	if err != nil {
+		return err
+	}
+	messageID, err := parseUUIDParam(c, "messageId")
+	if err != nil {
+		return err
+	}
+
+	// Check if user owns the conversation
+	if err := h.db.CheckConversationOwnership(c.Context(), conversationID, user.ID); err != nil {
+		return err
+	}
+
+	message, err := h.db.GetMessage(c.Context(), messageID)
+	if err != nil {
+		return err
+	}
+	if message.ConversationID != conversationID {
+		return errors.New(errors.ErrResourceNotFound, "Message not found")
+	}
+	if message.Role != "assistant" {
+		return errors.New(errors.ErrValidationFailed, "Only assistant messages can be rated")
+	}
+
+	var input models.MessageFeedbackInput
+	if err := c.BodyParser(&input); err != nil {
+		slog.Debug("Failed to parse feedback request", "error", err)
+		return errors.New(errors.ErrBadRequest, "Invalid request body")
+	}
+	if err := normalizeFeedback(&input); err != nil {
+		return err
+	}
+
+	feedback, err := h.db.UpsertMessageFeedback(c.Context(), messageID, user.ID, &input)
+	if err != nil {
+		return err
+	}
+
+	if feedback.Rating == models.FeedbackDown && message.ParentID != nil {
+		h.evictCachedAnswer(c, conversationID, *message)
+	}
+
+	slog.Info("Message feedback recorded",
+		"conversation_id", conversationID,
+		"message_id", messageID,
+		"user_id", user.ID,
+		"rating", feedback.Rating)
+
+	return c.JSON(feedback)
+}
+
+// HandleFeedbackReport aggregates feedback by cited article, cache hit vs. miss, day and reason (admin only)
+// GET /api/admin/feedback/report
+// Query parameters: from and to (RFC 3339 or YYYY-MM-DD, default the last 30 days), limit (articles, default 50)
+func (h *FeedbackHandler) HandleFeedbackReport(c *fiber.Ctx) error {
+	from, err := parseDateParam(c, "from", false)
+	if err != nil {
+		return err
+	}
+	to, err := parseDateParam(c, "to", true)
+	if err != nil {
+		return err
+	}
+
+	if to == nil {
+		now := time.Now()
+		to = &now
+	}
+	if from == nil {
+		start := to.AddDate(0, 0, -30)
+		from = &start
+	}
+	if !from.Before(*to) {
+		return errors.New(errors.ErrValidationFailed, "from must be before to")
+	}
+
+	limit := 50
+	if limitStr := c.Query("limit"); limitStr != "" {
+		limit, err = strconv.Atoi(limitStr)
+		if err != nil || limit < 1 || limit > maxReportArticles {
+			return errors.New(errors.ErrValidationFailed, fmt.Sprintf("Limit must be between 1 and %d", maxReportArticles))
+		}
+	}
+
+	report, err := h.db.GetFeedbackReport(c.Context(), *from, *to, limit)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(report)
+}
+
+// evictCachedAnswer removes the cached chat response for the question an answer replied to
+// Failures are logged only; the rating itself has been stored
+func (h *FeedbackHandler) evictCachedAnswer(c *fiber.Ctx, conversationID uuid.UUID, answer models.Message) {
+	question, err := h.db.GetMessage(c.Context(), *answer.ParentID)
+	if err != nil {
+		slog.Warn("Failed to load question for cache eviction", "error", err, "message_id", answer.ID)
+		return
+	}
+
+	cacheKey := services.ChatCacheKey(question.Content, conversationID)
+	if err := h.cache.Delete(c.Context(), cacheKey); err != nil {
+		slog.Warn("Failed to evict cached response", "error", err, "cache_key", cacheKey[:8]+"...")
+		return
+	}
+
+	slog.Debug("Evicted negatively rated answer from cache", "message_id", answer.ID, "cache_key", cacheKey[:8]+"...")
+}
+
+// normalizeFeedback validates a rating, de-duplicates reason tags and sanitizes the comment
+func normalizeFeedback(input *models.MessageFeedbackInput) error {
+	input.Rating = strings.ToLower(strings.TrimSpace(input.Rating))
+	if input.Rating != models.FeedbackUp && input.Rating != models.FeedbackDown {
+		return errors.New(errors.ErrValidationFailed, "Rating must be 'up' or 'down'")
+	}
+
+	reasons := make([]string, 0, len(input.Reasons))
+	for _, reason := range input.Reasons {
+		reason = strings.ToLower(strings.TrimSpace(reason))
+		if !slices.Contains(models.FeedbackReasons, reason) {
+			return errors.NewWithDetails(errors.ErrValidationFailed, "Unknown feedback reason", map[string]interface{}{
+				"reason":  reason,
+				"allowed": models.FeedbackReasons,
+			})
+		}
+		if !slices.Contains(reasons, reason) {
+			reasons = append(reasons, reason)
+		}
+	}
+	input.Reasons = reasons
+
+	input.Comment = validation.SanitizeString(input.Comment)
+	if utf8.RuneCountInString(input.Comment) > maxFeedbackComment {
+		return errors.New(errors.ErrValidationFailed, fmt.Sprintf("Comment must be at most %d characters", maxFeedbackComment))
+	}
+
+	return nil
+}
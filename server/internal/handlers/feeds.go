@@ -0,0 +1,267 @@
+package handlers
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/gofiber/fiber/v2"
+
+	"article-chat-system/server/internal/auth"
+	"article-chat-system/server/internal/config"
+	"article-chat-system/server/internal/database"
+	"article-chat-system/server/internal/errors"
+	"article-chat-system/server/internal/fetcher"
+	"article-chat-system/server/internal/models"
+	"article-chat-system/server/internal/validation"
+)
+
+const (
+	// maxFeedCategory matches the feeds.category column
+	maxFeedCategory = 50
+	// maxFeedPollInterval is the longest poll interval a feed may request (one week)
+	maxFeedPollInterval = 7 * 24 * 60 * 60
+	// feedCheckTimeout bounds fetching a feed to validate a new subscription
+	feedCheckTimeout = 30 * time.Second
+)
+
+// FeedHandler manages RSS and Atom subscriptions; FeedPoller ingests their entries
+type FeedHandler struct {
+	fetcher     *fetcher.ArticleFetcher
+	db          *database.DB
+	authService *auth.AuthService
+	config      config.FeedConfig
+}
+
+func NewFeedHandler(fetcher *fetcher.ArticleFetcher, db *database.DB, authService *auth.AuthService, cfg config.FeedConfig) *FeedHandler {
+	return &FeedHandler{
+		fetcher:     fetcher,
+		db:          db,
+		authService: authService,
+		config:      cfg,
+	}
+}
+
+// HandleListFeeds returns all feed subscriptions
+func (h *FeedHandler) HandleListFeeds(c *fiber.Ctx) error {
+	feeds, err := h.db.ListFeeds(c.Context())
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(fiber.Map{
+		"feeds": feeds,
+		"total": len(feeds),
+	})
+}
+
+// HandleCreateFeed subscribes to a feed
+// POST /api/feeds
+// The feed is fetched once to check that it parses; its title is used when none is given.
+func (h *FeedHandler) HandleCreateFeed(c *fiber.Ctx) error {
+	user, err := auth.GetUserFromContext(c)
+	if err != nil {
+		return err
+	}
+
+	var req models.CreateFeedRequest
+	if err := c.BodyParser(&req); err != nil {
+		slog.Debug("Failed to parse create feed request", "error", err)
+		return errors.New(errors.ErrBadRequest, "Invalid request body")
+	}
+
+	req.URL = strings.TrimSpace(req.URL)
+	if err := validation.ValidateArticleURL(req.URL); err != nil {
+		return err
+	}
+
+	category, err := validateFeedCategory(req.Category)
+	if err != nil {
+		return err
+	}
+
+	interval := req.PollInterval
+	if interval == 0 {
+		interval = h.config.DefaultInterval
+	}
+	if err := h.validatePollInterval(interval); err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(c.Context(), feedCheckTimeout)
+	defer cancel()
+
+	if err := h.fetcher.CheckURL(ctx, req.URL); err != nil {
+		slog.Warn("Feed URL rejected", "url", req.URL, "error", err)
+		return errors.New(errors.ErrValidationFailed, err.Error())
+	}
+
+	parsed, _, err := h.fetcher.FetchFeed(ctx, req.URL, fetcher.Validators{})
+	if err != nil {
+		return errors.NewWithDetails(
+			errors.ErrValidationFailed,
+			"URL is not a readable RSS or Atom feed",
+			map[string]string{"url": req.URL, "reason": err.Error()},
+		)
+	}
+
+	title := validation.SanitizeString(req.Title)
+	if title == "" {
+		title = parsed.Title
+	}
+
+	feed, err := h.db.CreateFeed(c.Context(), &models.Feed{
+		URL:          req.URL,
+		Title:        title,
+		Category:     category,
+		PollInterval: interval,
+		Enabled:      true,
+	}, user.ID)
+	if err != nil {
+		return err
+	}
+
+	slog.Info("Feed subscribed", "feed_id", feed.ID, "url", feed.URL, "entries", len(parsed.Entries), "user_id", user.ID)
+
+	return c.Status(fiber.StatusCreated).JSON(feed)
+}
+
+// HandleGetFeed returns one subscription with its polling state
+func (h *FeedHandler) HandleGetFeed(c *fiber.Ctx) error {
+	feedID, err := parseUUIDParam(c, "id")
+	if err != nil {
+		return err
+	}
+
+	feed, err := h.db.GetFeed(c.Context(), feedID)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(feed)
+}
+
+// HandleUpdateFeed changes a subscription's title, category, interval or enabled state
+// PATCH /api/feeds/:id
+// Only the user who subscribed or an admin may change a feed.
+func (h *FeedHandler) HandleUpdateFeed(c *fiber.Ctx) error {
+	user, err := auth.GetUserFromContext(c)
+	if err != nil {
+		return err
+	}
+
+	feedID, err := parseUUIDParam(c, "id")
+	if err != nil {
+		return err
+	}
+
+	var change models.UpdateFeedRequest
+	if err := c.BodyParser(&change); err != nil {
+		slog.Debug("Failed to parse update feed request", "error", err)
+		return errors.New(errors.ErrBadRequest, "Invalid request body")
+	}
+
+	if change.Title == nil && change.Category == nil && change.PollInterval == nil && change.Enabled == nil {
+		return errors.New(errors.ErrMissingRequiredField, "Provide title, category, poll_interval or enabled")
+	}
+
+	if change.Title != nil {
+		title := validation.SanitizeString(*change.Title)
+		change.Title = &title
+	}
+	if change.Category != nil {
+		category, err := validateFeedCategory(*change.Category)
+		if err != nil {
+			return err
+		}
+		change.Category = &category
+	}
+	if change.PollInterval != nil {
+		if err := h.validatePollInterval(*change.PollInterval); err != nil {
+			return err
+		}
+	}
+
+	feed, err := h.db.GetFeed(c.Context(), feedID)
+	if err != nil {
+		return err
+	}
+	if err := h.checkFeedOwner(feed, user); err != nil {
+		return err
+	}
+
+	feed, err = h.db.UpdateFeed(c.Context(), feedID, &change)
+	if err != nil {
+		return err
+	}
+
+	slog.Info("Feed updated", "feed_id", feed.ID, "enabled", feed.Enabled, "user_id", user.ID)
+
+	return c.JSON(feed)
+}
+
+// HandleDeleteFeed unsubscribes from a feed; its articles stay in the knowledge base
+// Only the user who subscribed or an admin may delete a feed.
+func (h *FeedHandler) HandleDeleteFeed(c *fiber.Ctx) error {
+	user, err := auth.GetUserFromContext(c)
+	if err != nil {
+		return err
+	}
+
+	feedID, err := parseUUIDParam(c, "id")
+	if err != nil {
+		return err
+	}
+
+	feed, err := h.db.GetFeed(c.Context(), feedID)
+	if err != nil {
+		return err
+	}
+	if err := h.checkFeedOwner(feed, user); err != nil {
+		return err
+	}
+
+	if err := h.db.DeleteFeed(c.Context(), feedID); err != nil {
+		return err
+	}
+
+	slog.Info("Feed deleted", "feed_id", feedID, "user_id", user.ID)
+
+	return c.JSON(fiber.Map{
+		"message": "Feed deleted successfully",
+		"id":      feedID,
+	})
+}
+
+// checkFeedOwner allows the user who subscribed and admins; feeds whose
+// subscriber was deleted can only be changed by an admin
+func (h *FeedHandler) checkFeedOwner(feed *models.Feed, user *models.User) error {
+	if feed.CreatedBy != user.ID.String() && !h.authService.IsAdmin(user) {
+		return errors.New(errors.ErrForbidden, "Only the subscriber or an admin can change this feed")
+	}
+	return nil
+}
+
+// validatePollInterval checks a poll interval against the configured minimum and one week
+func (h *FeedHandler) validatePollInterval(interval int) error {
+	if interval < h.config.MinInterval || interval > maxFeedPollInterval {
+		return errors.NewWithDetails(errors.ErrValidationFailed, "poll_interval is out of range", map[string]interface{}{
+			"min_seconds": h.config.MinInterval,
+			"max_seconds": maxFeedPollInterval,
+		})
+	}
+	return nil
+}
+
+// validateFeedCategory sanitizes a category; empty means no tag
+func validateFeedCategory(category string) (string, error) {
+	category = validation.SanitizeString(category)
+	if utf8.RuneCountInString(category) > maxFeedCategory {
+		return "", errors.NewWithDetails(errors.ErrValidationFailed, "category is too long", map[string]interface{}{
+			"max_length": maxFeedCategory,
+		})
+	}
+	return category, nil
+}
@@ -0,0 +1,57 @@
+package handlers
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+
+	"article-chat-system/server/internal/auth"
+	"article-chat-system/server/internal/config"
+	"article-chat-system/server/internal/errors"
+	"article-chat-system/server/internal/models"
+)
+
+func TestCheckFeedOwner(t *testing.T) {
+	subscriber := &models.User{ID: uuid.New(), Email: "subscriber@example.com", EmailVerified: true}
+	other := &models.User{ID: uuid.New(), Email: "other@example.com", EmailVerified: true}
+	admin := &models.User{ID: uuid.New(), Email: "admin@example.com", EmailVerified: true}
+	unverifiedAdmin := &models.User{ID: uuid.New(), Email: "admin@example.com"}
+
+	authCfg := config.AuthConfig{
+		AdminEmails:       "admin@example.com",
+		EmailVerification: config.EmailVerificationConfig{Enabled: true},
+	}
+	h := NewFeedHandler(nil, nil, auth.NewAuthService(nil, nil, nil, authCfg, config.MailConfig{}), config.FeedConfig{})
+
+	owned := &models.Feed{ID: uuid.NewString(), CreatedBy: subscriber.ID.String()}
+	orphaned := &models.Feed{ID: uuid.NewString()} // Subscriber's account was deleted
+
+	tests := []struct {
+		name    string
+		feed    *models.Feed
+		user    *models.User
+		allowed bool
+	}{
+		{"subscriber", owned, subscriber, true},
+		{"other user", owned, other, false},
+		{"admin", owned, admin, true},
+		{"admin with unverified email", owned, unverifiedAdmin, false},
+		{"orphaned feed, user", orphaned, other, false},
+		{"orphaned feed, admin", orphaned, admin, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := h.checkFeedOwner(tt.feed, tt.user)
+			if tt.allowed && err != nil {
+				t.Errorf("checkFeedOwner = %v, want nil", err)
+			}
+			if !tt.allowed {
+				appErr, ok := errors.IsAppError(err)
+				if !ok || appErr.Code != errors.ErrForbidden {
+					t.Errorf("checkFeedOwner = %v, want ErrForbidden", err)
+				}
+			}
+		})
+	}
+}
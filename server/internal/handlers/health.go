@@ -47,3 +47,16 @@ func (h *HealthHandler) HandleHealth(c *fiber.Ctx) error {
 		"rag_service_url": h.config.RAGService.URL,
 	})
 }
+
+// HandleArticleQueue lists the article tasks waiting for a worker, next to the queue metrics
+func (h *HealthHandler) HandleArticleQueue(c *fiber.Ctx) error {
+	limit, _, err := parsePaginationParams(c)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(fiber.Map{
+		"stats": h.poolManager.ArticleQueue.Stats(),
+		"tasks": h.poolManager.ArticleQueue.Snapshot(limit),
+	})
+}
@@ -0,0 +1,114 @@
+package handlers
+
+import (
+	"crypto/subtle"
+	"log/slog"
+	"net/url"
+	"time"
+
+	"article-chat-system/server/internal/auth"
+	"article-chat-system/server/internal/config"
+	"article-chat-system/server/internal/errors"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// oidcStateCookie binds a login to the browser that started it, so a callback URL
+// from someone else's login can't sign the victim into the attacker's account
+const oidcStateCookie = "oidc_state"
+
+// OIDCHandler handles OpenID Connect single sign-on requests
+type OIDCHandler struct {
+	authService *auth.AuthService
+	provider    *auth.OIDCProvider
+	config      config.OIDCConfig
+}
+
+// NewOIDCHandler creates a new OIDC handler
+func NewOIDCHandler(authService *auth.AuthService, provider *auth.OIDCProvider, cfg config.OIDCConfig) *OIDCHandler {
+	return &OIDCHandler{
+		authService: authService,
+		provider:    provider,
+		config:      cfg,
+	}
+}
+
+// HandleOIDCLogin redirects the browser to the identity provider
+// With ?mode=json the authorization URL is returned instead, for SPA-driven redirects
+func (h *OIDCHandler) HandleOIDCLogin(c *fiber.Ctx) error {
+	authURL, state, err := h.provider.AuthorizationURL(c.Context())
+	if err != nil {
+		return err
+	}
+
+	setStateCookie(c, state, time.Now().Add(auth.OIDCStateTTL))
+
+	if c.Query("mode") == "json" {
+		return c.JSON(fiber.Map{
+			"authorization_url": authURL,
+		})
+	}
+
+	return c.Redirect(authURL, fiber.StatusFound)
+}
+
+// HandleOIDCCallback completes the authorization code flow and issues a session
+// When a post-login redirect URL is configured, the token is passed in the URL fragment
+// so it never reaches server logs; otherwise the normal auth response is returned as JSON
+func (h *OIDCHandler) HandleOIDCCallback(c *fiber.Ctx) error {
+	if providerErr := c.Query("error"); providerErr != "" {
+		slog.Warn("Identity provider returned an error", "error", providerErr, "description", c.Query("error_description"))
+		return errors.New(errors.ErrUnauthorized, "Login was cancelled or rejected by the identity provider")
+	}
+
+	// The state must come back to the browser that started the login
+	state := c.Query("state")
+	bound := c.Cookies(oidcStateCookie)
+	setStateCookie(c, "", time.Unix(0, 0))
+	if state == "" || subtle.ConstantTimeCompare([]byte(bound), []byte(state)) != 1 {
+		slog.Warn("OIDC callback state does not match the browser's login", "has_cookie", bound != "")
+		return errors.New(errors.ErrUnauthorized, "Login was not started from this browser")
+	}
+
+	identity, err := h.provider.Exchange(c.Context(), c.Query("code"), state)
+	if err != nil {
+		return err
+	}
+
+	result, err := h.authService.LoginWithOIDC(identity, c.Get("User-Agent"), c.IP())
+	if err != nil {
+		return err
+	}
+
+	fragment := url.Values{}
+	if result.Challenge != nil {
+		slog.Info("SSO login awaiting second factor", "user_id", result.User.ID)
+		fragment.Set("challenge_token", result.Challenge.ChallengeToken)
+	} else {
+		slog.Info("User logged in via SSO", "user_id", result.User.ID, "provider", identity.Provider)
+		fragment.Set("token", result.Token)
+	}
+
+	if h.config.PostLoginRedirectURL != "" {
+		return c.Redirect(h.config.PostLoginRedirectURL+"#"+fragment.Encode(), fiber.StatusFound)
+	}
+
+	if result.Challenge != nil {
+		return c.JSON(result.Challenge)
+	}
+	return c.JSON(newAuthResponse(result.User, result.Token))
+}
+
+// setStateCookie sets or, with a past expiry, deletes the login binding cookie
+// Lax so the cookie comes back on the provider's top-level redirect to the callback
+func setStateCookie(c *fiber.Ctx, state string, expires time.Time) {
+	c.Cookie(&fiber.Cookie{
+		Name:     oidcStateCookie,
+		Value:    state,
+		Path:     "/api/auth/oidc",
+		Expires:  expires,
+		HTTPOnly: true,
+		Secure:   true,
+		SameSite: fiber.CookieSameSiteLaxMode,
+	})
+}
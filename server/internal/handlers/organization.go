@@ -0,0 +1,267 @@
+package handlers
+
+import (
+	"log/slog"
+	"slices"
+	"unicode/utf8"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+
+	"article-chat-system/server/internal/auth"
+	"article-chat-system/server/internal/errors"
+	"article-chat-system/server/internal/models"
+	"article-chat-system/server/internal/validation"
+)
+
+// Maximum name lengths, matching the folders and tags columns
+const (
+	maxFolderName = 100
+	maxTagName    = 50
+)
+
+// HandleOrganizeConversation pins, archives, files or tags a conversation
+// PATCH /api/conversations/:id
+// Only the fields present in the body change; folder_id "" removes the folder and tag_ids replaces all tags
+func (h *ConversationHandler) HandleOrganizeConversation(c *fiber.Ctx) error {
+	// Get authenticated user
+	user, err := auth.GetUserFromContext(c)
+	if err != nil {
+		return err
+	}
+
+	// Parse conversation ID
+	conversationID, err := parseUUIDParam(c, "id")
+	if err != nil {
+		return err
+	}
+
+	// Check if user owns the conversation
+	if err := h.db.CheckConversationOwnership(c.Context(), conversationID, user.ID); err != nil {
+		return err
+	}
+
+	var change models.ConversationOrganize
+	if err := c.BodyParser(&change); err != nil {
+		slog.Debug("Failed to parse organize conversation request", "error", err)
+		return errors.New(errors.ErrBadRequest, "Invalid request body")
+	}
+
+	if change.Pinned == nil && change.Archived == nil && change.FolderID == nil && change.TagIDs == nil {
+		return errors.New(errors.ErrMissingRequiredField, "Provide pinned, archived, folder_id or tag_ids")
+	}
+
+	folderID := uuid.Nil
+	if change.FolderID != nil && *change.FolderID != "" {
+		folderID, err = uuid.Parse(*change.FolderID)
+		if err != nil {
+			return errors.New(errors.ErrInvalidDataType, "Invalid folder_id format")
+		}
+	}
+
+	if change.TagIDs != nil {
+		unique := make([]uuid.UUID, 0, len(*change.TagIDs))
+		for _, tagID := range *change.TagIDs {
+			if !slices.Contains(unique, tagID) {
+				unique = append(unique, tagID)
+			}
+		}
+		change.TagIDs = &unique
+	}
+
+	conversation, err := h.db.OrganizeConversation(c.Context(), user.ID, conversationID, &change, folderID)
+	if err != nil {
+		return err
+	}
+
+	slog.Info("Conversation organized", "conversation_id", conversationID, "user_id", user.ID)
+
+	return c.JSON(conversation)
+}
+
+// HandleListFolders returns the user's folders
+func (h *ConversationHandler) HandleListFolders(c *fiber.Ctx) error {
+	user, err := auth.GetUserFromContext(c)
+	if err != nil {
+		return err
+	}
+
+	folders, err := h.db.GetUserFolders(c.Context(), user.ID)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(fiber.Map{
+		"folders": folders,
+	})
+}
+
+// HandleCreateFolder creates a folder
+func (h *ConversationHandler) HandleCreateFolder(c *fiber.Ctx) error {
+	user, err := auth.GetUserFromContext(c)
+	if err != nil {
+		return err
+	}
+
+	name, err := parseLabelName(c, maxFolderName)
+	if err != nil {
+		return err
+	}
+
+	folder, err := h.db.CreateFolder(c.Context(), user.ID, name)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(folder)
+}
+
+// HandleRenameFolder renames a folder
+func (h *ConversationHandler) HandleRenameFolder(c *fiber.Ctx) error {
+	user, err := auth.GetUserFromContext(c)
+	if err != nil {
+		return err
+	}
+
+	folderID, err := parseUUIDParam(c, "id")
+	if err != nil {
+		return err
+	}
+
+	name, err := parseLabelName(c, maxFolderName)
+	if err != nil {
+		return err
+	}
+
+	folder, err := h.db.RenameFolder(c.Context(), user.ID, folderID, name)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(folder)
+}
+
+// HandleDeleteFolder deletes a folder; its conversations are kept and become unfiled
+func (h *ConversationHandler) HandleDeleteFolder(c *fiber.Ctx) error {
+	user, err := auth.GetUserFromContext(c)
+	if err != nil {
+		return err
+	}
+
+	folderID, err := parseUUIDParam(c, "id")
+	if err != nil {
+		return err
+	}
+
+	if err := h.db.DeleteFolder(c.Context(), user.ID, folderID); err != nil {
+		return err
+	}
+
+	return c.JSON(fiber.Map{
+		"message": "Folder deleted successfully",
+	})
+}
+
+// HandleListTags returns the user's tags
+func (h *ConversationHandler) HandleListTags(c *fiber.Ctx) error {
+	user, err := auth.GetUserFromContext(c)
+	if err != nil {
+		return err
+	}
+
+	tags, err := h.db.GetUserTags(c.Context(), user.ID)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(fiber.Map{
+		"tags": tags,
+	})
+}
+
+// HandleCreateTag creates a tag
+func (h *ConversationHandler) HandleCreateTag(c *fiber.Ctx) error {
+	user, err := auth.GetUserFromContext(c)
+	if err != nil {
+		return err
+	}
+
+	name, err := parseLabelName(c, maxTagName)
+	if err != nil {
+		return err
+	}
+
+	tag, err := h.db.CreateTag(c.Context(), user.ID, name)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(tag)
+}
+
+// HandleRenameTag renames a tag
+func (h *ConversationHandler) HandleRenameTag(c *fiber.Ctx) error {
+	user, err := auth.GetUserFromContext(c)
+	if err != nil {
+		return err
+	}
+
+	tagID, err := parseUUIDParam(c, "id")
+	if err != nil {
+		return err
+	}
+
+	name, err := parseLabelName(c, maxTagName)
+	if err != nil {
+		return err
+	}
+
+	tag, err := h.db.RenameTag(c.Context(), user.ID, tagID, name)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(tag)
+}
+
+// HandleDeleteTag deletes a tag and removes it from all conversations
+func (h *ConversationHandler) HandleDeleteTag(c *fiber.Ctx) error {
+	user, err := auth.GetUserFromContext(c)
+	if err != nil {
+		return err
+	}
+
+	tagID, err := parseUUIDParam(c, "id")
+	if err != nil {
+		return err
+	}
+
+	if err := h.db.DeleteTag(c.Context(), user.ID, tagID); err != nil {
+		return err
+	}
+
+	return c.JSON(fiber.Map{
+		"message": "Tag deleted successfully",
+	})
+}
+
+// parseLabelName reads and validates the name of a folder or tag from the request body
+func parseLabelName(c *fiber.Ctx, maxLength int) (string, error) {
+	var input models.LabelInput
+	if err := c.BodyParser(&input); err != nil {
+		slog.Debug("Failed to parse name request", "error", err)
+		return "", errors.New(errors.ErrBadRequest, "Invalid request body")
+	}
+
+	name := validation.SanitizeString(input.Name)
+	if name == "" {
+		return "", errors.New(errors.ErrMissingRequiredField, "name is required")
+	}
+	if utf8.RuneCountInString(name) > maxLength {
+		return "", errors.NewWithDetails(errors.ErrValidationFailed, "name is too long", map[string]interface{}{
+			"max_length": maxLength,
+		})
+	}
+
+	return name, nil
+}
@@ -0,0 +1,200 @@
+package handlers
+
+import (
+	"log/slog"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+
+	"article-chat-system/server/internal/auth"
+	"article-chat-system/server/internal/database"
+	"article-chat-system/server/internal/errors"
+	"article-chat-system/server/internal/export"
+	"article-chat-system/server/internal/models"
+)
+
+// maxShareHours caps how long a share link can stay valid (one year)
+const maxShareHours = 24 * 365
+
+// ShareHandler handles read-only share links for conversations
+type ShareHandler struct {
+	db *database.DB
+}
+
+// NewShareHandler creates a new share handler
+func NewShareHandler(db *database.DB) *ShareHandler {
+	return &ShareHandler{
+		db: db,
+	}
+}
+
+// HandleCreateShare creates a share link for a conversation the user owns
+// The token is returned only once; only its hash is stored
+func (h *ShareHandler) HandleCreateShare(c *fiber.Ctx) error {
+	// Get authenticated user
+	user, err := auth.GetUserFromContext(c)
+	if err != nil {
+		return err
+	}
+
+	// Parse conversation ID
+	conversationID, err := parseUUIDParam(c, "id")
+	if err != nil {
+		return err
+	}
+
+	// Check if user owns the conversation
+	if err := h.db.CheckConversationOwnership(c.Context(), conversationID, user.ID); err != nil {
+		return err
+	}
+
+	var create models.ConversationShareCreate
+
+	// Parse request body (optional)
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&create); err != nil {
+			slog.Debug("Failed to parse create share request", "error", err)
+			return errors.New(errors.ErrBadRequest, "Invalid request body")
+		}
+	}
+
+	if create.ExpiresInHours < 0 || create.ExpiresInHours > maxShareHours {
+		return errors.New(errors.ErrValidationFailed, "expires_in_hours must be between 0 (never) and 8760")
+	}
+
+	var expiresAt *time.Time
+	if create.ExpiresInHours > 0 {
+		t := time.Now().Add(time.Duration(create.ExpiresInHours) * time.Hour)
+		expiresAt = &t
+	}
+
+	token, err := auth.GenerateSessionToken()
+	if err != nil {
+		return err
+	}
+
+	share, err := h.db.CreateConversationShare(c.Context(), conversationID, user.ID, auth.HashToken(token), expiresAt)
+	if err != nil {
+		return err
+	}
+	share.Token = token
+
+	slog.Info("Conversation share created", "conversation_id", conversationID, "user_id", user.ID, "share_id", share.ID)
+
+	return c.Status(fiber.StatusCreated).JSON(share)
+}
+
+// HandleListShares lists the share links of a conversation the user owns
+func (h *ShareHandler) HandleListShares(c *fiber.Ctx) error {
+	// Get authenticated user
+	user, err := auth.GetUserFromContext(c)
+	if err != nil {
+		return err
+	}
+
+	// Parse conversation ID
+	conversationID, err := parseUUIDParam(c, "id")
+	if err != nil {
+		return err
+	}
+
+	// Check if user owns the conversation
+	if err := h.db.CheckConversationOwnership(c.Context(), conversationID, user.ID); err != nil {
+		return err
+	}
+
+	shares, err := h.db.GetConversationShares(c.Context(), conversationID)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(fiber.Map{
+		"shares": shares,
+	})
+}
+
+// HandleRevokeShare deletes a share link; the token stops working immediately
+func (h *ShareHandler) HandleRevokeShare(c *fiber.Ctx) error {
+	// Get authenticated user
+	user, err := auth.GetUserFromContext(c)
+	if err != nil {
+		return err
+	}
+
+	// Parse conversation and share IDs
+	conversationID, err := parseUUIDParam(c, "id")
+	if err != nil {
+		return err
+	}
+	shareID, err := parseUUIDParam(c, "shareId")
+	if err != nil {
+		return err
+	}
+
+	// Check if user owns the conversation
+	if err := h.db.CheckConversationOwnership(c.Context(), conversationID, user.ID); err != nil {
+		return err
+	}
+
+	if err := h.db.DeleteConversationShare(c.Context(), shareID, conversationID); err != nil {
+		return err
+	}
+
+	slog.Info("Conversation share revoked", "conversation_id", conversationID, "user_id", user.ID, "share_id", shareID)
+
+	return c.JSON(fiber.Map{
+		"message": "Share link revoked",
+	})
+}
+
+// HandleGetSharedConversation returns the public, read-only view of a shared conversation
+// No authentication is required; the unguessable token is the credential
+func (h *ShareHandler) HandleGetSharedConversation(c *fiber.Ctx) error {
+	token := c.Params("token")
+	if token == "" {
+		return errors.New(errors.ErrMissingRequiredField, "token is required")
+	}
+
+	conversationID, expiresAt, err := h.db.ResolveConversationShare(c.Context(), auth.HashToken(token))
+	if err != nil {
+		return err
+	}
+
+	conversation, err := h.db.GetConversationWithMessages(c.Context(), conversationID)
+	if err != nil {
+		return err
+	}
+
+	// Shared pages must not be cached by intermediaries once revoked
+	c.Set(fiber.HeaderCacheControl, "no-store")
+
+	return c.JSON(newSharedConversation(conversation, expiresAt))
+}
+
+// newSharedConversation strips owner details, IDs and internal metadata from a conversation
+func newSharedConversation(conversation *models.ConversationWithMessages, expiresAt *time.Time) models.SharedConversation {
+	shared := models.SharedConversation{
+		Title:     conversation.Title,
+		CreatedAt: conversation.CreatedAt,
+		ExpiresAt: expiresAt,
+		Messages:  make([]models.SharedMessage, 0, len(conversation.Messages)),
+	}
+
+	for _, msg := range conversation.Messages {
+		item := models.SharedMessage{
+			Role:      msg.Role,
+			Content:   msg.Content,
+			CreatedAt: msg.CreatedAt,
+		}
+		for _, source := range export.MessageSources(msg) {
+			item.Sources = append(item.Sources, models.SharedSource{
+				ArticleID:    source.ArticleID,
+				ArticleTitle: source.ArticleTitle,
+				Relevance:    source.Relevance,
+			})
+		}
+		shared.Messages = append(shared.Messages, item)
+	}
+
+	return shared
+}
@@ -0,0 +1,128 @@
+package handlers
+
+import (
+	"log/slog"
+
+	"article-chat-system/server/internal/auth"
+	"article-chat-system/server/internal/errors"
+	"article-chat-system/server/internal/models"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// HandleTwoFactorEnroll starts TOTP enrollment and returns the secret and provisioning URI
+func (h *AuthHandler) HandleTwoFactorEnroll(c *fiber.Ctx) error {
+	// Get authenticated user
+	user, err := auth.GetUserFromContext(c)
+	if err != nil {
+		return err
+	}
+
+	enrollment, err := h.authService.StartTOTPEnrollment(user)
+	if err != nil {
+		return err
+	}
+
+	slog.Info("Two-factor enrollment started", "user_id", user.ID)
+
+	return c.JSON(enrollment)
+}
+
+// HandleTwoFactorConfirm confirms enrollment with a first TOTP code and returns recovery codes
+func (h *AuthHandler) HandleTwoFactorConfirm(c *fiber.Ctx) error {
+	// Get authenticated user
+	user, err := auth.GetUserFromContext(c)
+	if err != nil {
+		return err
+	}
+
+	var req models.TwoFactorCode
+
+	// Parse request body
+	if err := c.BodyParser(&req); err != nil {
+		slog.Debug("Failed to parse two-factor confirm request", "error", err)
+		return errors.New(errors.ErrBadRequest, "Invalid request body")
+	}
+
+	if req.Code == "" {
+		return errors.New(errors.ErrMissingRequiredField, "Code is required")
+	}
+
+	recoveryCodes, err := h.authService.ConfirmTOTPEnrollment(user.ID, req.Code)
+	if err != nil {
+		return err
+	}
+
+	slog.Info("Two-factor authentication enabled", "user_id", user.ID)
+
+	return c.JSON(fiber.Map{
+		"message":        "Two-factor authentication enabled. Store these recovery codes somewhere safe.",
+		"recovery_codes": recoveryCodes,
+	})
+}
+
+// HandleTwoFactorVerify completes a login challenge and returns a session token
+func (h *AuthHandler) HandleTwoFactorVerify(c *fiber.Ctx) error {
+	var req models.TwoFactorVerify
+
+	// Parse request body
+	if err := c.BodyParser(&req); err != nil {
+		slog.Debug("Failed to parse two-factor verify request", "error", err)
+		return errors.New(errors.ErrBadRequest, "Invalid request body")
+	}
+
+	user, token, err := h.authService.VerifyTwoFactorLogin(&req, c.Get("User-Agent"), c.IP())
+	if err != nil {
+		return err
+	}
+
+	slog.Info("User logged in with second factor", "user_id", user.ID, "email", user.Email)
+
+	return c.JSON(newAuthResponse(user, token))
+}
+
+// HandleTwoFactorDisable turns off two-factor authentication (requires the current password)
+func (h *AuthHandler) HandleTwoFactorDisable(c *fiber.Ctx) error {
+	// Get authenticated user
+	user, err := auth.GetUserFromContext(c)
+	if err != nil {
+		return err
+	}
+
+	var req models.PasswordConfirmation
+
+	// Parse request body
+	if err := c.BodyParser(&req); err != nil {
+		slog.Debug("Failed to parse two-factor disable request", "error", err)
+		return errors.New(errors.ErrBadRequest, "Invalid request body")
+	}
+
+	if err := h.authService.DisableTOTP(user, req.Password); err != nil {
+		return err
+	}
+
+	slog.Info("Two-factor authentication disabled", "user_id", user.ID)
+
+	return c.JSON(fiber.Map{
+		"message": "Two-factor authentication disabled",
+	})
+}
+
+// HandleTwoFactorStatus reports whether 2FA is enabled and how many recovery codes remain
+func (h *AuthHandler) HandleTwoFactorStatus(c *fiber.Ctx) error {
+	// Get authenticated user
+	user, err := auth.GetUserFromContext(c)
+	if err != nil {
+		return err
+	}
+
+	remaining, err := h.authService.GetDB().CountUnusedRecoveryCodes(user.ID)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(fiber.Map{
+		"enabled":                  user.TwoFactorEnabled,
+		"recovery_codes_remaining": remaining,
+	})
+}
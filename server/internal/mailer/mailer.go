@@ -0,0 +1,60 @@
+// Package mailer delivers transactional emails (confirmations, verification links)
+//
+// Delivery is pluggable through the Mailer interface. The default log driver writes
+// messages to the structured log, which is enough for development and for deployments
+// that forward logs to an operator. The file driver writes one file per message.
+package mailer
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"article-chat-system/server/internal/config"
+)
+
+// Message is a plain-text email
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// Mailer sends emails
+type Mailer interface {
+	Send(ctx context.Context, msg Message) error
+}
+
+// New creates the mailer selected by the configured driver
+func New(cfg config.MailConfig) (Mailer, error) {
+	switch cfg.Driver {
+	case "", "log":
+		return NewLogMailer(cfg.From), nil
+	case "file":
+		return NewFileMailer(cfg.From, cfg.Dir)
+	default:
+		return nil, fmt.Errorf("unsupported mail driver %q", cfg.Driver)
+	}
+}
+
+// LogMailer writes emails to the application log instead of sending them
+type LogMailer struct {
+	from string
+}
+
+// NewLogMailer creates a mailer that logs messages
+func NewLogMailer(from string) *LogMailer {
+	return &LogMailer{
+		from: from,
+	}
+}
+
+// Send logs the message
+func (m *LogMailer) Send(ctx context.Context, msg Message) error {
+	slog.Info("Email (log driver)",
+		"from", m.from,
+		"to", msg.To,
+		"subject", msg.Subject,
+		"body", msg.Body)
+	return nil
+}
@@ -4,6 +4,8 @@ import (
 	"article-chat-system/server/internal/errors"
 	"article-chat-system/server/internal/models"
 	"log/slog"
+	"math"
+	"strconv"
 	"time"
 
 	"github.com/gofiber/fiber/v2"
@@ -28,6 +30,11 @@ func ErrorHandler() fiber.ErrorHandler {
 
 		// Check if it's an AppError
 		if appErr, ok := errors.IsAppError(err); ok {
+			// Tell throttled clients when they may retry (rounded up to whole seconds)
+			if appErr.RetryAfter > 0 {
+				c.Set(fiber.HeaderRetryAfter, strconv.Itoa(int(math.Ceil(appErr.RetryAfter.Seconds()))))
+			}
+
 			return c.Status(appErr.StatusCode()).JSON(models.ErrorResponse{
 				Error:     string(appErr.Code),
 				Message:   appErr.Message,
@@ -2,22 +2,147 @@ package models
 
 import (
 	"time"
+
+	"github.com/google/uuid"
 )
 
 type Article struct {
-	ID          string    `json:"id" db:"id"`
-	URL         string    `json:"url" db:"url"`
-	Title       string    `json:"title" db:"title"`
-	Content     string    `json:"content" db:"content"`
-	RawHTML     string    `json:"raw_html" db:"raw_html"`
-	Author      string    `json:"author" db:"author"`
-	Source      string    `json:"source" db:"source"`
-	FetchedAt   time.Time `json:"fetched_at" db:"fetched_at"`
-	PublishedAt time.Time `json:"published_at" db:"published_at"`
-	ProcessedAt time.Time `json:"processed_at" db:"processed_at"`
-	ChunkCount  int       `json:"chunk_count" db:"chunk_count"`
-	Status      string    `json:"status" db:"status"`
-	Metadata    Metadata  `json:"metadata" db:"metadata"`
+	ID           string    `json:"id" db:"id"`
+	URL          string    `json:"url" db:"url"`
+	CanonicalURL string    `json:"canonical_url" db:"canonical_url"` // Identifies the article across URL variants
+	ContentHash  string    `json:"content_hash" db:"content_hash"`   // SHA-256 of the extracted text
+	Title        string    `json:"title" db:"title"`
+	Content      string    `json:"content,omitempty" db:"content"`   // Omitted from lists unless requested
+	RawHTML      string    `json:"raw_html,omitempty" db:"raw_html"` // Omitted from lists unless requested
+	Author       string    `json:"author" db:"author"`
+	Source       string    `json:"source" db:"source"`
+	FetchedAt    time.Time `json:"fetched_at" db:"fetched_at"`
+	PublishedAt  time.Time `json:"published_at" db:"published_at"`
+	ProcessedAt  time.Time `json:"processed_at" db:"processed_at"`
+	ChunkCount   int       `json:"chunk_count" db:"chunk_count"`
+	Status       string    `json:"status" db:"status"`
+	Metadata     Metadata  `json:"metadata" db:"metadata"`
+	FeedID       string    `json:"feed_id,omitempty" db:"feed_id"`           // Feed the article was ingested from
+	SubmittedBy  string    `json:"submitted_by,omitempty" db:"submitted_by"` // User who submitted or uploaded the article
+
+	// Failure state: the last ingestion error, failed attempts since the last success and the
+	// next automatic retry (nil: none scheduled)
+	LastError   string     `json:"last_error,omitempty" db:"last_error"`
+	Attempts    int        `json:"attempts" db:"attempts"`
+	NextRetryAt *time.Time `json:"next_retry_at,omitempty" db:"next_retry_at"`
+
+	// Refresh state: the content version, the interval between checks in seconds (nil: the
+	// configured default, 0: never) and the page's HTTP cache validators
+	Version         int        `json:"version" db:"version"`
+	RefreshInterval *int       `json:"refresh_interval" db:"refresh_interval"`
+	LastCheckedAt   *time.Time `json:"last_checked_at,omitempty" db:"last_checked_at"`
+	UpdatedAt       time.Time  `json:"updated_at" db:"updated_at"`
+	ETag            string     `json:"-" db:"etag"`
+	LastModified    string     `json:"-" db:"last_modified"`
+}
+
+// ArticleVersion describes one version of an article's content
+type ArticleVersion struct {
+	Version     int        `json:"version"`
+	Title       string     `json:"title"`
+	ContentHash string     `json:"content_hash"`
+	FetchedAt   time.Time  `json:"fetched_at"`
+	ReplacedAt  *time.Time `json:"replaced_at,omitempty"` // nil for the current version
+}
+
+// ArticleRefreshIntervalRequest sets how often an article is re-fetched
+type ArticleRefreshIntervalRequest struct {
+	Interval *int `json:"interval"` // Seconds, 0 disables refreshing; null restores the default
+}
+
+// Article statuses, in ingestion order
+const (
+	ArticleStatusFetched  = "fetched"  // Page fetched and content extracted
+	ArticleStatusIndexing = "indexing" // Sent to the RAG service for chunking and embedding
+	ArticleStatusIndexed  = "indexed"
+	ArticleStatusFailed   = "failed"
+)
+
+// ReindexJob reports the progress of re-running ingestion over a set of articles
+type ReindexJob struct {
+	ID         string         `json:"id"`
+	Status     string         `json:"status"` // One of the ReindexJob statuses
+	Total      int            `json:"total"`
+	Processed  int            `json:"processed"`
+	Succeeded  int            `json:"succeeded"`
+	Failed     int            `json:"failed"`
+	Skipped    int            `json:"skipped"` // Failed articles another retry was already working on
+	Errors     []ReindexError `json:"errors"`  // The first failures only
+	StartedAt  time.Time      `json:"started_at"`
+	FinishedAt *time.Time     `json:"finished_at,omitempty"`
+}
+
+// ReindexError is one article a reindex job failed to ingest
+type ReindexError struct {
+	ArticleID string `json:"article_id"`
+	Error     string `json:"error"`
+}
+
+// Reindex job statuses
+const (
+	ReindexJobRunning   = "running"
+	ReindexJobCompleted = "completed"
+	ReindexJobCancelled = "cancelled" // Stopped by shutdown
+	ReindexJobFailed    = "failed"    // The articles could not be loaded
+)
+
+// ArticleFilter holds the filter, sort and paging options for listing articles
+type ArticleFilter struct {
+	Statuses       []string   // Any of these statuses; empty: all
+	Domain         string     // Articles from this host or its subdomains
+	Tag            string     // Articles tagged with this tag
+	Source         string     // user_submitted, feed or upload
+	SubmittedBy    *uuid.UUID // Articles submitted by this user
+	DateField      string     // fetched_at or published_at, the column From and To apply to
+	From           *time.Time // Inclusive
+	To             *time.Time // Exclusive
+	Query          string     // Keywords searched in title and content
+	Sort           string     // fetched_at, published_at or title
+	Desc           bool
+	IncludeContent bool // Load content and raw HTML
+	Limit          int
+	Cursor         string // Opaque position returned as next_cursor
+}
+
+// Feed is an RSS or Atom subscription whose new entries are ingested as articles
+type Feed struct {
+	ID           string     `json:"id"`
+	URL          string     `json:"url"`
+	Title        string     `json:"title"`
+	Category     string     `json:"category"`      // Tag added to ingested articles
+	PollInterval int        `json:"poll_interval"` // Seconds between polls while the feed is healthy
+	Enabled      bool       `json:"enabled"`
+	ErrorCount   int        `json:"error_count"` // Consecutive failed polls; each one doubles the delay
+	LastError    string     `json:"last_error,omitempty"`
+	LastPolledAt *time.Time `json:"last_polled_at,omitempty"`
+	NextPollAt   time.Time  `json:"next_poll_at"`
+	ArticleCount int        `json:"article_count"`
+	CreatedBy    string     `json:"created_by,omitempty"`
+	CreatedAt    time.Time  `json:"created_at"`
+	UpdatedAt    time.Time  `json:"updated_at"`
+	ETag         string     `json:"-"`
+	LastModified string     `json:"-"`
+}
+
+// CreateFeedRequest subscribes to a feed; the title defaults to the feed's own
+type CreateFeedRequest struct {
+	URL          string `json:"url"`
+	Title        string `json:"title,omitempty"`
+	Category     string `json:"category,omitempty"`
+	PollInterval int    `json:"poll_interval,omitempty"` // Seconds; 0 uses the configured default
+}
+
+// UpdateFeedRequest changes a subscription; nil fields are left unchanged
+type UpdateFeedRequest struct {
+	Title        *string `json:"title,omitempty"`
+	Category     *string `json:"category,omitempty"`
+	PollInterval *int    `json:"poll_interval,omitempty"`
+	Enabled      *bool   `json:"enabled,omitempty"`
 }
 
 type Metadata struct {
@@ -92,10 +217,18 @@ type AddArticleRequest struct {
 }
 
 type AddArticleResponse struct {
-	ID      string `json:"id"`
-	Status  string `json:"status"`
-	Message string `json:"message"`
-	Cached  bool   `json:"cached,omitempty"`
+	ID        string `json:"id"`
+	Status    string `json:"status"`
+	Message   string `json:"message"`
+	Cached    bool   `json:"cached,omitempty"`
+	Duplicate bool   `json:"duplicate,omitempty"` // ID is the existing article with the same canonical URL or content
+}
+
+// UploadArticleResult is the outcome for one uploaded document; Error is set when it was not indexed
+type UploadArticleResult struct {
+	Filename string `json:"filename"`
+	AddArticleResponse
+	Error string `json:"error,omitempty"`
 }
 
 type ErrorResponse struct {
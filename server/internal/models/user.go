@@ -15,6 +15,12 @@ type User struct {
 	UpdatedAt time.Time  `json:"updated_at"`
 	LastLogin *time.Time `json:"last_login,omitempty"`
 	IsActive  bool       `json:"is_active"`
+
+	TwoFactorEnabled bool `json:"two_factor_enabled"`
+	EmailVerified    bool `json:"email_verified"`
+
+	// SSOLoginAt is set by ValidateSession when the current session came from an SSO login
+	SSOLoginAt *time.Time `json:"-"`
 }
 
 // UserCredentials represents user login credentials
@@ -39,6 +45,7 @@ type UserSession struct {
 	CreatedAt time.Time `json:"created_at"`
 	UserAgent string    `json:"user_agent,omitempty"`
 	IPAddress string    `json:"ip_address,omitempty"`
+	ViaSSO    bool      `json:"via_sso"` // Issued by an SSO login
 }
 
 // UserProfile represents the user profile data
@@ -49,6 +56,9 @@ type UserProfile struct {
 	CreatedAt time.Time  `json:"created_at"`
 	UpdatedAt time.Time  `json:"updated_at"`
 	LastLogin *time.Time `json:"last_login,omitempty"`
+
+	TwoFactorEnabled bool `json:"two_factor_enabled"`
+	EmailVerified    bool `json:"email_verified"`
 }
 
 // UserUpdate represents fields that can be updated
@@ -62,6 +72,73 @@ type AuthResponse struct {
 	Token string      `json:"token"`
 }
 
+// TwoFactorChallenge represents a login that passed the password check and awaits a TOTP code
+type TwoFactorChallenge struct {
+	ID        uuid.UUID `json:"id"`
+	UserID    uuid.UUID `json:"user_id"`
+	TokenHash string    `json:"-"`
+	Attempts  int       `json:"attempts"`
+	ExpiresAt time.Time `json:"expires_at"`
+	CreatedAt time.Time `json:"created_at"`
+	UserAgent string    `json:"user_agent,omitempty"`
+	IPAddress string    `json:"ip_address,omitempty"`
+	ViaSSO    bool      `json:"-"` // Started by an SSO login
+}
+
+// TwoFactorChallengeResponse is returned by login instead of a session when 2FA is enabled
+type TwoFactorChallengeResponse struct {
+	TwoFactorRequired bool      `json:"two_factor_required"`
+	ChallengeToken    string    `json:"challenge_token"`
+	ExpiresAt         time.Time `json:"expires_at"`
+}
+
+// TwoFactorEnrollment is returned when a user starts TOTP enrollment
+type TwoFactorEnrollment struct {
+	Secret          string `json:"secret"`
+	ProvisioningURI string `json:"provisioning_uri"` // otpauth:// URI to render as a QR code
+}
+
+// TwoFactorCode carries a TOTP code to confirm enrollment
+type TwoFactorCode struct {
+	Code string `json:"code" validate:"required,len=6"`
+}
+
+// TwoFactorVerify completes a login challenge with a TOTP code or a recovery code
+type TwoFactorVerify struct {
+	ChallengeToken string `json:"challenge_token" validate:"required"`
+	Code           string `json:"code,omitempty"`
+	RecoveryCode   string `json:"recovery_code,omitempty"`
+}
+
+// PasswordConfirmation re-authenticates a user before a sensitive change
+// The password may be omitted right after an SSO login
+type PasswordConfirmation struct {
+	Password string `json:"password,omitempty"`
+}
+
+// AccountDeletion confirms removal of the authenticated account
+// With Deactivate set the account is only disabled and its data is kept
+type AccountDeletion struct {
+	Password   string `json:"password,omitempty"` // May be omitted right after an SSO login
+	Deactivate bool   `json:"deactivate"`
+}
+
+// EmailChange requests moving the account to a new email address
+type EmailChange struct {
+	NewEmail string `json:"new_email" validate:"required,email"`
+	Password string `json:"password,omitempty"` // May be omitted right after an SSO login
+}
+
+// EmailVerification carries the signed token emailed at signup
+type EmailVerification struct {
+	Token string `json:"token" validate:"required"`
+}
+
+// EmailChangeConfirmation carries the token emailed to the new address
+type EmailChangeConfirmation struct {
+	Token string `json:"token" validate:"required"`
+}
+
 // Conversation represents a chat conversation
 type Conversation struct {
 	ID           uuid.UUID `json:"id"`
@@ -70,16 +147,30 @@ type Conversation struct {
 	CreatedAt    time.Time `json:"created_at"`
 	UpdatedAt    time.Time `json:"updated_at"`
 	MessageCount int       `json:"message_count"`
+
+	Pinned     bool       `json:"pinned"`
+	Archived   bool       `json:"archived"`
+	PinnedAt   *time.Time `json:"pinned_at,omitempty"`
+	ArchivedAt *time.Time `json:"archived_at,omitempty"`
+	FolderID   *uuid.UUID `json:"folder_id,omitempty"`
+	Tags       []Tag      `json:"tags"`
+
+	TitleManual bool `json:"title_manual"` // Set by the user; automatic titles never replace it
 }
 
 // Message represents a single message in a conversation
 type Message struct {
 	ID             uuid.UUID              `json:"id"`
 	ConversationID uuid.UUID              `json:"conversation_id"`
-	Role           string                 `json:"role"` // "user" or "assistant"
+	ParentID       *uuid.UUID             `json:"parent_id,omitempty"` // Previous message on the branch; nil for the first message
+	Role           string                 `json:"role"`                // "user" or "assistant"
 	Content        string                 `json:"content"`
 	Metadata       map[string]interface{} `json:"metadata,omitempty"`
 	CreatedAt      time.Time              `json:"created_at"`
+
+	// Position among messages sharing the same parent (1-based), set when loading a branch
+	BranchIndex int `json:"branch_index,omitempty"`
+	BranchCount int `json:"branch_count,omitempty"`
 }
 
 // ConversationWithMessages represents a conversation with its messages
@@ -88,11 +179,238 @@ type ConversationWithMessages struct {
 	Messages []Message `json:"messages"`
 }
 
+// ConversationSummary is the rolling summary of turns outside the history window sent to the RAG service
+type ConversationSummary struct {
+	Summary   string
+	MessageID *uuid.UUID // Last message the summary covers
+	UpdatedAt *time.Time
+}
+
 // ConversationCreate represents data for creating a new conversation
 type ConversationCreate struct {
 	Title string `json:"title,omitempty"`
 }
 
+// MessageSearch holds the parsed parameters of a conversation history search
+type MessageSearch struct {
+	Query string
+	Role  string     // Optional: "user" or "assistant"
+	From  *time.Time // Optional inclusive lower bound on created_at
+	To    *time.Time // Optional exclusive upper bound on created_at
+}
+
+// MessageSearchHit is a single message matching a history search
+// Snippet is HTML-escaped with matches wrapped in <mark> tags
+type MessageSearchHit struct {
+	MessageID         uuid.UUID `json:"message_id"`
+	ConversationID    uuid.UUID `json:"conversation_id"`
+	ConversationTitle string    `json:"conversation_title"`
+	Role              string    `json:"role"`
+	Snippet           string    `json:"snippet"`
+	Rank              float64   `json:"rank"`
+	CreatedAt         time.Time `json:"created_at"`
+}
+
+// ImportedConversation describes a conversation created by an import
+type ImportedConversation struct {
+	SourceID       uuid.UUID `json:"source_id"`
+	ConversationID uuid.UUID `json:"conversation_id"`
+	Title          string    `json:"title"`
+	MessageCount   int       `json:"message_count"`
+}
+
+// ImportDuplicate describes a conversation skipped because it already exists
+type ImportDuplicate struct {
+	SourceID uuid.UUID `json:"source_id"`
+	Title    string    `json:"title"`
+}
+
+// ImportResult summarizes a conversation import
+type ImportResult struct {
+	Imported   []ImportedConversation `json:"imported"`
+	Duplicates []ImportDuplicate      `json:"duplicates"`
+}
+
+// ConversationShare is a read-only link to a conversation
+// Token is only populated in the response that creates the share
+type ConversationShare struct {
+	ID             uuid.UUID  `json:"id"`
+	ConversationID uuid.UUID  `json:"conversation_id"`
+	Token          string     `json:"token,omitempty"`
+	ExpiresAt      *time.Time `json:"expires_at,omitempty"`
+	CreatedAt      time.Time  `json:"created_at"`
+	LastAccessedAt *time.Time `json:"last_accessed_at,omitempty"`
+	AccessCount    int        `json:"access_count"`
+}
+
+// ConversationShareCreate represents data for creating a share link
+type ConversationShareCreate struct {
+	ExpiresInHours int `json:"expires_in_hours,omitempty"` // 0 means the link never expires
+}
+
+// SharedConversation is the public view of a shared conversation
+// It omits IDs, the owner and internal message metadata
+type SharedConversation struct {
+	Title     string          `json:"title"`
+	CreatedAt time.Time       `json:"created_at"`
+	ExpiresAt *time.Time      `json:"expires_at,omitempty"`
+	Messages  []SharedMessage `json:"messages"`
+}
+
+// SharedMessage is a message in a shared conversation
+type SharedMessage struct {
+	Role      string         `json:"role"`
+	Content   string         `json:"content"`
+	CreatedAt time.Time      `json:"created_at"`
+	Sources   []SharedSource `json:"sources,omitempty"`
+}
+
+// SharedSource is an article cited by a shared answer
+type SharedSource struct {
+	ArticleID    string  `json:"article_id"`
+	ArticleTitle string  `json:"article_title"`
+	Relevance    float32 `json:"relevance"`
+}
+
+// MessageEdit replaces the content of a user message on a new branch
+type MessageEdit struct {
+	Message string `json:"message" validate:"required"`
+}
+
+// BranchResponse is returned when an edit or regeneration creates a new branch
+type BranchResponse struct {
+	ChatResponse
+	UserMessageID      uuid.UUID `json:"user_message_id"`
+	AssistantMessageID uuid.UUID `json:"assistant_message_id"`
+}
+
+// Feedback ratings
+const (
+	FeedbackUp   = "up"
+	FeedbackDown = "down"
+)
+
+// FeedbackReasons are the reason tags accepted with a rating
+var FeedbackReasons = []string{
+	"accurate", "helpful", "well_sourced",
+	"inaccurate", "incomplete", "irrelevant_sources", "outdated", "unclear",
+}
+
+// MessageFeedback is a user's rating of an assistant message
+type MessageFeedback struct {
+	ID        uuid.UUID `json:"id"`
+	MessageID uuid.UUID `json:"message_id"`
+	UserID    uuid.UUID `json:"user_id"`
+	Rating    string    `json:"rating"`
+	Reasons   []string  `json:"reasons"`
+	Comment   string    `json:"comment,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// MessageFeedbackInput represents data for rating an assistant message
+type MessageFeedbackInput struct {
+	Rating  string   `json:"rating" validate:"required,oneof=up down"`
+	Reasons []string `json:"reasons,omitempty"`
+	Comment string   `json:"comment,omitempty"`
+}
+
+// FeedbackStats counts ratings in one report bucket
+type FeedbackStats struct {
+	Up       int     `json:"up"`
+	Down     int     `json:"down"`
+	Total    int     `json:"total"`
+	DownRate float64 `json:"down_rate"`
+}
+
+// ArticleFeedback aggregates ratings of answers that cited an article
+type ArticleFeedback struct {
+	ArticleID    string `json:"article_id"`
+	ArticleTitle string `json:"article_title"`
+	FeedbackStats
+}
+
+// CacheFeedback aggregates ratings of cached or freshly generated answers
+type CacheFeedback struct {
+	Cached bool `json:"cached"`
+	FeedbackStats
+}
+
+// DailyFeedback aggregates ratings given on one day
+type DailyFeedback struct {
+	Day string `json:"day"`
+	FeedbackStats
+}
+
+// FeedbackReason counts how often a reason tag was given with each rating
+type FeedbackReason struct {
+	Reason string `json:"reason"`
+	FeedbackStats
+}
+
+// FeedbackReport summarizes answer quality over a date range
+type FeedbackReport struct {
+	From      time.Time         `json:"from"`
+	To        time.Time         `json:"to"`
+	Totals    FeedbackStats     `json:"totals"`
+	ByArticle []ArticleFeedback `json:"by_article"`
+	ByCache   []CacheFeedback   `json:"by_cache"`
+	ByDay     []DailyFeedback   `json:"by_day"`
+	ByReason  []FeedbackReason  `json:"by_reason"`
+}
+
+// Folder groups a user's conversations; a conversation is in at most one folder
+type Folder struct {
+	ID                uuid.UUID `json:"id"`
+	Name              string    `json:"name"`
+	ConversationCount int       `json:"conversation_count"`
+	CreatedAt         time.Time `json:"created_at"`
+	UpdatedAt         time.Time `json:"updated_at"`
+}
+
+// Tag labels a user's conversations; a conversation can have many tags
+// ConversationCount is only set when listing tags
+type Tag struct {
+	ID                uuid.UUID `json:"id"`
+	Name              string    `json:"name"`
+	ConversationCount *int      `json:"conversation_count,omitempty"`
+}
+
+// LabelInput represents data for creating or renaming a folder or tag
+type LabelInput struct {
+	Name string `json:"name" validate:"required,min=1"`
+}
+
+// ConversationOrganize changes how a conversation is organized; nil fields are left unchanged
+// FolderID "" removes the conversation from its folder; TagIDs replaces all tags
+type ConversationOrganize struct {
+	Pinned   *bool        `json:"pinned,omitempty"`
+	Archived *bool        `json:"archived,omitempty"`
+	FolderID *string      `json:"folder_id,omitempty"`
+	TagIDs   *[]uuid.UUID `json:"tag_ids,omitempty"`
+}
+
+// Conversation list archive filters
+const (
+	ArchivedExclude = "exclude" // Default: hide archived conversations
+	ArchivedOnly    = "only"
+	ArchivedInclude = "include"
+)
+
+// ConversationFilter holds the filter, sort and paging options for listing conversations
+type ConversationFilter struct {
+	Archived string      // ArchivedExclude, ArchivedOnly or ArchivedInclude
+	Pinned   *bool       // nil: both
+	FolderID *uuid.UUID  // Only conversations in this folder
+	Unfiled  bool        // Only conversations without a folder
+	TagIDs   []uuid.UUID // Conversations having all of these tags
+	Sort     string      // updated_at, created_at, title or message_count
+	Desc     bool
+	Limit    int
+	Offset   int    // Ignored when Cursor is set
+	Cursor   string // Opaque position returned as next_cursor
+}
+
 // ConversationUpdate represents data for updating a conversation
 type ConversationUpdate struct {
 	Title string `json:"title" validate:"required,min=1"`
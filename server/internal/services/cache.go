@@ -42,9 +42,12 @@ import (
 	"encoding/hex"
 	"encoding/json"
 	"fmt"
+	"strconv"
 	"strings"
+	"sync"
 	"time"
 
+	"github.com/google/uuid"
 	"github.com/redis/go-redis/v9"
 )
 
@@ -54,6 +57,7 @@ type CacheService interface {
 	Get(ctx context.Context, key string, dest interface{}) error                            // Retrieve cached value
 	Set(ctx context.Context, key string, value interface{}, expiration time.Duration) error // Store value with TTL
 	Delete(ctx context.Context, key string) error                                           // Remove cached value
+	Increment(ctx context.Context, key string, expiration time.Duration) (int64, error)     // Atomically add one; the TTL starts with the first increment
 	Close() error                                                                           // Cleanup resources
 }
 
@@ -64,7 +68,8 @@ type CacheService interface {
 // MemoryCache provides local in-memory caching as fallback when Redis is unavailable
 // Ensures service continues to function with reduced performance rather than failing
 type MemoryCache struct {
-	store map[string]cacheEntry // Thread-safe not required - Go service is single-threaded per request
+	mu    sync.RWMutex          // Guards store - handlers and background jobs share the cache
+	store map[string]cacheEntry // Cached entries keyed by cache key
 }
 
 // cacheEntry represents a single cached item with expiration metadata
@@ -83,14 +88,18 @@ func NewMemoryCache() *MemoryCache {
 
 // Get retrieves a value from in-memory cache with automatic expiration cleanup
 func (m *MemoryCache) Get(ctx context.Context, key string, dest interface{}) error {
+	m.mu.RLock()
 	entry, exists := m.store[key]
+	m.mu.RUnlock()
 	if !exists {
 		return fmt.Errorf("key not found: %s", key)
 	}
 
 	// Check expiration and clean up expired entries automatically
 	if time.Now().After(entry.Expiration) {
+		m.mu.Lock()
 		delete(m.store, key) // Self-cleaning to prevent memory leaks
+		m.mu.Unlock()
 		return fmt.Errorf("key expired: %s", key)
 	}
 
@@ -107,23 +116,52 @@ func (m *MemoryCache) Set(ctx context.Context, key string, value interface{}, ex
 	}
 
 	// Store with absolute expiration time
+	m.mu.Lock()
 	m.store[key] = cacheEntry{
 		Value:      data,
 		Expiration: time.Now().Add(expiration),
 	}
+	m.mu.Unlock()
 
 	return nil
 }
 
 // Delete removes a value from in-memory cache
 func (m *MemoryCache) Delete(ctx context.Context, key string) error {
+	m.mu.Lock()
 	delete(m.store, key)
+	m.mu.Unlock()
 	return nil // In-memory operations cannot fail
 }
 
+// Increment adds one to a counter under the lock, so concurrent callers never lose an update
+func (m *MemoryCache) Increment(ctx context.Context, key string, expiration time.Duration) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := time.Now()
+	var count int64
+	entry, exists := m.store[key]
+	if exists && now.Before(entry.Expiration) {
+		if err := json.Unmarshal(entry.Value, &count); err != nil {
+			return 0, err
+		}
+	} else {
+		entry.Expiration = now.Add(expiration)
+	}
+
+	count++
+	entry.Value = []byte(strconv.FormatInt(count, 10))
+	m.store[key] = entry
+
+	return count, nil
+}
+
 // Close clears the in-memory cache and releases resources
 func (m *MemoryCache) Close() error {
+	m.mu.Lock()
 	m.store = make(map[string]cacheEntry) // Clear all entries
+	m.mu.Unlock()
 	return nil
 }
 
@@ -176,6 +214,20 @@ func (r *RedisCache) Delete(ctx context.Context, key string) error {
 	return r.client.Del(ctx, key).Err()
 }
 
+// incrementScript increments a counter and sets its TTL when the counter is created
+var incrementScript = redis.NewScript(`
+local count = redis.call("INCR", KEYS[1])
+if count == 1 then
+	redis.call("PEXPIRE", KEYS[1], ARGV[1])
+end
+return count
+`)
+
+// Increment adds one to a counter with INCR; the TTL is set in the same script so it can't be lost
+func (r *RedisCache) Increment(ctx context.Context, key string, expiration time.Duration) (int64, error) {
+	return incrementScript.Run(ctx, r.client, []string{key}, expiration.Milliseconds()).Int64()
+}
+
 // Close closes the Redis connection and releases resources
 func (r *RedisCache) Close() error {
 	return r.client.Close()
@@ -201,6 +253,13 @@ func GenerateCacheKey(message string, conversationContext string) string {
 	return "chat:" + hex.EncodeToString(hash[:])[:16]         // Prefix + first 16 chars for uniqueness
 }
 
+// ChatCacheKey is the cache key of the answer to a message in a conversation
+// Everything that reads or evicts cached answers builds the key here, from the parsed ID, so
+// the same conversation always maps to the same key whatever form the client sent its ID in
+func ChatCacheKey(message string, conversationID uuid.UUID) string {
+	return GenerateCacheKey(message, "conv_"+conversationID.String())
+}
+
 // normalizeMessage performs intelligent text normalization to maximize cache hits
 // This function is critical to the caching system's effectiveness
 func normalizeMessage(message string) string {
@@ -0,0 +1,200 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+
+	"article-chat-system/server/internal/config"
+	"article-chat-system/server/internal/database"
+	"article-chat-system/server/internal/fetcher"
+	"article-chat-system/server/internal/models"
+	"article-chat-system/server/internal/workers"
+	"github.com/google/uuid"
+)
+
+const (
+	// feedFetchTimeout bounds downloading and parsing one feed
+	feedFetchTimeout = time.Minute
+	// feedIngestTimeout bounds ingesting one feed entry
+	feedIngestTimeout = 2 * time.Minute
+	// feedLease is how long a claimed feed stays claimed if its poll never finishes
+	feedLease = 15 * 60
+	// maxFeedBackoffDoublings caps the exponent of the error backoff
+	maxFeedBackoffDoublings = 16
+)
+
+// FeedPoller polls RSS and Atom subscriptions and ingests their new entries
+// Each feed is polled on its own interval with conditional requests. Entries whose canonical URL
+// already has an article are skipped; the others go through the ArticleIngester on the article
+// pool, tagged with the feed's category. Failed polls back off exponentially.
+type FeedPoller struct {
+	fetcher  *fetcher.ArticleFetcher
+	ingester *ArticleIngester
+	db       *database.DB
+	pools    *workers.PoolManager
+	config   config.FeedConfig
+}
+
+// NewFeedPoller creates a poller; polls run on the general worker pool
+func NewFeedPoller(cfg config.FeedConfig, articleFetcher *fetcher.ArticleFetcher, ingester *ArticleIngester, db *database.DB, pools *workers.PoolManager) *FeedPoller {
+	return &FeedPoller{
+		fetcher:  articleFetcher,
+		ingester: ingester,
+		db:       db,
+		pools:    pools,
+		config:   cfg,
+	}
+}
+
+// Start polls due feeds every poll interval until ctx is cancelled
+func (p *FeedPoller) Start(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(time.Duration(p.config.PollInterval) * time.Second)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				p.pools.SubmitTask(func() {
+					p.pollDue(ctx)
+				})
+			}
+		}
+	}()
+}
+
+// pollDue polls one batch of due feeds
+func (p *FeedPoller) pollDue(ctx context.Context) {
+	feeds, err := p.db.ClaimDueFeeds(ctx, feedLease, p.config.BatchSize)
+	if err != nil {
+		slog.Warn("Failed to load feeds due for polling", "error", err)
+		return
+	}
+
+	for i := range feeds {
+		if ctx.Err() != nil {
+			return
+		}
+		p.poll(ctx, &feeds[i])
+	}
+}
+
+// poll fetches one feed, submits its new entries and records the outcome
+func (p *FeedPoller) poll(ctx context.Context, feed *models.Feed) {
+	feedID, err := uuid.Parse(feed.ID)
+	if err != nil {
+		return
+	}
+
+	fetchCtx, cancel := context.WithTimeout(ctx, feedFetchTimeout)
+	validators := fetcher.Validators{ETag: feed.ETag, LastModified: feed.LastModified}
+	parsed, validators, err := p.fetcher.FetchFeed(fetchCtx, feed.URL, validators)
+	cancel()
+	if errors.Is(err, fetcher.ErrNotModified) {
+		slog.Debug("Feed not modified", "feed_id", feed.ID)
+		p.recordPoll(ctx, feedID, feed.Title, validators, feed.PollInterval)
+		return
+	}
+	if err != nil {
+		delay := p.backoff(feed.PollInterval, feed.ErrorCount+1)
+		slog.Warn("Failed to poll feed", "error", err, "feed_id", feed.ID, "url", feed.URL,
+			"errors", feed.ErrorCount+1, "retry_in", delay)
+		if recordErr := p.db.RecordFeedError(ctx, feedID, err.Error(), delay); recordErr != nil {
+			slog.Warn("Failed to record feed error", "error", recordErr, "feed_id", feed.ID)
+		}
+		return
+	}
+
+	entries, err := p.newEntries(ctx, parsed.Entries)
+	if err != nil {
+		// Retried after the lease; nothing was submitted
+		slog.Warn("Failed to check feed entries", "error", err, "feed_id", feed.ID)
+		return
+	}
+
+	var tags []string
+	if feed.Category != "" {
+		tags = []string{feed.Category}
+	}
+	for _, entry := range entries {
+		p.submit(ctx, feed, entry, tags)
+	}
+
+	p.recordPoll(ctx, feedID, parsed.Title, validators, feed.PollInterval)
+	slog.Info("Feed polled", "feed_id", feed.ID, "entries", len(parsed.Entries), "new", len(entries))
+}
+
+// newEntries returns up to MaxItems entries without an article, in feed order
+func (p *FeedPoller) newEntries(ctx context.Context, entries []fetcher.FeedEntry) ([]fetcher.FeedEntry, error) {
+	canonicalURLs := make([]string, len(entries))
+	for i, entry := range entries {
+		canonicalURLs[i], _ = fetcher.CanonicalURL(entry.URL)
+	}
+
+	existing, err := p.db.ExistingArticleURLs(ctx, canonicalURLs)
+	if err != nil {
+		return nil, err
+	}
+
+	var fresh []fetcher.FeedEntry
+	for i, entry := range entries {
+		canonicalURL := canonicalURLs[i]
+		if canonicalURL == "" || existing[canonicalURL] {
+			continue
+		}
+		// Feeds sometimes list an entry twice
+		existing[canonicalURL] = true
+
+		fresh = append(fresh, entry)
+		if len(fresh) == p.config.MaxItems {
+			break
+		}
+	}
+
+	return fresh, nil
+}
+
+// submit ingests one feed entry on the article pool
+func (p *FeedPoller) submit(ctx context.Context, feed *models.Feed, entry fetcher.FeedEntry, tags []string) {
+	p.pools.SubmitArticleTask(workers.ArticleTask{
+		Owner:    "feeds", // All feeds share one turn per round, like scheduled retries
+		Priority: workers.PriorityBackground,
+		Label:    entry.URL,
+		Run: func() {
+			ingestCtx, cancel := context.WithTimeout(ctx, feedIngestTimeout)
+			defer cancel()
+
+			response, err := p.ingester.Ingest(ingestCtx, IngestRequest{
+				URL:    entry.URL,
+				Source: ArticleSourceFeed,
+				FeedID: feed.ID,
+				Tags:   tags,
+			})
+			if err != nil {
+				slog.Warn("Failed to ingest feed entry", "error", err, "feed_id", feed.ID, "url", entry.URL)
+				return
+			}
+
+			slog.Info("Feed entry ingested", "feed_id", feed.ID, "url", entry.URL,
+				"article_id", response.ID, "duplicate", response.Duplicate)
+		},
+	})
+}
+
+// recordPoll stores a successful poll; the next one is one interval away
+func (p *FeedPoller) recordPoll(ctx context.Context, feedID uuid.UUID, title string, validators fetcher.Validators, interval int) {
+	if err := p.db.RecordFeedPoll(ctx, feedID, title, validators.ETag, validators.LastModified, interval); err != nil {
+		slog.Warn("Failed to record feed poll", "error", err, "feed_id", feedID)
+	}
+}
+
+// backoff returns the delay in seconds after the given number of consecutive errors
+// The interval doubles with each error, up to the configured maximum.
+func (p *FeedPoller) backoff(interval, errorCount int) int {
+	delay := interval << min(errorCount, maxFeedBackoffDoublings)
+	return max(min(delay, p.config.MaxBackoff), interval)
+}
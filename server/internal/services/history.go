@@ -0,0 +1,171 @@
+package services
+
+import (
+	"context"
+	"log/slog"
+	"time"
+	"unicode/utf8"
+
+	"article-chat-system/server/internal/config"
+	"article-chat-system/server/internal/database"
+	"article-chat-system/server/internal/models"
+	"article-chat-system/server/internal/workers"
+	"github.com/google/uuid"
+)
+
+// HistoryWindow bounds the conversation history sent to the RAG service
+// The window keeps the most recent turns within a turn limit and an estimated token budget.
+// With summaries enabled, older turns are folded into a rolling summary stored on the
+// conversation by a background job and sent alongside the window.
+type HistoryWindow struct {
+	ragClient *RAGClient
+	db        *database.DB
+	pools     *workers.PoolManager
+	config    config.HistoryConfig
+}
+
+// NewHistoryWindow creates a history window; summaries run on the general worker pool
+func NewHistoryWindow(cfg config.HistoryConfig, ragClient *RAGClient, db *database.DB, pools *workers.PoolManager) *HistoryWindow {
+	return &HistoryWindow{
+		ragClient: ragClient,
+		db:        db,
+		pools:     pools,
+		config:    cfg,
+	}
+}
+
+// Build selects the messages to send as history and the summary of the turns before them
+// messages is a branch path, oldest first. The summary is "" when summaries are disabled or
+// no stored summary applies to the dropped turns.
+func (w *HistoryWindow) Build(ctx context.Context, conversationID uuid.UUID, messages []models.Message) ([]models.Message, string) {
+	older, recent := w.split(messages)
+	if len(older) == 0 || !w.config.Summaries {
+		return recent, ""
+	}
+
+	summary, err := w.db.GetConversationSummary(ctx, conversationID)
+	if err != nil {
+		slog.Warn("Failed to load conversation summary", "error", err, "conversation_id", conversationID)
+		return recent, ""
+	}
+	if coveredMessages(older, summary) == 0 {
+		return recent, ""
+	}
+
+	return recent, summary.Summary
+}
+
+// ScheduleSummary refreshes the conversation's summary in the background once enough
+// turns have left the window since the last refresh
+func (w *HistoryWindow) ScheduleSummary(conversationID uuid.UUID) {
+	if !w.config.Summaries {
+		return
+	}
+
+	w.pools.SubmitTask(func() {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+		defer cancel()
+
+		if err := w.refreshSummary(ctx, conversationID); err != nil {
+			slog.Warn("Failed to refresh conversation summary", "error", err, "conversation_id", conversationID)
+		}
+	})
+}
+
+// refreshSummary folds the dropped turns not yet covered into the stored summary
+func (w *HistoryWindow) refreshSummary(ctx context.Context, conversationID uuid.UUID) error {
+	messages, err := w.db.GetConversationMessages(ctx, conversationID)
+	if err != nil {
+		return err
+	}
+
+	older, _ := w.split(messages)
+	if len(older) == 0 {
+		return nil
+	}
+
+	summary, err := w.db.GetConversationSummary(ctx, conversationID)
+	if err != nil {
+		return err
+	}
+
+	// A summary of another branch does not apply; start over from the first message
+	covered := coveredMessages(older, summary)
+	pending := older[covered:]
+	if len(pending) < w.config.SummaryMinMessages {
+		return nil
+	}
+
+	previous := ""
+	if covered > 0 {
+		previous = summary.Summary
+	}
+
+	updated, err := w.ragClient.SummarizeConversation(ctx, previous, pending)
+	if err != nil {
+		return err
+	}
+	if updated == "" {
+		return nil
+	}
+
+	last := pending[len(pending)-1]
+	if err := w.db.UpdateConversationSummary(ctx, conversationID, updated, last.ID); err != nil {
+		return err
+	}
+
+	slog.Debug("Conversation summary refreshed", "conversation_id", conversationID, "summarized_messages", len(pending))
+	return nil
+}
+
+// split divides a branch path into the turns outside the window and those inside it
+// Walking back from the newest message, messages are kept until the turn limit or token
+// budget is reached. The window always starts at a question.
+func (w *HistoryWindow) split(messages []models.Message) (older, recent []models.Message) {
+	start := len(messages)
+	turns, tokens := 0, 0
+	for i := len(messages) - 1; i >= 0; i-- {
+		if w.config.MaxTurns > 0 && turns == w.config.MaxTurns {
+			break
+		}
+
+		cost := estimateTokens(messages[i].Content)
+		if w.config.MaxTokens > 0 && tokens+cost > w.config.MaxTokens {
+			break
+		}
+
+		tokens += cost
+		start = i
+		if messages[i].Role == "user" {
+			turns++
+		}
+	}
+
+	// Never open the window with an answer whose question was dropped
+	for start < len(messages) && messages[start].Role != "user" {
+		start++
+	}
+
+	return messages[:start], messages[start:]
+}
+
+// coveredMessages returns how many of the older messages the summary covers
+// Zero when there is no summary or it was written for another branch.
+func coveredMessages(older []models.Message, summary *models.ConversationSummary) int {
+	if summary.Summary == "" || summary.MessageID == nil {
+		return 0
+	}
+
+	for i := range older {
+		if older[i].ID == *summary.MessageID {
+			return i + 1
+		}
+	}
+
+	return 0
+}
+
+// estimateTokens approximates the token count of text at four characters per token
+func estimateTokens(text string) int {
+	return (utf8.RuneCountInString(text) + 3) / 4
+}
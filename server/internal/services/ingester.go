@@ -0,0 +1,379 @@
+package services
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"article-chat-system/server/internal/config"
+	"article-chat-system/server/internal/database"
+	"article-chat-system/server/internal/errors"
+	"article-chat-system/server/internal/fetcher"
+	"article-chat-system/server/internal/models"
+	"github.com/google/uuid"
+)
+
+const (
+	// failureRecordTimeout bounds recording a failure once the ingestion context may have expired
+	failureRecordTimeout = 10 * time.Second
+	// maxRetryDoublings caps the exponent of the retry backoff
+	maxRetryDoublings = 16
+)
+
+// Article sources recorded on ingested articles
+const (
+	ArticleSourceUser   = "user_submitted"
+	ArticleSourceFeed   = "feed"
+	ArticleSourceUpload = "upload"
+)
+
+// IngestRequest describes an article to add to the knowledge base
+type IngestRequest struct {
+	URL    string
+	Source string // ArticleSourceUser or ArticleSourceFeed
+	FeedID string // Feed the entry came from, if any
+	Tags   []string
+	Custom map[string]string
+
+	SubmittedBy string // User who submitted the URL, if any
+}
+
+// ArticleIngester adds articles to the knowledge base
+// Submitted URLs are canonicalized and checked for duplicates before anything is fetched. The
+// page is then fetched and extracted, checked again by declared canonical URL and content hash,
+// stored and sent to the RAG service for chunking and embedding. Failures are stored on the
+// article, which is retried with exponential backoff while retries are enabled.
+type ArticleIngester struct {
+	fetcher   *fetcher.ArticleFetcher
+	ragClient *RAGClient
+	db        *database.DB
+	cache     CacheService
+	retry     config.RetryConfig
+}
+
+// NewArticleIngester creates an ingester
+func NewArticleIngester(articleFetcher *fetcher.ArticleFetcher, ragClient *RAGClient, db *database.DB, cache CacheService, retry config.RetryConfig) *ArticleIngester {
+	return &ArticleIngester{
+		fetcher:   articleFetcher,
+		ragClient: ragClient,
+		db:        db,
+		cache:     cache,
+		retry:     retry,
+	}
+}
+
+// Known returns the response for an article already added under a variant of articleURL,
+// or nil when the URL is new
+func (i *ArticleIngester) Known(ctx context.Context, articleURL string) (*models.AddArticleResponse, error) {
+	// Variants of one article's URL share the cache key and the duplicate check
+	canonicalURL, err := fetcher.CanonicalURL(articleURL)
+	if err != nil {
+		return nil, errors.New(errors.ErrValidationFailed, err.Error())
+	}
+
+	cacheKey := GenerateArticleCacheKey(canonicalURL)
+
+	var cachedResponse models.AddArticleResponse
+	if err := i.cache.Get(ctx, cacheKey, &cachedResponse); err == nil {
+		slog.Info("Article cache hit",
+			"url", articleURL,
+			"cache_key", cacheKey[:12]+"...",
+			"cached_article_id", cachedResponse.ID)
+
+		cachedResponse.Cached = true
+		cachedResponse.Message = "Article already processed (from cache)"
+		return &cachedResponse, nil
+	}
+
+	slog.Debug("Article cache miss",
+		"url", articleURL,
+		"cache_key", cacheKey[:12]+"...")
+
+	existing, err := i.db.FindDuplicateArticle(ctx, canonicalURL, "")
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil {
+		slog.Info("Duplicate article submitted", "url", articleURL, "article_id", existing.ID)
+		return duplicateArticleResponse(existing), nil
+	}
+
+	return nil, nil
+}
+
+// Ingest fetches, stores and indexes an article unless it is already in the knowledge base
+// Fetch failures return ErrArticleFetchFailed and indexing failures ErrEmbeddingsError, both
+// with the failed stage in the details.
+func (i *ArticleIngester) Ingest(ctx context.Context, req IngestRequest) (*models.AddArticleResponse, error) {
+	if known, err := i.Known(ctx, req.URL); err != nil || known != nil {
+		return known, err
+	}
+
+	// Fetch and extract first so the article record has its title, content and statistics
+	article, err := i.fetcher.FetchArticle(ctx, req.URL)
+	if err != nil {
+		return nil, i.recordFetchFailure(ctx, req, err)
+	}
+	article.Source = req.Source
+	article.FeedID = req.FeedID
+	article.SubmittedBy = req.SubmittedBy
+	article.Metadata.Tags = req.Tags
+	article.Metadata.Custom = req.Custom
+
+	response, err := i.store(ctx, article)
+	if err != nil || response.Duplicate {
+		return response, err
+	}
+
+	// Cache the successful response under the submitted URL with 24 hour TTL; a failure only
+	// costs a database lookup
+	submittedURL, _ := fetcher.CanonicalURL(req.URL)
+	cacheKey := GenerateArticleCacheKey(submittedURL)
+	if cacheErr := i.cache.Set(ctx, cacheKey, response, 24*time.Hour); cacheErr != nil {
+		slog.Warn("Failed to cache article response", "error", cacheErr, "cache_key", cacheKey[:12]+"...")
+	}
+
+	return response, nil
+}
+
+// Forget drops the cached responses for a deleted article, so submitting its URL again
+// ingests it instead of answering with the deleted article's ID
+func (i *ArticleIngester) Forget(ctx context.Context, article *models.Article) {
+	// Responses are cached under the submitted URL, which may differ from the page's canonical URL
+	urls := []string{article.CanonicalURL}
+	if submittedURL, err := fetcher.CanonicalURL(article.URL); err == nil && submittedURL != article.CanonicalURL {
+		urls = append(urls, submittedURL)
+	}
+
+	for _, url := range urls {
+		if url == "" {
+			continue
+		}
+		cacheKey := GenerateArticleCacheKey(url)
+		if err := i.cache.Delete(ctx, cacheKey); err != nil {
+			slog.Warn("Failed to evict article response", "error", err, "cache_key", cacheKey[:12]+"...")
+		}
+	}
+}
+
+// IngestDocument stores and indexes an uploaded document extracted by ExtractDocument; the caller
+// sets its SubmittedBy. Documents are deduplicated by content hash, so the same text uploaded twice is stored once.
+func (i *ArticleIngester) IngestDocument(ctx context.Context, article *models.Article, tags []string, custom map[string]string) (*models.AddArticleResponse, error) {
+	article.Source = ArticleSourceUpload
+	article.Metadata.Tags = tags
+	article.Metadata.Custom = custom
+
+	return i.store(ctx, article)
+}
+
+// store creates an extracted article and sends it to the RAG service, unless an article with the
+// same canonical URL or content already exists
+func (i *ArticleIngester) store(ctx context.Context, article *models.Article) (*models.AddArticleResponse, error) {
+	// The page may declare another canonical URL, and the same text may be published elsewhere
+	existing, err := i.db.FindDuplicateArticle(ctx, article.CanonicalURL, article.ContentHash)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil {
+		slog.Info("Duplicate article content", "url", article.URL, "article_id", existing.ID)
+		return duplicateArticleResponse(existing), nil
+	}
+
+	article.Status = models.ArticleStatusIndexing
+	created, err := i.db.CreateArticle(ctx, article)
+	if err != nil {
+		return nil, err
+	}
+	if !created {
+		// Submitted concurrently under another variant of the URL
+		existing, err := i.db.FindDuplicateArticle(ctx, article.CanonicalURL, "")
+		if err != nil || existing == nil {
+			return nil, errors.New(errors.ErrDatabaseError, "Failed to store article")
+		}
+		return duplicateArticleResponse(existing), nil
+	}
+	articleID := uuid.MustParse(article.ID)
+
+	// Send the extracted content to the RAG service for chunking and embedding
+	slog.Info("Forwarding article to RAG service", "url", article.URL, "article_id", article.ID, "source", article.Source)
+	metadata := map[string]interface{}{
+		"article_id":   article.ID,
+		"source":       article.Source,
+		"submitted_at": article.FetchedAt,
+	}
+	if article.FeedID != "" {
+		metadata["feed_id"] = article.FeedID
+	}
+
+	chunks, err := i.ragClient.ProcessArticle(ctx, article, metadata)
+	if err != nil {
+		slog.Error("Failed to index article", "error", err, "url", article.URL, "article_id", article.ID)
+		i.recordFailure(ctx, article, "index", err)
+
+		return nil, errors.NewWithDetails(
+			errors.ErrEmbeddingsError,
+			"Failed to index article",
+			map[string]string{"stage": "index", "article_id": article.ID, "reason": err.Error()},
+		)
+	}
+
+	if err := i.db.UpdateArticleStatus(ctx, articleID, models.ArticleStatusIndexed, chunks); err != nil {
+		slog.Warn("Failed to record article status", "error", err, "article_id", article.ID)
+	}
+
+	return &models.AddArticleResponse{
+		ID:      article.ID,
+		Status:  "success",
+		Message: "Article processed and indexed successfully",
+	}, nil
+}
+
+// Reingest runs ingestion again for a stored article
+// An article whose fetch failed is fetched again; stored content is re-indexed, replacing its
+// chunks. An indexed article that fails to re-index stays indexed with its previous chunks.
+func (i *ArticleIngester) Reingest(ctx context.Context, article *models.Article) (*models.AddArticleResponse, error) {
+	if article.Content == "" {
+		return i.refetch(ctx, article)
+	}
+	articleID := uuid.MustParse(article.ID)
+
+	metadata := map[string]interface{}{
+		"article_id":   article.ID,
+		"source":       article.Source,
+		"reindexed_at": time.Now(),
+	}
+	if article.FeedID != "" {
+		metadata["feed_id"] = article.FeedID
+	}
+
+	chunks, err := i.ragClient.ReindexArticle(ctx, article, metadata)
+	if err != nil {
+		slog.Error("Failed to re-index article", "error", err, "article_id", article.ID)
+		if article.Status != models.ArticleStatusIndexed {
+			i.recordFailure(ctx, article, "index", err)
+		}
+
+		return nil, errors.NewWithDetails(
+			errors.ErrEmbeddingsError,
+			"Failed to index article",
+			map[string]string{"stage": "index", "article_id": article.ID, "reason": err.Error()},
+		)
+	}
+
+	if err := i.db.UpdateArticleStatus(ctx, articleID, models.ArticleStatusIndexed, chunks); err != nil {
+		slog.Warn("Failed to record article status", "error", err, "article_id", article.ID)
+	}
+
+	return &models.AddArticleResponse{
+		ID:      article.ID,
+		Status:  "success",
+		Message: "Article re-indexed successfully",
+	}, nil
+}
+
+// refetch fetches an article whose fetch failed, keeping its ID, canonical URL and submitter data
+func (i *ArticleIngester) refetch(ctx context.Context, failed *models.Article) (*models.AddArticleResponse, error) {
+	article, err := i.fetcher.FetchArticle(ctx, failed.URL)
+	if err != nil {
+		i.recordFailure(ctx, failed, "fetch", err)
+		return nil, errors.NewWithDetails(
+			errors.ErrArticleFetchFailed,
+			"Failed to fetch article",
+			map[string]string{"stage": "fetch", "article_id": failed.ID, "url": failed.URL, "reason": err.Error()},
+		)
+	}
+
+	// The page may declare a canonical URL or carry text that already has an article, which
+	// then supersedes the failed one
+	existing, err := i.db.FindDuplicateArticle(ctx, article.CanonicalURL, article.ContentHash)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil {
+		slog.Info("Failed article superseded by duplicate", "article_id", failed.ID, "duplicate_id", existing.ID)
+		if err := i.db.DeleteArticle(ctx, uuid.MustParse(failed.ID)); err != nil {
+			slog.Warn("Failed to delete superseded article", "error", err, "article_id", failed.ID)
+		}
+		return duplicateArticleResponse(existing), nil
+	}
+
+	article.ID = failed.ID
+	article.CanonicalURL = failed.CanonicalURL
+	article.Source = failed.Source
+	article.FeedID = failed.FeedID
+	article.SubmittedBy = failed.SubmittedBy
+	article.Metadata.Tags = failed.Metadata.Tags
+	article.Metadata.Custom = failed.Metadata.Custom
+	article.Attempts = failed.Attempts
+
+	return i.store(ctx, article)
+}
+
+// recordFetchFailure stores a failed article for a URL that could not be fetched, so that it can
+// be retried, and returns the fetch error
+func (i *ArticleIngester) recordFetchFailure(ctx context.Context, req IngestRequest, fetchErr error) error {
+	details := map[string]string{"stage": "fetch", "url": req.URL, "reason": fetchErr.Error()}
+
+	// Known has validated the URL
+	canonicalURL, _ := fetcher.CanonicalURL(req.URL)
+	article := &models.Article{
+		ID:           uuid.New().String(),
+		URL:          req.URL,
+		CanonicalURL: canonicalURL,
+		Source:       req.Source,
+		FeedID:       req.FeedID,
+		SubmittedBy:  req.SubmittedBy,
+		Status:       models.ArticleStatusFailed,
+		Metadata:     models.Metadata{Tags: req.Tags, Custom: req.Custom},
+	}
+
+	recordCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), failureRecordTimeout)
+	defer cancel()
+
+	created, err := i.db.CreateArticle(recordCtx, article)
+	if err != nil {
+		slog.Warn("Failed to record failed article", "error", err, "url", req.URL)
+	} else if created {
+		i.recordFailure(recordCtx, article, "fetch", fetchErr)
+		details["article_id"] = article.ID
+	}
+
+	return errors.NewWithDetails(errors.ErrArticleFetchFailed, "Failed to fetch article", details)
+}
+
+// recordFailure marks an article failed with the error of the failed stage and schedules its
+// next automatic retry
+func (i *ArticleIngester) recordFailure(ctx context.Context, article *models.Article, stage string, cause error) {
+	// The failure is often the ingestion context expiring
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), failureRecordTimeout)
+	defer cancel()
+
+	attempts := article.Attempts + 1
+	delay := i.retryDelay(attempts)
+	if err := i.db.RecordArticleFailure(ctx, uuid.MustParse(article.ID), stage+": "+cause.Error(), attempts, delay); err != nil {
+		slog.Warn("Failed to record article failure", "error", err, "article_id", article.ID)
+		return
+	}
+
+	slog.Info("Article ingestion failed", "article_id", article.ID, "stage", stage, "attempts", attempts, "retry_in", delay)
+}
+
+// retryDelay returns the seconds until the automatic retry after the given number of failed
+// attempts, or 0 when retries are disabled or exhausted
+func (i *ArticleIngester) retryDelay(attempts int) int {
+	if !i.retry.Enabled || attempts >= i.retry.MaxAttempts {
+		return 0
+	}
+	delay := i.retry.BaseDelay << min(attempts-1, maxRetryDoublings)
+	return min(delay, i.retry.MaxDelay)
+}
+
+// duplicateArticleResponse points a submission at the article already holding its URL or content
+func duplicateArticleResponse(existing *models.Article) *models.AddArticleResponse {
+	return &models.AddArticleResponse{
+		ID:        existing.ID,
+		Status:    "success",
+		Message:   "Article already in the knowledge base",
+		Duplicate: true,
+	}
+}
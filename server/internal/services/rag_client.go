@@ -28,6 +28,7 @@ type RAGChatRequest struct {
 	Query               string               `json:"query"`
 	ConversationID      string               `json:"conversationId"`
 	ConversationHistory []models.ChatMessage `json:"conversationHistory,omitempty"`
+	ConversationSummary string               `json:"conversationSummary,omitempty"` // Summary of turns older than the history
 	Stream              bool                 `json:"stream"`
 }
 
@@ -43,9 +44,45 @@ type RAGChatResponse struct {
 // RAGArticleRequest represents the request to process an article
 type RAGArticleRequest struct {
 	URL      string                 `json:"url"`
+	Title    string                 `json:"title,omitempty"`
+	Content  string                 `json:"content,omitempty"` // Extracted text; the RAG service fetches the URL when empty
+	Replace  bool                   `json:"replace,omitempty"` // Replace the chunks already stored for the URL
 	Metadata map[string]interface{} `json:"metadata,omitempty"`
 }
 
+// RAGArticleResponse represents the result of indexing an article
+type RAGArticleResponse struct {
+	Chunks int `json:"chunks"`
+}
+
+// RAGTitleRequest represents the request to title a conversation from its first exchange
+type RAGTitleRequest struct {
+	Query    string `json:"query"`
+	Response string `json:"response"`
+}
+
+// RAGTitleResponse represents the generated conversation title
+type RAGTitleResponse struct {
+	Title string `json:"title"`
+}
+
+// RAGSummaryRequest represents the request to fold older turns into a conversation summary
+type RAGSummaryRequest struct {
+	PreviousSummary string              `json:"previousSummary,omitempty"`
+	Messages        []RAGSummaryMessage `json:"messages"`
+}
+
+// RAGSummaryMessage is one turn to be summarized
+type RAGSummaryMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+// RAGSummaryResponse represents the updated conversation summary
+type RAGSummaryResponse struct {
+	Summary string `json:"summary"`
+}
+
 // RAGStatusResponse represents the service status
 type RAGStatusResponse struct {
 	Status            string                 `json:"status"`
@@ -88,13 +125,14 @@ func NewRAGClient(cfg config.RAGServiceConfig) *RAGClient {
 }
 
 // ProcessChat sends a chat query to the RAG service
-func (r *RAGClient) ProcessChat(ctx context.Context, query string, conversationID string, history []models.ChatMessage) (*models.ChatResponse, error) {
+func (r *RAGClient) ProcessChat(ctx context.Context, query string, conversationID string, history []models.ChatMessage, summary string) (*models.ChatResponse, error) {
 	startTime := time.Now()
 
 	request := RAGChatRequest{
 		Query:               query,
 		ConversationID:      conversationID,
 		ConversationHistory: history,
+		ConversationSummary: summary,
 		Stream:              false,
 	}
 
@@ -137,11 +175,12 @@ func (r *RAGClient) ProcessChat(ctx context.Context, query string, conversationI
 }
 
 // ProcessChatStream sends a streaming chat query to the RAG service
-func (r *RAGClient) ProcessChatStream(ctx context.Context, query string, conversationID string, history []models.ChatMessage) (<-chan models.StreamResponse, error) {
+func (r *RAGClient) ProcessChatStream(ctx context.Context, query string, conversationID string, history []models.ChatMessage, summary string) (<-chan models.StreamResponse, error) {
 	request := RAGChatRequest{
 		Query:               query,
 		ConversationID:      conversationID,
 		ConversationHistory: history,
+		ConversationSummary: summary,
 		Stream:              true,
 	}
 
@@ -270,28 +309,90 @@ func (r *RAGClient) ProcessChatStream(ctx context.Context, query string, convers
 	return responseChan, nil
 }
 
-// ProcessArticle sends an article to the RAG service for processing
-func (r *RAGClient) ProcessArticle(ctx context.Context, url string, metadata map[string]interface{}) error {
+// GenerateTitle asks the RAG service for a short title summarizing a question and its answer
+func (r *RAGClient) GenerateTitle(ctx context.Context, question, answer string) (string, error) {
+	resp, err := r.client.R().
+		SetContext(ctx).
+		SetBody(RAGTitleRequest{Query: question, Response: answer}).
+		SetResult(&RAGTitleResponse{}).
+		Post("/api/chat/title")
+
+	if err != nil {
+		return "", fmt.Errorf("title request failed: %w", err)
+	}
+
+	if resp.StatusCode() != http.StatusOK {
+		return "", fmt.Errorf("rag service error: status %d, body: %s", resp.StatusCode(), string(resp.Body()))
+	}
+
+	return resp.Result().(*RAGTitleResponse).Title, nil
+}
+
+// SummarizeConversation asks the RAG service to fold messages into the previous summary
+func (r *RAGClient) SummarizeConversation(ctx context.Context, previousSummary string, messages []models.Message) (string, error) {
+	request := RAGSummaryRequest{
+		PreviousSummary: previousSummary,
+		Messages:        make([]RAGSummaryMessage, len(messages)),
+	}
+	for i, msg := range messages {
+		request.Messages[i] = RAGSummaryMessage{Role: msg.Role, Content: msg.Content}
+	}
+
+	resp, err := r.client.R().
+		SetContext(ctx).
+		SetBody(request).
+		SetResult(&RAGSummaryResponse{}).
+		Post("/api/chat/summarize")
+
+	if err != nil {
+		return "", fmt.Errorf("summary request failed: %w", err)
+	}
+
+	if resp.StatusCode() != http.StatusOK {
+		return "", fmt.Errorf("rag service error: status %d, body: %s", resp.StatusCode(), string(resp.Body()))
+	}
+
+	return resp.Result().(*RAGSummaryResponse).Summary, nil
+}
+
+// ProcessArticle sends an extracted article to the RAG service for chunking and embedding
+// Returns the number of chunks indexed.
+func (r *RAGClient) ProcessArticle(ctx context.Context, article *models.Article, metadata map[string]interface{}) (int, error) {
+	return r.processArticle(ctx, article, metadata, false)
+}
+
+// ReindexArticle indexes new content of an article, replacing its previously stored chunks
+// The old chunks stay searchable if indexing fails.
+func (r *RAGClient) ReindexArticle(ctx context.Context, article *models.Article, metadata map[string]interface{}) (int, error) {
+	return r.processArticle(ctx, article, metadata, true)
+}
+
+func (r *RAGClient) processArticle(ctx context.Context, article *models.Article, metadata map[string]interface{}, replace bool) (int, error) {
 	request := RAGArticleRequest{
-		URL:      url,
+		URL:      article.URL,
+		Title:    article.Title,
+		Content:  article.Content,
+		Replace:  replace,
 		Metadata: metadata,
 	}
 
 	resp, err := r.client.R().
 		SetContext(ctx).
 		SetBody(request).
+		SetResult(&RAGArticleResponse{}).
 		Post("/api/articles/process")
 
 	if err != nil {
-		return fmt.Errorf("failed to process article: %w", err)
+		return 0, fmt.Errorf("failed to process article: %w", err)
 	}
 
 	if resp.StatusCode() != http.StatusOK && resp.StatusCode() != http.StatusAccepted {
-		return fmt.Errorf("failed to process article: status %d, body: %s", resp.StatusCode(), string(resp.Body()))
+		return 0, fmt.Errorf("failed to process article: status %d, body: %s", resp.StatusCode(), string(resp.Body()))
 	}
 
-	slog.Info("Article sent to RAG service for processing", "url", url)
-	return nil
+	chunks := resp.Result().(*RAGArticleResponse).Chunks
+	slog.Info("Article indexed by RAG service", "url", article.URL, "chunks", chunks, "replace", replace)
+	return chunks, nil
 }
 
 // HealthCheck verifies the RAG service is accessible
@@ -0,0 +1,131 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+
+	"article-chat-system/server/internal/config"
+	"article-chat-system/server/internal/database"
+	"article-chat-system/server/internal/fetcher"
+	"article-chat-system/server/internal/models"
+	"article-chat-system/server/internal/workers"
+	"github.com/google/uuid"
+)
+
+// refreshTimeout bounds re-fetching and re-indexing a single article
+const refreshTimeout = 2 * time.Minute
+
+// ArticleRefresher re-fetches indexed articles on their refresh interval
+// Requests are conditional (ETag/Last-Modified) where the extractor supports it. Content whose
+// hash changed is re-embedded by the RAG service, replacing the old chunks, and the article's
+// version is bumped with the replaced content archived.
+type ArticleRefresher struct {
+	fetcher   *fetcher.ArticleFetcher
+	ragClient *RAGClient
+	db        *database.DB
+	pools     *workers.PoolManager
+	config    config.RefreshConfig
+}
+
+// NewArticleRefresher creates a refresher; polls run on the general worker pool
+func NewArticleRefresher(cfg config.RefreshConfig, articleFetcher *fetcher.ArticleFetcher, ragClient *RAGClient, db *database.DB, pools *workers.PoolManager) *ArticleRefresher {
+	return &ArticleRefresher{
+		fetcher:   articleFetcher,
+		ragClient: ragClient,
+		db:        db,
+		pools:     pools,
+		config:    cfg,
+	}
+}
+
+// Start polls for due articles every poll interval until ctx is cancelled
+func (r *ArticleRefresher) Start(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(time.Duration(r.config.PollInterval) * time.Second)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				r.pools.SubmitTask(func() {
+					r.refreshDue(ctx)
+				})
+			}
+		}
+	}()
+}
+
+// refreshDue checks one batch of due articles
+func (r *ArticleRefresher) refreshDue(ctx context.Context) {
+	articles, err := r.db.ClaimArticlesForRefresh(ctx, r.config.Interval, r.config.BatchSize)
+	if err != nil {
+		slog.Warn("Failed to load articles due for refresh", "error", err)
+		return
+	}
+
+	for i := range articles {
+		if ctx.Err() != nil {
+			return
+		}
+
+		articleCtx, cancel := context.WithTimeout(ctx, refreshTimeout)
+		if err := r.refresh(articleCtx, &articles[i]); err != nil {
+			slog.Warn("Failed to refresh article", "error", err, "article_id", articles[i].ID, "url", articles[i].URL)
+		}
+		cancel()
+	}
+}
+
+// refresh re-fetches one article and re-indexes it when its content changed
+func (r *ArticleRefresher) refresh(ctx context.Context, current *models.Article) error {
+	articleID, err := uuid.Parse(current.ID)
+	if err != nil {
+		return err
+	}
+
+	updated, err := r.fetcher.RefreshArticle(ctx, current)
+	if errors.Is(err, fetcher.ErrNotModified) {
+		slog.Debug("Article not modified", "article_id", current.ID)
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	if updated.ContentHash == current.ContentHash {
+		slog.Debug("Article content unchanged", "article_id", current.ID)
+		return r.db.UpdateArticleValidators(ctx, articleID, updated.ETag, updated.LastModified)
+	}
+
+	// Keep the stored identity and what the submitter provided
+	updated.ID = current.ID
+	updated.CanonicalURL = current.CanonicalURL
+	updated.Source = current.Source
+	updated.Metadata.Tags = current.Metadata.Tags
+	updated.Metadata.Custom = current.Metadata.Custom
+
+	metadata := map[string]interface{}{
+		"article_id":   current.ID,
+		"source":       current.Source,
+		"refreshed_at": updated.FetchedAt,
+	}
+	chunks, err := r.ragClient.ReindexArticle(ctx, updated, metadata)
+	if err != nil {
+		return err
+	}
+
+	version, err := r.db.UpdateArticleContent(ctx, updated, chunks)
+	if err != nil {
+		return err
+	}
+
+	slog.Info("Article content changed and re-indexed",
+		"article_id", current.ID,
+		"version", version,
+		"chunks", chunks)
+	return nil
+}
@@ -0,0 +1,241 @@
+package services
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+
+	"article-chat-system/server/internal/database"
+	"article-chat-system/server/internal/errors"
+	"article-chat-system/server/internal/models"
+	"article-chat-system/server/internal/workers"
+	"github.com/google/uuid"
+)
+
+const (
+	// reindexTimeout bounds re-ingesting one article
+	reindexTimeout = 2 * time.Minute
+	// reindexPageSize is the number of articles loaded and submitted at a time
+	reindexPageSize = 100
+	// maxReindexErrors caps the failures listed on a job
+	maxReindexErrors = 50
+	// maxReindexJobs is the number of jobs whose progress is kept
+	maxReindexJobs = 20
+)
+
+// ArticleReindexer re-runs ingestion over a filtered set of articles in the background
+// Articles are read page by page and re-ingested on the article pool. One job runs at a time;
+// the progress of recent jobs is kept in memory, so it is lost on restart.
+type ArticleReindexer struct {
+	ingester *ArticleIngester
+	db       *database.DB
+	pools    *workers.PoolManager
+	ctx      context.Context // Cancelled on shutdown, stopping running jobs
+
+	mu   sync.Mutex
+	jobs []*models.ReindexJob // Oldest first
+}
+
+// NewArticleReindexer creates a reindexer whose jobs stop when ctx is cancelled
+func NewArticleReindexer(ctx context.Context, ingester *ArticleIngester, db *database.DB, pools *workers.PoolManager) *ArticleReindexer {
+	return &ArticleReindexer{
+		ingester: ingester,
+		db:       db,
+		pools:    pools,
+		ctx:      ctx,
+	}
+}
+
+// Start starts a job re-ingesting the articles matching filter and returns its initial state
+// Fails while another job is running.
+func (r *ArticleReindexer) Start(ctx context.Context, filter *models.ArticleFilter) (models.ReindexJob, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, job := range r.jobs {
+		if job.Status == models.ReindexJobRunning {
+			return models.ReindexJob{}, errors.NewWithDetails(errors.ErrValidationFailed, "A reindex job is already running", map[string]string{
+				"job_id": job.ID,
+			})
+		}
+	}
+
+	total, err := r.db.CountArticles(ctx, filter)
+	if err != nil {
+		return models.ReindexJob{}, err
+	}
+
+	job := &models.ReindexJob{
+		ID:        uuid.New().String(),
+		Status:    models.ReindexJobRunning,
+		Total:     total,
+		Errors:    []models.ReindexError{},
+		StartedAt: time.Now(),
+	}
+	// Only the newest job can be running, so the oldest ones are finished
+	r.jobs = append(r.jobs, job)
+	if len(r.jobs) > maxReindexJobs {
+		r.jobs = r.jobs[len(r.jobs)-maxReindexJobs:]
+	}
+
+	go r.run(job, *filter)
+
+	slog.Info("Reindex job started", "job_id", job.ID, "articles", total)
+	return snapshotJob(job), nil
+}
+
+// Job returns the current progress of a job
+func (r *ArticleReindexer) Job(id string) (models.ReindexJob, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, job := range r.jobs {
+		if job.ID == id {
+			return snapshotJob(job), true
+		}
+	}
+	return models.ReindexJob{}, false
+}
+
+// run pages through the matching articles, waiting for each page before loading the next
+func (r *ArticleReindexer) run(job *models.ReindexJob, filter models.ArticleFilter) {
+	filter.IncludeContent = true
+	filter.Limit = reindexPageSize
+	filter.Cursor = ""
+
+	status := models.ReindexJobCompleted
+	for r.ctx.Err() == nil {
+		articles, cursors, err := r.db.ListArticles(r.ctx, &filter)
+		if err != nil {
+			slog.Error("Reindex job failed to load articles", "error", err, "job_id", job.ID)
+			status = models.ReindexJobFailed
+			break
+		}
+
+		var wg sync.WaitGroup
+		for i := range articles {
+			if r.ctx.Err() != nil {
+				break
+			}
+
+			article := &articles[i]
+			wg.Add(1)
+			accepted := r.pools.SubmitArticleTask(workers.ArticleTask{
+				Owner:    "reindex",
+				Priority: workers.PriorityBackground,
+				Label:    article.URL,
+				Ctx:      r.ctx,
+				Run: func() {
+					defer wg.Done()
+
+					ctx, cancel := context.WithTimeout(r.ctx, reindexTimeout)
+					defer cancel()
+
+					target, err := r.claimFailed(ctx, article)
+					if err == nil && target == nil {
+						r.skip(job)
+						return
+					}
+					if err == nil {
+						_, err = r.ingester.Reingest(ctx, target)
+					}
+					r.record(job, article.ID, err)
+				},
+			})
+			if !accepted {
+				wg.Done()
+			}
+		}
+		waitUnlessCancelled(r.ctx, &wg)
+
+		if cursors.Next == "" {
+			break
+		}
+		filter.Cursor = cursors.Next
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	finishedAt := time.Now()
+	job.FinishedAt = &finishedAt
+	job.Status = status
+	if r.ctx.Err() != nil {
+		job.Status = models.ReindexJobCancelled
+	}
+
+	slog.Info("Reindex job finished", "job_id", job.ID, "status", job.Status,
+		"processed", job.Processed, "succeeded", job.Succeeded, "failed", job.Failed, "skipped", job.Skipped)
+}
+
+// claimFailed leases a failed article the way ArticleRetrier and manual retries do, so the
+// three never re-ingest it at the same time. Other articles are returned unchanged; nil means
+// a retry already holds the article or it is no longer failed.
+func (r *ArticleReindexer) claimFailed(ctx context.Context, article *models.Article) (*models.Article, error) {
+	if article.Status != models.ArticleStatusFailed {
+		return article, nil
+	}
+
+	articleID, err := uuid.Parse(article.ID)
+	if err != nil {
+		return nil, errors.Wrap(err, errors.ErrInvalidDataType)
+	}
+
+	return r.db.ClaimArticleForRetry(ctx, articleID, retryLease)
+}
+
+// waitUnlessCancelled waits for wg, or until ctx ends: tasks skipped or dropped by the
+// article queue at shutdown never run, so they never call Done
+func waitUnlessCancelled(ctx context.Context, wg *sync.WaitGroup) {
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-ctx.Done():
+	}
+}
+
+// record counts one re-ingested article
+func (r *ArticleReindexer) record(job *models.ReindexJob, articleID string, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	job.Processed++
+	if err == nil {
+		job.Succeeded++
+		return
+	}
+
+	job.Failed++
+	if len(job.Errors) < maxReindexErrors {
+		message := err.Error()
+		// Ingestion errors carry the underlying cause in their details
+		if appErr, ok := errors.IsAppError(err); ok {
+			if details, ok := appErr.Details.(map[string]string); ok && details["reason"] != "" {
+				message = details["stage"] + ": " + details["reason"]
+			}
+		}
+		job.Errors = append(job.Errors, models.ReindexError{ArticleID: articleID, Error: message})
+	}
+}
+
+// skip counts an article left to the retry that holds it
+func (r *ArticleReindexer) skip(job *models.ReindexJob) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	job.Processed++
+	job.Skipped++
+}
+
+// snapshotJob copies a job so it can be read without the lock
+func snapshotJob(job *models.ReindexJob) models.ReindexJob {
+	snapshot := *job
+	snapshot.Errors = append([]models.ReindexError{}, job.Errors...)
+	return snapshot
+}
@@ -0,0 +1,93 @@
+package services
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"article-chat-system/server/internal/config"
+	"article-chat-system/server/internal/database"
+	"article-chat-system/server/internal/workers"
+)
+
+const (
+	// retryTimeout bounds one retry of a failed article
+	retryTimeout = 2 * time.Minute
+	// retryLease is how long a claimed article stays claimed if its retry never finishes
+	retryLease = 15 * 60
+)
+
+// ArticleRetrier retries failed ingestions once their backoff has elapsed
+// The ingester schedules each retry when recording the failure, doubling the delay with every
+// failed attempt; retries stop after the configured number of attempts.
+type ArticleRetrier struct {
+	ingester *ArticleIngester
+	db       *database.DB
+	pools    *workers.PoolManager
+	config   config.RetryConfig
+}
+
+// NewArticleRetrier creates a retrier; polls run on the general worker pool and retries on the
+// article pool
+func NewArticleRetrier(cfg config.RetryConfig, ingester *ArticleIngester, db *database.DB, pools *workers.PoolManager) *ArticleRetrier {
+	return &ArticleRetrier{
+		ingester: ingester,
+		db:       db,
+		pools:    pools,
+		config:   cfg,
+	}
+}
+
+// Start polls for due retries every poll interval until ctx is cancelled
+func (r *ArticleRetrier) Start(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(time.Duration(r.config.PollInterval) * time.Second)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				r.pools.SubmitTask(func() {
+					r.retryDue(ctx)
+				})
+			}
+		}
+	}()
+}
+
+// retryDue submits one batch of due retries to the article pool
+func (r *ArticleRetrier) retryDue(ctx context.Context) {
+	articles, err := r.db.ClaimArticlesForRetry(ctx, retryLease, r.config.BatchSize)
+	if err != nil {
+		slog.Warn("Failed to load articles due for retry", "error", err)
+		return
+	}
+
+	for i := range articles {
+		if ctx.Err() != nil {
+			return
+		}
+
+		article := &articles[i]
+		r.pools.SubmitArticleTask(workers.ArticleTask{
+			Owner:    "retries",
+			Priority: workers.PriorityBackground,
+			Label:    article.URL,
+			Run: func() {
+				retryCtx, cancel := context.WithTimeout(ctx, retryTimeout)
+				defer cancel()
+
+				response, err := r.ingester.Reingest(retryCtx, article)
+				if err != nil {
+					slog.Warn("Article retry failed", "error", err, "article_id", article.ID, "attempt", article.Attempts+1)
+					return
+				}
+
+				slog.Info("Article retry succeeded", "article_id", response.ID, "attempt", article.Attempts+1,
+					"duplicate", response.Duplicate)
+			},
+		})
+	}
+}
@@ -0,0 +1,69 @@
+package services
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+	"time"
+
+	"article-chat-system/server/internal/database"
+	"article-chat-system/server/internal/workers"
+	"github.com/google/uuid"
+)
+
+// maxGeneratedTitleLength caps generated titles, in characters
+const maxGeneratedTitleLength = 80
+
+// TitleGenerator titles new conversations in the background once their first answer is stored
+// The fallback title cut from the first message stays in place until the generated one arrives,
+// and stays for good if generation fails or the user renames the conversation first.
+type TitleGenerator struct {
+	ragClient *RAGClient
+	db        *database.DB
+	pools     *workers.PoolManager
+}
+
+// NewTitleGenerator creates a title generator that runs jobs on the general worker pool
+func NewTitleGenerator(ragClient *RAGClient, db *database.DB, pools *workers.PoolManager) *TitleGenerator {
+	return &TitleGenerator{
+		ragClient: ragClient,
+		db:        db,
+		pools:     pools,
+	}
+}
+
+// Schedule queues title generation for a conversation from its first question and answer
+func (g *TitleGenerator) Schedule(conversationID uuid.UUID, question, answer string) {
+	g.pools.SubmitTask(func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+
+		title, err := g.ragClient.GenerateTitle(ctx, question, answer)
+		if err != nil {
+			slog.Warn("Failed to generate conversation title", "error", err, "conversation_id", conversationID)
+			return
+		}
+
+		title = cleanGeneratedTitle(title)
+		if title == "" {
+			slog.Debug("RAG service returned an empty title", "conversation_id", conversationID)
+			return
+		}
+
+		// Not a manual update, so a title the user set in the meantime is kept
+		conv, err := g.db.UpdateConversation(ctx, conversationID, title, false)
+		if err != nil {
+			slog.Warn("Failed to store generated title", "error", err, "conversation_id", conversationID)
+			return
+		}
+
+		slog.Debug("Conversation title generated", "conversation_id", conversationID, "title", conv.Title)
+	})
+}
+
+// cleanGeneratedTitle collapses whitespace, strips wrapping quotes and caps the length
+func cleanGeneratedTitle(title string) string {
+	title = strings.Join(strings.Fields(title), " ")
+	title = strings.Trim(title, "\"'`“”‘’ ")
+	return database.TruncateTitle(title, maxGeneratedTitleLength)
+}
@@ -2,6 +2,7 @@ package workers
 
 import (
 	"log/slog"
+	"sync"
 	"time"
 
 	"github.com/alitto/pond"
@@ -10,6 +11,10 @@ import (
 type PoolManager struct {
 	ArticleProcessor *pond.WorkerPool
 	GeneralPool      *pond.WorkerPool
+	ArticleQueue     *ArticleQueue
+
+	articleSlots chan struct{} // One per article worker, so tasks wait in the queue rather than the pool
+	dispatcherWG sync.WaitGroup
 }
 
 type PoolConfig struct {
@@ -18,7 +23,7 @@ type PoolConfig struct {
 }
 
 func NewPoolManager(config PoolConfig) *PoolManager {
-	return &PoolManager{
+	pm := &PoolManager{
 		ArticleProcessor: pond.New(
 			config.ArticleWorkers,
 			config.ArticleWorkers*2,
@@ -31,11 +36,46 @@ func NewPoolManager(config PoolConfig) *PoolManager {
 			pond.MinWorkers(1),
 			pond.IdleTimeout(30*time.Second),
 		),
+		ArticleQueue: NewArticleQueue(),
+		articleSlots: make(chan struct{}, config.ArticleWorkers),
+	}
+
+	pm.dispatcherWG.Add(1)
+	go pm.dispatchArticleTasks()
+
+	return pm
+}
+
+// SubmitArticleTask queues a task for the article pool; see ArticleQueue for the ordering
+// It reports false when the queue is stopped, in which case the task never runs.
+func (pm *PoolManager) SubmitArticleTask(task ArticleTask) bool {
+	if !pm.ArticleQueue.Push(task) {
+		slog.Warn("Article queue stopped, dropping task", "owner", task.Owner, "label", task.Label)
+		return false
 	}
+	return true
 }
 
-func (pm *PoolManager) SubmitArticleTask(task func()) {
-	pm.ArticleProcessor.Submit(task)
+// dispatchArticleTasks hands queued tasks to the article pool as workers free up
+func (pm *PoolManager) dispatchArticleTasks() {
+	defer pm.dispatcherWG.Done()
+
+	for {
+		pm.articleSlots <- struct{}{}
+
+		task, ok := pm.ArticleQueue.Pop()
+		if !ok {
+			return
+		}
+
+		pm.ArticleProcessor.Submit(func() {
+			defer func() {
+				pm.ArticleQueue.Done()
+				<-pm.articleSlots
+			}()
+			task.Run()
+		})
+	}
 }
 
 func (pm *PoolManager) SubmitTask(task func()) {
@@ -44,6 +84,7 @@ func (pm *PoolManager) SubmitTask(task func()) {
 
 func (pm *PoolManager) GetStats() map[string]interface{} {
 	return map[string]interface{}{
+		"article_queue": pm.ArticleQueue.Stats(),
 		"article_pool": map[string]interface{}{
 			"running_workers":  pm.ArticleProcessor.RunningWorkers(),
 			"idle_workers":     pm.ArticleProcessor.IdleWorkers(),
@@ -66,6 +107,11 @@ func (pm *PoolManager) GetStats() map[string]interface{} {
 func (pm *PoolManager) Shutdown() {
 	slog.Info("Shutting down worker pools...")
 
+	if dropped := pm.ArticleQueue.Stop(); dropped > 0 {
+		slog.Warn("Dropped queued article tasks", "count", dropped)
+	}
+	pm.dispatcherWG.Wait()
+
 	pm.ArticleProcessor.StopAndWait()
 	slog.Info("Article processor pool stopped")
 
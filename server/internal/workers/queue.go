@@ -0,0 +1,266 @@
+package workers
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Article task priorities. Submitters choose from MinArticlePriority to
+// MaxArticlePriority; background work runs below every submitter.
+const (
+	PriorityBackground = -1
+	MinArticlePriority = 0
+	MaxArticlePriority = 10
+)
+
+// ArticleTask is one unit of work for the article pool
+type ArticleTask struct {
+	Owner    string          // Submitter the task is charged to, e.g. a user ID or "feeds"
+	Priority int             // Higher runs first within a fairness round
+	Label    string          // Shown in the queue listing, e.g. the article URL
+	Ctx      context.Context // Optional; the task is skipped if it is done before a worker is free
+	Run      func()
+}
+
+// QueuedTask describes a task waiting in the article queue
+type QueuedTask struct {
+	Owner      string    `json:"owner"`
+	Priority   int       `json:"priority"`
+	Label      string    `json:"label,omitempty"`
+	EnqueuedAt time.Time `json:"enqueued_at"`
+	WaitingFor float64   `json:"waiting_seconds"`
+}
+
+// QueueStats summarizes the article queue
+type QueueStats struct {
+	Depth           int            `json:"depth"`
+	Running         int            `json:"running"`
+	Owners          int            `json:"owners"`
+	OldestWait      float64        `json:"oldest_wait_seconds"`
+	AverageWait     float64        `json:"average_wait_seconds"` // Over every dispatched task
+	Dispatched      int64          `json:"dispatched"`
+	Skipped         int64          `json:"skipped"` // Tasks whose context ended while queued
+	DepthByPriority map[int]int    `json:"depth_by_priority"`
+	DepthByOwner    map[string]int `json:"depth_by_owner"`
+}
+
+type queuedTask struct {
+	task       ArticleTask
+	seq        uint64
+	enqueuedAt time.Time
+}
+
+// ownerQueue holds one submitter's tasks, highest priority first, then FIFO
+// An owner stays in the queue after its tasks run out until the next round starts,
+// so submitting one task at a time can't earn a second turn in the same round.
+type ownerQueue struct {
+	tasks       []*queuedTask
+	servedRound uint64 // One past the last round this owner was served in
+}
+
+func (o *ownerQueue) push(t *queuedTask) {
+	i := sort.Search(len(o.tasks), func(i int) bool {
+		return o.tasks[i].task.Priority < t.task.Priority
+	})
+	o.tasks = append(o.tasks, nil)
+	copy(o.tasks[i+1:], o.tasks[i:])
+	o.tasks[i] = t
+}
+
+// ArticleQueue orders article tasks by priority with per-owner fairness.
+// Dispatch runs in rounds: every owner with pending work gets one task per
+// round, so a bulk submitter cannot starve anyone else. Within a round,
+// owners whose next task has the higher priority go first, and ties go to
+// the task that was queued earliest.
+type ArticleQueue struct {
+	mu      sync.Mutex
+	cond    *sync.Cond
+	owners  map[string]*ownerQueue
+	round   uint64
+	seq     uint64
+	depth   int
+	running int
+	stopped bool
+
+	dispatched int64
+	skipped    int64
+	totalWait  time.Duration
+}
+
+func NewArticleQueue() *ArticleQueue {
+	q := &ArticleQueue{owners: make(map[string]*ownerQueue)}
+	q.cond = sync.NewCond(&q.mu)
+	return q
+}
+
+// Push queues a task. It reports false once the queue is stopped.
+func (q *ArticleQueue) Push(task ArticleTask) bool {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if q.stopped {
+		return false
+	}
+
+	owner, ok := q.owners[task.Owner]
+	if !ok {
+		owner = &ownerQueue{}
+		q.owners[task.Owner] = owner
+	}
+
+	q.seq++
+	owner.push(&queuedTask{task: task, seq: q.seq, enqueuedAt: time.Now()})
+	q.depth++
+	q.cond.Signal()
+	return true
+}
+
+// Pop blocks until a task is due and returns it, or returns false once the
+// queue is stopped. Tasks whose context has ended are dropped on the way.
+func (q *ArticleQueue) Pop() (ArticleTask, bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	for {
+		for q.depth == 0 && !q.stopped {
+			q.cond.Wait()
+		}
+		if q.stopped {
+			return ArticleTask{}, false
+		}
+
+		next := q.next()
+		if next.task.Ctx != nil && next.task.Ctx.Err() != nil {
+			q.skipped++
+			continue
+		}
+
+		q.dispatched++
+		q.running++
+		q.totalWait += time.Since(next.enqueuedAt)
+		return next.task, true
+	}
+}
+
+// next removes the task the current round serves next. Callers hold mu and
+// ensure the queue is not empty.
+func (q *ArticleQueue) next() *queuedTask {
+	var best *ownerQueue
+	for _, owner := range q.owners {
+		if len(owner.tasks) == 0 || owner.servedRound > q.round {
+			continue
+		}
+		if best == nil || ahead(owner.tasks[0], best.tasks[0]) {
+			best = owner
+		}
+	}
+
+	// Everyone with work has had a turn; start the next round and forget idle owners
+	if best == nil {
+		q.round++
+		for name, owner := range q.owners {
+			if len(owner.tasks) == 0 {
+				delete(q.owners, name)
+			}
+		}
+		return q.next()
+	}
+
+	head := best.tasks[0]
+	best.tasks = best.tasks[1:]
+	best.servedRound = q.round + 1
+	q.depth--
+	return head
+}
+
+func ahead(a, b *queuedTask) bool {
+	if a.task.Priority != b.task.Priority {
+		return a.task.Priority > b.task.Priority
+	}
+	return a.seq < b.seq
+}
+
+// Done records that a dispatched task has finished
+func (q *ArticleQueue) Done() {
+	q.mu.Lock()
+	q.running--
+	q.mu.Unlock()
+}
+
+// Stop wakes any waiting Pop and reports how many tasks were left queued
+func (q *ArticleQueue) Stop() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	q.stopped = true
+	dropped := q.depth
+	q.owners = make(map[string]*ownerQueue)
+	q.depth = 0
+	q.cond.Broadcast()
+	return dropped
+}
+
+// Stats returns depth and wait metrics for the queue
+func (q *ArticleQueue) Stats() QueueStats {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	now := time.Now()
+	stats := QueueStats{
+		Depth:           q.depth,
+		Running:         q.running,
+		Dispatched:      q.dispatched,
+		Skipped:         q.skipped,
+		DepthByPriority: make(map[int]int),
+		DepthByOwner:    make(map[string]int, len(q.owners)),
+	}
+	if q.dispatched > 0 {
+		stats.AverageWait = (q.totalWait / time.Duration(q.dispatched)).Seconds()
+	}
+
+	for name, owner := range q.owners {
+		if len(owner.tasks) == 0 {
+			continue
+		}
+		stats.Owners++
+		stats.DepthByOwner[name] = len(owner.tasks)
+		for _, t := range owner.tasks {
+			stats.DepthByPriority[t.task.Priority]++
+			if wait := now.Sub(t.enqueuedAt).Seconds(); wait > stats.OldestWait {
+				stats.OldestWait = wait
+			}
+		}
+	}
+
+	return stats
+}
+
+// Snapshot lists up to limit queued tasks, highest priority first, then oldest
+func (q *ArticleQueue) Snapshot(limit int) []QueuedTask {
+	q.mu.Lock()
+	queued := make([]*queuedTask, 0, q.depth)
+	for _, owner := range q.owners {
+		queued = append(queued, owner.tasks...)
+	}
+	q.mu.Unlock()
+
+	sort.Slice(queued, func(i, j int) bool { return ahead(queued[i], queued[j]) })
+	if len(queued) > limit {
+		queued = queued[:limit]
+	}
+
+	now := time.Now()
+	tasks := make([]QueuedTask, len(queued))
+	for i, t := range queued {
+		tasks[i] = QueuedTask{
+			Owner:      t.task.Owner,
+			Priority:   t.task.Priority,
+			Label:      t.task.Label,
+			EnqueuedAt: t.enqueuedAt,
+			WaitingFor: now.Sub(t.enqueuedAt).Seconds(),
+		}
+	}
+	return tasks
+}
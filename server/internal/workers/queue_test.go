@@ -0,0 +1,143 @@
+package workers
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func task(owner string, priority int, label string) ArticleTask {
+	return ArticleTask{Owner: owner, Priority: priority, Label: label, Run: func() {}}
+}
+
+// popLabels pops n tasks and returns their labels in dispatch order
+func popLabels(t *testing.T, q *ArticleQueue, n int) []string {
+	t.Helper()
+	labels := make([]string, 0, n)
+	for i := 0; i < n; i++ {
+		next, ok := q.Pop()
+		if !ok {
+			t.Fatalf("Pop %d returned false", i)
+		}
+		q.Done()
+		labels = append(labels, next.Label)
+	}
+	return labels
+}
+
+func assertOrder(t *testing.T, got []string, want ...string) {
+	t.Helper()
+	if len(got) != len(want) {
+		t.Fatalf("dispatched %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("dispatched %v, want %v", got, want)
+		}
+	}
+}
+
+func TestArticleQueuePriorityOrder(t *testing.T) {
+	q := NewArticleQueue()
+	q.Push(task("alice", 1, "low"))
+	q.Push(task("alice", 5, "high"))
+	q.Push(task("alice", 3, "mid"))
+	q.Push(task("alice", 5, "high-later"))
+	q.Push(task("alice", PriorityBackground, "background"))
+
+	assertOrder(t, popLabels(t, q, 5), "high", "high-later", "mid", "low", "background")
+}
+
+func TestArticleQueueOwnerRounds(t *testing.T) {
+	q := NewArticleQueue()
+	for _, label := range []string{"bulk-1", "bulk-2", "bulk-3"} {
+		q.Push(task("bulk", MaxArticlePriority, label))
+	}
+	q.Push(task("alice", MinArticlePriority, "alice-1"))
+	q.Push(task("feeds", PriorityBackground, "feed-1"))
+	q.Push(task("feeds", PriorityBackground, "feed-2"))
+
+	// Every owner gets one task per round; priority only orders owners within a round
+	assertOrder(t, popLabels(t, q, 6), "bulk-1", "alice-1", "feed-1", "bulk-2", "feed-2", "bulk-3")
+}
+
+func TestArticleQueueDrainedOwnerWaitsForNextRound(t *testing.T) {
+	q := NewArticleQueue()
+	q.Push(task("alice", 0, "alice-1"))
+	q.Push(task("bob", 0, "bob-1"))
+	q.Push(task("bob", 0, "bob-2"))
+
+	assertOrder(t, popLabels(t, q, 1), "alice-1")
+
+	// Alice was already served this round, so resubmitting doesn't jump ahead of bob
+	q.Push(task("alice", MaxArticlePriority, "alice-2"))
+	assertOrder(t, popLabels(t, q, 3), "bob-1", "alice-2", "bob-2")
+}
+
+func TestArticleQueueSkipsEndedContexts(t *testing.T) {
+	q := NewArticleQueue()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	timedOut := task("alice", 5, "timed-out")
+	timedOut.Ctx = ctx
+	live := task("alice", 0, "live")
+	live.Ctx = context.Background()
+
+	q.Push(timedOut)
+	q.Push(live)
+
+	assertOrder(t, popLabels(t, q, 1), "live")
+
+	stats := q.Stats()
+	if stats.Skipped != 1 || stats.Dispatched != 1 || stats.Depth != 0 {
+		t.Errorf("stats = %+v, want 1 skipped, 1 dispatched and an empty queue", stats)
+	}
+}
+
+func TestArticleQueueStop(t *testing.T) {
+	q := NewArticleQueue()
+
+	popped := make(chan bool)
+	go func() {
+		_, ok := q.Pop()
+		popped <- ok
+	}()
+
+	// Give Pop time to block on the empty queue
+	time.Sleep(10 * time.Millisecond)
+
+	if dropped := q.Stop(); dropped != 0 {
+		t.Errorf("Stop() = %d, want 0", dropped)
+	}
+
+	select {
+	case ok := <-popped:
+		if ok {
+			t.Error("Pop after Stop returned a task")
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Pop did not return after Stop")
+	}
+
+	if q.Push(task("alice", 0, "late")) {
+		t.Error("Push after Stop = true, want false")
+	}
+}
+
+func TestArticleQueueStopReportsDropped(t *testing.T) {
+	q := NewArticleQueue()
+	q.Push(task("alice", 0, "a"))
+	q.Push(task("bob", 0, "b"))
+
+	if dropped := q.Stop(); dropped != 2 {
+		t.Errorf("Stop() = %d, want 2", dropped)
+	}
+	if _, ok := q.Pop(); ok {
+		t.Error("Pop after Stop returned a task")
+	}
+	if depth := q.Stats().Depth; depth != 0 {
+		t.Errorf("depth after Stop = %d, want 0", depth)
+	}
+}